@@ -0,0 +1,376 @@
+// Package algo provides execution algorithms that split a parent order into
+// child orders: TWAP, participation of volume and a client-side iceberg.
+//
+// Algorithms place children with PlaceOrder2 and follow their fills on the
+// order stream, so the order (and for POV the trade) table must be subscribed.
+package algo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	bitmex "github.com/sumorf/bitmex-api"
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// Client is the part of *bitmex.BitMEX used by the algorithms
+type Client interface {
+	PlaceOrder2(side bitmex.Side, ordType bitmex.OrdType, stopPx bitmex.Price, price bitmex.Price, orderQty int64,
+		displayQty int64, timeInForce bitmex.TimeInForce, execInst bitmex.ExecInst, symbol string, clOrdID string, text string) (swagger.Order, error)
+	CancelOrder(oid string) (swagger.Order, error)
+	WatchOrders(h func(orders []*swagger.Order, action string)) (remove func())
+	WatchTrades(h func(trades []*swagger.Trade, action string)) (remove func())
+}
+
+var _ Client = (*bitmex.BitMEX)(nil)
+
+// State of an algorithm
+type State int
+
+const (
+	Running State = iota
+	Paused
+	Canceled
+	Done
+	Failed
+	// Expired ends an algorithm whose window closed before the parent
+	// quantity filled; Progress.Remaining is left unexecuted
+	Expired
+)
+
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "Running"
+	case Paused:
+		return "Paused"
+	case Canceled:
+		return "Canceled"
+	case Done:
+		return "Done"
+	case Failed:
+		return "Failed"
+	case Expired:
+		return "Expired"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Params are shared by all algorithms
+type Params struct {
+	Symbol   string
+	Side     bitmex.Side
+	Quantity int64 // parent quantity
+
+	// LimitPrice caps the child prices: buys never above, sells never below.
+	// Children are market orders when zero.
+	LimitPrice float64
+
+	// OnProgress is called after every fill and state change
+	OnProgress func(Progress)
+}
+
+// Progress reports the execution of the parent order
+type Progress struct {
+	State     State
+	Filled    int64
+	Remaining int64
+	AvgPrice  float64 // volume weighted average fill price
+	Err       error
+}
+
+// Algo is the control interface of a running algorithm
+type Algo interface {
+	Pause() error
+	Resume() error
+	Cancel() error
+	Progress() Progress
+	Done() <-chan struct{}
+}
+
+var ErrFinished = errors.New("algo: already finished")
+
+// base keeps the children of an algorithm and their fills. The algorithms
+// embed it and implement step, which is called on every wake up.
+type base struct {
+	client Client
+	params Params
+	step   func()
+
+	m         sync.Mutex
+	state     State
+	children  map[string]*swagger.Order // key: OrderID
+	err       error
+	pausedAt  time.Time
+	pausedFor time.Duration // total time spent paused
+
+	remove []func()
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+func newBase(client Client, params Params) (*base, error) {
+	if params.Quantity <= 0 {
+		return nil, errors.New("algo: quantity must be positive")
+	}
+	if !params.Side.Valid() {
+		return nil, errors.New("algo: side must be Buy or Sell")
+	}
+	a := &base{
+		client:   client,
+		params:   params,
+		children: make(map[string]*swagger.Order),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	a.remove = append(a.remove, client.WatchOrders(a.onOrders))
+	return a, nil
+}
+
+func (a *base) onOrders(orders []*swagger.Order, action string) {
+	changed := false
+	a.m.Lock()
+	for _, o := range orders {
+		if c, ok := a.children[o.OrderID]; ok && !o.Timestamp.Before(c.Timestamp) {
+			*c = *o
+			changed = true
+		}
+	}
+	a.m.Unlock()
+	if changed {
+		a.notify()
+		a.report()
+	}
+}
+
+func (a *base) notify() {
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause stops placing children and cancels the working ones
+func (a *base) Pause() error {
+	a.m.Lock()
+	if a.state != Running {
+		a.m.Unlock()
+		return ErrFinished
+	}
+	a.state = Paused
+	a.pausedAt = time.Now()
+	a.m.Unlock()
+	err := a.cancelChildren()
+	a.report()
+	return err
+}
+
+// Resume continues a paused algorithm
+func (a *base) Resume() error {
+	a.m.Lock()
+	if a.state != Paused {
+		a.m.Unlock()
+		return ErrFinished
+	}
+	a.state = Running
+	a.pausedFor += time.Since(a.pausedAt)
+	a.m.Unlock()
+	a.notify()
+	a.report()
+	return nil
+}
+
+// Cancel cancels the working children and stops the algorithm
+func (a *base) Cancel() error {
+	err := a.cancelChildren()
+	a.finish(Canceled, nil)
+	return err
+}
+
+// Done is closed when the algorithm is finished
+func (a *base) Done() <-chan struct{} {
+	return a.done
+}
+
+// Progress returns the current execution state
+func (a *base) Progress() Progress {
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.progress()
+}
+
+// progress must be called with a.m held
+func (a *base) progress() Progress {
+	var filled int64
+	var value float64
+	for _, c := range a.children {
+		filled += c.CumQty
+		value += float64(c.CumQty) * c.AvgPx
+	}
+	p := Progress{
+		State:     a.state,
+		Filled:    filled,
+		Remaining: a.params.Quantity - filled,
+		Err:       a.err,
+	}
+	if filled > 0 {
+		p.AvgPrice = value / float64(filled)
+	}
+	return p
+}
+
+func (a *base) report() {
+	if a.params.OnProgress != nil {
+		a.params.OnProgress(a.Progress())
+	}
+}
+
+func (a *base) running() bool {
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.state == Running
+}
+
+// inflight returns the quantity still working in children
+func (a *base) inflight() (qty int64) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	for _, c := range a.children {
+		if working(c) {
+			qty += c.LeavesQty
+		}
+	}
+	return
+}
+
+// place sends a child order and returns it. price 0 falls back to
+// LimitPrice, and to a market order when no limit is set.
+func (a *base) place(qty int64, price float64, timeInForce bitmex.TimeInForce, execInst bitmex.ExecInst) (swagger.Order, error) {
+	ordType := bitmex.OrdTypeMarket
+	if price <= 0 {
+		price = a.params.LimitPrice
+	}
+	if price > 0 {
+		ordType = bitmex.OrdTypeLimit
+	} else {
+		timeInForce = ""
+	}
+	order, err := a.client.PlaceOrder2(a.params.Side, ordType, bitmex.Price{}, bitmex.PriceOf(price), qty, -1,
+		timeInForce, execInst, a.params.Symbol, "", "algo child")
+	if err != nil {
+		a.fail(err)
+		return order, err
+	}
+
+	a.m.Lock()
+	if c, ok := a.children[order.OrderID]; !ok || !order.Timestamp.Before(c.Timestamp) {
+		a.children[order.OrderID] = &order
+	}
+	a.m.Unlock()
+	// the response may already carry fills
+	a.notify()
+	a.report()
+	return order, nil
+}
+
+// child returns the latest state of a child order
+func (a *base) child(orderID string) (o swagger.Order, ok bool) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if c, found := a.children[orderID]; found {
+		return *c, true
+	}
+	return
+}
+
+func (a *base) cancelChildren() (err error) {
+	a.m.Lock()
+	var ids []string
+	for id, c := range a.children {
+		if working(c) {
+			ids = append(ids, id)
+		}
+	}
+	a.m.Unlock()
+
+	for _, id := range ids {
+		order, e := a.client.CancelOrder(id)
+		if e != nil {
+			err = e
+			continue
+		}
+		a.m.Lock()
+		if c, ok := a.children[id]; ok && !order.Timestamp.Before(c.Timestamp) {
+			*c = order
+		}
+		a.m.Unlock()
+	}
+	return
+}
+
+func (a *base) fail(err error) {
+	a.m.Lock()
+	a.err = err
+	a.m.Unlock()
+	a.cancelChildren()
+	a.finish(Failed, err)
+}
+
+func (a *base) finish(state State, err error) {
+	a.m.Lock()
+	if a.state == Canceled || a.state == Done || a.state == Failed || a.state == Expired {
+		a.m.Unlock()
+		return
+	}
+	a.state = state
+	if err != nil {
+		a.err = err
+	}
+	a.m.Unlock()
+
+	for _, remove := range a.remove {
+		remove()
+	}
+	close(a.done)
+	a.report()
+}
+
+// active returns the time spent running since start
+func (a *base) active(start time.Time) time.Duration {
+	a.m.Lock()
+	defer a.m.Unlock()
+	return time.Since(start) - a.pausedFor
+}
+
+// run calls step on every wake up and tick until the algorithm finishes.
+// tick may be nil.
+func (a *base) run(tick <-chan time.Time) {
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-a.wake:
+		case <-tick:
+		}
+		if a.running() {
+			a.step()
+		}
+	}
+}
+
+func working(o *swagger.Order) bool {
+	return o.OrdStatus == bitmex.OS_NEW || o.OrdStatus == bitmex.OS_PARTIALLY_FILLED
+}
+
+// capped reports whether price violates the limit price of the side
+func (p *Params) capped(price float64) bool {
+	if p.LimitPrice <= 0 {
+		return false
+	}
+	if p.Side == bitmex.SideBuy {
+		return price > p.LimitPrice
+	}
+	return price < p.LimitPrice
+}
@@ -0,0 +1,310 @@
+package algo
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	bitmex "github.com/sumorf/bitmex-api"
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// fakeClient fills every child it receives up to fillQty (all when zero),
+// or cancels it unfilled when unfilled is set, or when crossing is set for
+// ParticipateDoNotInitiate children
+type fakeClient struct {
+	m        sync.Mutex
+	fillQty  int64
+	unfilled bool
+	crossing bool
+	orders   []swagger.Order
+	onOrder  func([]*swagger.Order, string)
+	onTrade  func([]*swagger.Trade, string)
+	err      error
+}
+
+func (f *fakeClient) PlaceOrder2(side bitmex.Side, ordType bitmex.OrdType, stopPx bitmex.Price, price bitmex.Price, orderQty int64,
+	displayQty int64, timeInForce bitmex.TimeInForce, execInst bitmex.ExecInst, symbol string, clOrdID string, text string) (swagger.Order, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.err != nil {
+		return swagger.Order{}, f.err
+	}
+	filled := orderQty
+	if f.fillQty > 0 && f.fillQty < filled {
+		filled = f.fillQty
+	}
+	postOnly := f.crossing && execInst&bitmex.ExecParticipateDoNotInitiate != 0
+	if f.unfilled || postOnly {
+		filled = 0
+	}
+	o := swagger.Order{
+		OrderID:   strconv.Itoa(len(f.orders)),
+		Symbol:    symbol,
+		Side:      string(side),
+		OrdType:   string(ordType),
+		ExecInst:  execInst.String(),
+		Price:     price.Float64(),
+		OrderQty:  orderQty,
+		CumQty:    filled,
+		LeavesQty: orderQty - filled,
+		AvgPx:     100,
+		OrdStatus: bitmex.OS_FILLED,
+		Timestamp: time.Now(),
+	}
+	if filled < orderQty {
+		o.OrdStatus = bitmex.OS_PARTIALLY_FILLED
+	}
+	if f.unfilled || postOnly {
+		o.OrdStatus, o.LeavesQty = bitmex.OS_CANCELED, 0
+	}
+	if postOnly {
+		o.Text = "Canceled: Order had execInst of ParticipateDoNotInitiate\n" + text
+	}
+	f.orders = append(f.orders, o)
+	return o, nil
+}
+
+func (f *fakeClient) CancelOrder(oid string) (swagger.Order, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	i, _ := strconv.Atoi(oid)
+	o := &f.orders[i]
+	o.OrdStatus = bitmex.OS_CANCELED
+	o.LeavesQty = 0
+	o.Timestamp = time.Now()
+	return *o, nil
+}
+
+func (f *fakeClient) WatchOrders(h func([]*swagger.Order, string)) func() {
+	f.onOrder = h
+	return func() {}
+}
+
+func (f *fakeClient) WatchTrades(h func([]*swagger.Trade, string)) func() {
+	f.onTrade = h
+	return func() {}
+}
+
+func (f *fakeClient) placed() []swagger.Order {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return append([]swagger.Order(nil), f.orders...)
+}
+
+func wait(t *testing.T, a Algo) {
+	select {
+	case <-a.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatalf("not done: %+v", a.Progress())
+	}
+}
+
+func TestTWAP(t *testing.T) {
+	f := &fakeClient{}
+	a, err := NewTWAP(f, TWAPParams{
+		Params:   Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 100, LimitPrice: 5000},
+		Duration: 40 * time.Millisecond,
+		Slices:   4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(t, a)
+
+	p := a.Progress()
+	if p.State != Done || p.Filled != 100 || p.AvgPrice != 100 {
+		t.Errorf("progress %+v", p)
+	}
+	orders := f.placed()
+	if len(orders) != 4 {
+		t.Fatalf("children %v", len(orders))
+	}
+	for _, o := range orders {
+		if o.OrderQty != 25 || o.OrdType != bitmex.ORD_TYPE_LIMIT || o.Price != 5000 {
+			t.Errorf("child %#v", o)
+		}
+	}
+}
+
+func TestTWAP_Expired(t *testing.T) {
+	f := &fakeClient{unfilled: true}
+	a, err := NewTWAP(f, TWAPParams{
+		Params:   Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 100, LimitPrice: 5000},
+		Duration: 40 * time.Millisecond,
+		Slices:   4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(t, a)
+
+	if p := a.Progress(); p.State != Expired || p.Filled != 0 || p.Remaining != 100 {
+		t.Errorf("progress %+v", p)
+	}
+	n := len(f.placed())
+	if n == 0 || n > 4 {
+		t.Errorf("%v children", n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if len(f.placed()) != n {
+		t.Error("children placed after the window")
+	}
+}
+
+func TestPOV(t *testing.T) {
+	f := &fakeClient{}
+	a, err := NewPOV(f, POVParams{
+		Params: Params{Symbol: "XBTUSD", Side: bitmex.SIDE_SELL, Quantity: 30, LimitPrice: 5000},
+		Rate:   0.1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.onTrade([]*swagger.Trade{
+		{Symbol: "XBTUSD", Size: 100, Price: 5001},
+		{Symbol: "XBTUSD", Size: 1000, Price: 4000}, // below the limit
+		{Symbol: "ETHUSD", Size: 1000, Price: 5001},
+	}, "insert")
+	waitFor(t, func() bool { return a.Progress().Filled == 10 })
+
+	f.onTrade([]*swagger.Trade{{Symbol: "XBTUSD", Size: 1000, Price: 5001}}, "insert")
+	wait(t, a)
+	if p := a.Progress(); p.State != Done || p.Filled != 30 {
+		t.Errorf("progress %+v", p)
+	}
+}
+
+func TestPOV_Paused(t *testing.T) {
+	f := &fakeClient{}
+	a, err := NewPOV(f, POVParams{
+		Params: Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 30},
+		Rate:   0.1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	f.onTrade([]*swagger.Trade{{Symbol: "XBTUSD", Size: 1000, Price: 5000}}, "insert")
+	a.Resume()
+	f.onTrade([]*swagger.Trade{{Symbol: "XBTUSD", Size: 100, Price: 5000}}, "insert")
+	waitFor(t, func() bool { return a.Progress().Filled == 10 })
+	time.Sleep(20 * time.Millisecond)
+	if p := a.Progress(); p.Filled != 10 {
+		t.Errorf("progress %+v", p)
+	}
+	a.Cancel()
+}
+
+func TestIceberg(t *testing.T) {
+	f := &fakeClient{fillQty: 5}
+	a, err := NewIceberg(f, IcebergParams{
+		Params:     Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 25},
+		Price:      4000,
+		DisplayQty: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// first child partially filled: no second child while it works
+	waitFor(t, func() bool { return len(f.placed()) == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if n := len(f.placed()); n != 1 {
+		t.Fatalf("children %v", n)
+	}
+
+	f.m.Lock()
+	f.fillQty = 0
+	o := &f.orders[0]
+	o.CumQty, o.LeavesQty, o.OrdStatus, o.Timestamp = 10, 0, bitmex.OS_FILLED, time.Now()
+	update := *o
+	f.m.Unlock()
+	f.onOrder([]*swagger.Order{&update}, "update")
+
+	wait(t, a)
+	orders := f.placed()
+	if len(orders) != 3 || orders[1].OrderQty != 10 || orders[2].OrderQty != 5 {
+		t.Errorf("children %#v", orders)
+	}
+}
+
+func TestIceberg_PostOnly(t *testing.T) {
+	f := &fakeClient{crossing: true}
+	a, err := NewIceberg(f, IcebergParams{
+		Params:     Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 25},
+		Price:      4000,
+		DisplayQty: 10,
+		PostOnly:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(t, a)
+	if p := a.Progress(); p.State != Failed || p.Err != ErrPostOnly {
+		t.Errorf("progress %+v", p)
+	}
+	if n := len(f.placed()); n != icebergPostOnlyCancels {
+		t.Errorf("%v children", n)
+	}
+}
+
+func TestIceberg_Capped(t *testing.T) {
+	f := &fakeClient{}
+	_, err := NewIceberg(f, IcebergParams{
+		Params:     Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 25, LimitPrice: 3000},
+		Price:      4000,
+		DisplayQty: 10,
+	})
+	if err == nil {
+		t.Fatal("iceberg above the limit price")
+	}
+	if f.onOrder != nil {
+		t.Error("order handler left registered")
+	}
+}
+
+func TestPauseAndFail(t *testing.T) {
+	f := &fakeClient{fillQty: 1}
+	a, err := NewIceberg(f, IcebergParams{
+		Params:     Params{Symbol: "XBTUSD", Side: bitmex.SIDE_BUY, Quantity: 25},
+		Price:      4000,
+		DisplayQty: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return len(f.placed()) == 1 })
+	if err := a.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if o := f.placed()[0]; o.OrdStatus != bitmex.OS_CANCELED {
+		t.Errorf("child not canceled: %v", o.OrdStatus)
+	}
+
+	f.m.Lock()
+	f.err = errors.New("overloaded")
+	f.m.Unlock()
+	a.Resume()
+	wait(t, a)
+	if p := a.Progress(); p.State != Failed || p.Err == nil || p.Filled != 1 {
+		t.Errorf("progress %+v", p)
+	}
+	if err := a.Resume(); err != ErrFinished {
+		t.Errorf("resume after finish: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,93 @@
+package algo
+
+import (
+	"errors"
+	"strings"
+
+	bitmex "github.com/sumorf/bitmex-api"
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// IcebergParams configures a client-side iceberg
+type IcebergParams struct {
+	Params
+	Price      float64 // limit price of every child
+	DisplayQty int64   // quantity shown at a time
+	PostOnly   bool    // send children with ParticipateDoNotInitiate
+}
+
+// icebergPostOnlyCancels is the number of children in a row canceled for
+// crossing the book after which a post-only iceberg fails
+const icebergPostOnlyCancels = 5
+
+// ErrPostOnly fails a post-only iceberg whose children keep being canceled
+// because they would take liquidity
+var ErrPostOnly = errors.New("algo: post-only children canceled repeatedly")
+
+// Iceberg shows DisplayQty of the parent at Price and sends the next child
+// once the working one has filled.
+type Iceberg struct {
+	*base
+	p IcebergParams
+
+	last     string // OrderID of the latest child, used by step only
+	canceled int    // post-only cancels in a row, used by step only
+}
+
+// NewIceberg starts an iceberg
+func NewIceberg(client Client, p IcebergParams) (*Iceberg, error) {
+	if p.Price <= 0 || p.DisplayQty <= 0 {
+		return nil, errors.New("algo: iceberg needs a price and a display quantity")
+	}
+	if p.capped(p.Price) {
+		return nil, errors.New("algo: iceberg price beyond limit price")
+	}
+	a, err := newBase(client, p.Params)
+	if err != nil {
+		return nil, err
+	}
+	i := &Iceberg{base: a, p: p}
+	a.step = i.step
+	go a.run(nil)
+	a.notify()
+	return i, nil
+}
+
+func (i *Iceberg) step() {
+	progress := i.Progress()
+	if progress.Remaining <= 0 {
+		i.finish(Done, nil)
+		return
+	}
+	if i.inflight() > 0 {
+		return
+	}
+	if last, ok := i.child(i.last); ok && postOnlyCanceled(&last) {
+		i.canceled++
+		if i.canceled >= icebergPostOnlyCancels {
+			i.fail(ErrPostOnly)
+			return
+		}
+	} else {
+		i.canceled = 0
+	}
+
+	qty := i.p.DisplayQty
+	if qty > progress.Remaining {
+		qty = progress.Remaining
+	}
+	var execInst bitmex.ExecInst
+	if i.p.PostOnly {
+		execInst = bitmex.ExecParticipateDoNotInitiate
+	}
+	if order, err := i.place(qty, i.p.Price, "", execInst); err == nil {
+		i.last = order.OrderID
+	}
+}
+
+// postOnlyCanceled reports whether BitMEX canceled a ParticipateDoNotInitiate
+// order because it would have crossed the book
+func postOnlyCanceled(o *swagger.Order) bool {
+	return o.OrdStatus == bitmex.OS_CANCELED && o.CumQty == 0 &&
+		strings.Contains(o.Text, "ParticipateDoNotInitiate")
+}
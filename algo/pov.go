@@ -0,0 +1,83 @@
+package algo
+
+import (
+	"errors"
+
+	bitmex "github.com/sumorf/bitmex-api"
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// POVParams configures a participation of volume algorithm
+type POVParams struct {
+	Params
+	Rate        float64 // share of the market volume to trade, 0 < Rate <= 1
+	MinChildQty int64   // smallest child order, 1 when zero
+}
+
+// POV follows the live trade feed and keeps the filled quantity at Rate of
+// the volume traded in the market since it started. Trades at prices beyond
+// LimitPrice, or while paused, are not counted. Children are immediate-or-cancel limit orders
+// at LimitPrice, or market orders without a limit.
+type POV struct {
+	*base
+	p      POVParams
+	volume float64 // eligible market volume, guarded by base.m
+}
+
+// NewPOV starts a participation of volume algorithm
+func NewPOV(client Client, p POVParams) (*POV, error) {
+	if p.Rate <= 0 || p.Rate > 1 {
+		return nil, errors.New("algo: pov rate must be in (0, 1]")
+	}
+	if p.MinChildQty <= 0 {
+		p.MinChildQty = 1
+	}
+	a, err := newBase(client, p.Params)
+	if err != nil {
+		return nil, err
+	}
+	v := &POV{base: a, p: p}
+	a.step = v.step
+	a.remove = append(a.remove, client.WatchTrades(v.onTrades))
+	go a.run(nil)
+	return v, nil
+}
+
+// onTrades counts the eligible market volume. Trades while paused are left
+// out, so resuming does not catch up on them in one burst.
+func (v *POV) onTrades(trades []*swagger.Trade, action string) {
+	counted := false
+	v.m.Lock()
+	if v.state != Running {
+		v.m.Unlock()
+		return
+	}
+	for _, t := range trades {
+		if t.Symbol == v.p.Symbol && !v.p.capped(t.Price) {
+			v.volume += float64(t.Size)
+			counted = true
+		}
+	}
+	v.m.Unlock()
+	if counted {
+		v.notify()
+	}
+}
+
+func (v *POV) step() {
+	progress := v.Progress()
+	if progress.Remaining <= 0 {
+		v.finish(Done, nil)
+		return
+	}
+
+	v.m.Lock()
+	target := int64(v.p.Rate * v.volume)
+	v.m.Unlock()
+	if target > v.p.Quantity {
+		target = v.p.Quantity
+	}
+	if qty := target - progress.Filled - v.inflight(); qty >= v.p.MinChildQty {
+		v.place(qty, 0, bitmex.TimeInForceImmediateOrCancel, 0)
+	}
+}
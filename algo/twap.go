@@ -0,0 +1,78 @@
+package algo
+
+import (
+	"errors"
+	"time"
+
+	bitmex "github.com/sumorf/bitmex-api"
+)
+
+// TWAPParams configures a time weighted average price algorithm
+type TWAPParams struct {
+	Params
+	Duration time.Duration // execution window
+	Slices   int           // number of child orders over the window
+}
+
+// TWAP splits the parent quantity evenly over a time window. Each slice is
+// sent as an immediate-or-cancel limit order at LimitPrice, or as a market
+// order without a limit. Quantity left over by a slice is carried into the
+// next one. Time spent paused extends the window; the TWAP expires at its
+// end with the unfilled quantity.
+type TWAP struct {
+	*base
+	p     TWAPParams
+	start time.Time
+	sent  int // slices sent, a child per slice
+}
+
+// NewTWAP starts a TWAP algorithm
+func NewTWAP(client Client, p TWAPParams) (*TWAP, error) {
+	if p.Duration <= 0 || p.Slices <= 0 {
+		return nil, errors.New("algo: twap needs a duration and slices")
+	}
+	a, err := newBase(client, p.Params)
+	if err != nil {
+		return nil, err
+	}
+	t := &TWAP{base: a, p: p, start: time.Now()}
+	a.step = t.step
+
+	ticker := time.NewTicker(p.Duration / time.Duration(p.Slices))
+	go func() {
+		defer ticker.Stop()
+		a.run(ticker.C)
+	}()
+	a.notify()
+	return t, nil
+}
+
+func (t *TWAP) step() {
+	interval := t.p.Duration / time.Duration(t.p.Slices)
+	due := int(t.active(t.start)/interval) + 1
+	if due > t.p.Slices {
+		due = t.p.Slices
+	}
+
+	progress := t.Progress()
+	if progress.Remaining <= 0 {
+		t.finish(Done, nil)
+		return
+	}
+	inflight := t.inflight()
+	if t.active(t.start) >= t.p.Duration {
+		// no children after the window, the last ones may still fill
+		if inflight == 0 {
+			t.finish(Expired, nil)
+		}
+		return
+	}
+	if due <= t.sent {
+		return
+	}
+	t.sent = due
+	target := t.p.Quantity * int64(due) / int64(t.p.Slices)
+	if qty := target - progress.Filled - inflight; qty > 0 {
+		t.place(qty, 0, bitmex.TimeInForceImmediateOrCancel, 0)
+	}
+}
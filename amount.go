@@ -0,0 +1,232 @@
+package bitmex
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+const (
+	// 金额的币种, 以最小单位命名
+	CURRENCY_XBT  = "XBt"  // 聪, 1e-8 XBT
+	CURRENCY_USDT = "USDt" // 1e-6 USDT
+	CURRENCY_GWEI = "Gwei" // 1e-9 ETH
+	CURRENCY_USD  = "USD"  // 美分, 1e-2 USD
+)
+
+type currencyUnit struct {
+	base     string // BitMEX currency name of the base unit
+	symbol   string // name of the whole unit
+	decimals int
+}
+
+var currencyUnits = []currencyUnit{
+	{CURRENCY_XBT, "XBT", 8},
+	{CURRENCY_USDT, "USDT", 6},
+	{CURRENCY_GWEI, "ETH", 9},
+	{CURRENCY_USD, "USD", 2},
+}
+
+func unitOf(currency string) currencyUnit {
+	for _, u := range currencyUnits {
+		if u.base == currency {
+			return u
+		}
+	}
+	return currencyUnit{base: currency, symbol: currency}
+}
+
+// Amount is an exact quantity of a currency in integer base units,
+// e.g. satoshis for XBt
+type Amount struct {
+	Currency string // base unit: XBt, USDt, Gwei, USD
+	Value    int64  // number of base units
+}
+
+// NewAmount returns value base units of currency
+func NewAmount(currency string, value int64) Amount {
+	return Amount{Currency: currency, Value: value}
+}
+
+// XBt returns an amount of satoshis
+func XBt(satoshi int64) Amount {
+	return Amount{Currency: CURRENCY_XBT, Value: satoshi}
+}
+
+// XBT returns an amount of bitcoins, rounded to the satoshi
+func XBT(xbt float64) Amount {
+	return FromFloat(CURRENCY_XBT, xbt)
+}
+
+// USD returns an amount of dollars, rounded to the cent
+func USD(usd float64) Amount {
+	return FromFloat(CURRENCY_USD, usd)
+}
+
+// FromFloat returns an amount of whole units of currency (XBT for XBt),
+// rounded to the base unit
+func FromFloat(currency string, whole float64) Amount {
+	scale := math.Pow10(unitOf(currency).decimals)
+	return Amount{Currency: currency, Value: int64(math.Round(whole * scale))}
+}
+
+// ParseAmount parses "0.5 XBT" or "50000000 XBt". Whole and base unit names
+// are both accepted.
+func ParseAmount(s string) (a Amount, err error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		err = fmt.Errorf("amount %q: want <number> <currency>", s)
+		return
+	}
+	number, name := fields[0], fields[1]
+	for _, u := range currencyUnits {
+		if name == u.base && u.base != u.symbol {
+			a.Currency = u.base
+			a.Value, err = strconv.ParseInt(number, 10, 64)
+			return
+		}
+		if strings.EqualFold(name, u.symbol) {
+			a.Currency = u.base
+			a.Value, err = parseDecimal(number, u.decimals)
+			return
+		}
+	}
+	a.Currency = name
+	a.Value, err = strconv.ParseInt(number, 10, 64)
+	return
+}
+
+// parseDecimal parses a decimal number into an integer of 10^-decimals
+// units without going through float64
+func parseDecimal(s string, decimals int) (int64, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if len(frac) > decimals {
+		return 0, fmt.Errorf("amount %v: more than %v decimals", s, decimals)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	v, err := strconv.ParseInt(whole+frac+strings.Repeat("0", decimals-len(frac)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// Symbol returns the name of the whole unit, XBT for XBt
+func (a Amount) Symbol() string {
+	return unitOf(a.Currency).symbol
+}
+
+// Float returns the amount in whole units, XBT for XBt
+func (a Amount) Float() float64 {
+	return float64(a.Value) / math.Pow10(unitOf(a.Currency).decimals)
+}
+
+// String formats the amount in whole units with every decimal, e.g.
+// "0.01000000 XBT"
+func (a Amount) String() string {
+	u := unitOf(a.Currency)
+	if u.decimals == 0 {
+		return fmt.Sprintf("%d %v", a.Value, u.symbol)
+	}
+	sign, v := "", a.Value
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	scale := int64(math.Pow10(u.decimals))
+	return fmt.Sprintf("%v%d.%0*d %v", sign, v/scale, u.decimals, v%scale, u.symbol)
+}
+
+// Convert returns the amount in another currency. price is the price of one
+// whole unit of a in whole units of currency, e.g. the XBTUSD price to
+// convert XBt to USD.
+func (a Amount) Convert(currency string, price float64) Amount {
+	return FromFloat(currency, a.Float()*price)
+}
+
+// InverseValue returns the XBt value of contracts inverse USD contracts
+// (XBTUSD) at price
+func InverseValue(contracts int64, price float64) Amount {
+	if price <= 0 {
+		return XBt(0)
+	}
+	return XBt(int64(math.Round(float64(contracts) * 1e8 / price)))
+}
+
+// InverseContracts returns the number of inverse USD contracts worth a at
+// price, rounded down
+func (a Amount) InverseContracts(price float64) int64 {
+	return int64(a.Convert(CURRENCY_USD, price).Value / 100)
+}
+
+// Balance is the margin account of one currency in typed amounts
+type Balance struct {
+	Currency      string
+	Wallet        Amount // walletBalance
+	Margin        Amount // marginBalance: wallet plus unrealised PnL
+	Available     Amount // availableMargin
+	Withdrawable  Amount // withdrawableMargin
+	UnrealisedPnl Amount
+	RealisedPnl   Amount
+	InitMargin    Amount
+	MaintMargin   Amount
+}
+
+// NewBalance converts a margin record
+func NewBalance(m *swagger.Margin) Balance {
+	amount := func(v int64) Amount { return NewAmount(m.Currency, v) }
+	return Balance{
+		Currency:      m.Currency,
+		Wallet:        amount(m.WalletBalance),
+		Margin:        amount(m.MarginBalance),
+		Available:     amount(m.AvailableMargin),
+		Withdrawable:  amount(m.WithdrawableMargin),
+		UnrealisedPnl: amount(m.UnrealisedPnl),
+		RealisedPnl:   amount(m.RealisedPnl),
+		InitMargin:    amount(m.InitMargin),
+		MaintMargin:   amount(m.MaintMargin),
+	}
+}
+
+// GetBalance returns the margin account in typed amounts
+func (b *BitMEX) GetBalance() (balance Balance, err error) {
+	return b.GetBalanceContext(context.Background())
+}
+
+// GetBalanceContext is like GetBalance but takes a context
+func (b *BitMEX) GetBalanceContext(ctx context.Context) (balance Balance, err error) {
+	margin, err := b.GetMarginContext(ctx)
+	if err != nil {
+		return
+	}
+	balance = NewBalance(&margin)
+	return
+}
+
+// GetWalletAmount returns the wallet balance in typed amounts
+func (b *BitMEX) GetWalletAmount() (amount Amount, err error) {
+	return b.GetWalletAmountContext(context.Background())
+}
+
+// GetWalletAmountContext is like GetWalletAmount but takes a context
+func (b *BitMEX) GetWalletAmountContext(ctx context.Context) (amount Amount, err error) {
+	wallet, err := b.GetWalletContext(ctx)
+	if err != nil {
+		return
+	}
+	amount = NewAmount(wallet.Currency, wallet.Amount)
+	return
+}
@@ -0,0 +1,74 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		want string
+	}{
+		{XBt(1000000), "0.01000000 XBT"},
+		{XBt(-123456789012), "-1234.56789012 XBT"},
+		{NewAmount(CURRENCY_USDT, 2500000), "2.500000 USDT"},
+		{USD(12.345), "12.35 USD"},
+		{NewAmount("XYZ", 7), "7 XYZ"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("%#v.String() = %v, want %v", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Amount
+	}{
+		{"0.5 XBT", XBt(50000000)},
+		{"50000000 XBt", XBt(50000000)},
+		{"21000000.00000001 XBT", XBt(2100000000000001)},
+		{"-.25 usd", USD(-0.25)},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+	if _, err := ParseAmount("0.000000001 XBT"); err == nil {
+		t.Error("sub-satoshi amount parsed")
+	}
+}
+
+func TestAmount_Convert(t *testing.T) {
+	if usd := XBT(0.5).Convert(CURRENCY_USD, 10000); usd != USD(5000) {
+		t.Errorf("0.5 XBT = %v", usd)
+	}
+	if xbt := USD(5000).Convert(CURRENCY_XBT, 1.0/10000); xbt != XBT(0.5) {
+		t.Errorf("5000 USD = %v", xbt)
+	}
+	if v := InverseValue(10000, 8000); v != XBt(125000000) {
+		t.Errorf("10000 XBTUSD at 8000 = %v", v)
+	}
+	if n := XBt(125000000).InverseContracts(8000); n != 10000 {
+		t.Errorf("contracts %v", n)
+	}
+}
+
+func TestNewBalance(t *testing.T) {
+	// a balance above 2^24 satoshis does not survive a float32
+	var m swagger.Margin
+	if err := json.Unmarshal([]byte(`{"currency":"XBt","walletBalance":123456789,"availableMargin":100000001}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	balance := NewBalance(&m)
+	if balance.Wallet != XBt(123456789) || balance.Available.String() != "1.00000001 XBT" {
+		t.Errorf("balance %#v", balance)
+	}
+}
@@ -2,11 +2,10 @@ package bitmex
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"github.com/chuckpreslar/emission"
 	"github.com/sumorf/bitmex-api/recws"
-	"golang.org/x/net/proxy"
-	"log"
-	"net"
 	"time"
 
 	//"github.com/mariuspass/recws"
@@ -29,10 +28,17 @@ type RateLimit struct {
 
 // BitMEX describes the API
 type BitMEX struct {
-	Key      string
-	Secret   string
-	host     string
-	proxyURL string
+	Key    string
+	Secret string
+	host   string
+	wsURL  string
+
+	proxyMutex    sync.RWMutex
+	proxy         *proxyDialer
+	baseTransport *http.Transport // shared by the REST requests
+
+	signerMutex  sync.RWMutex
+	customSigner Signer // set by SetSigner
 
 	ctx                  context.Context
 	timeout              time.Duration
@@ -43,83 +49,168 @@ type BitMEX struct {
 	rateLimitMutex       sync.RWMutex
 	rateLimitPublic      RateLimit
 	rateLimit            RateLimit
+	limiter              *rateLimiter
+	retrier              *retrier
+
+	clockMutex     sync.RWMutex
+	clock          ClockSample
+	clockStop      chan struct{} // stops SetClockSync
+	restAuthExpiry time.Duration
+	wsAuthExpiry   time.Duration
 
 	ws              recws.RecConn
 	emitter         *emission.Emitter
 	subscribeCmd    *WSCmd
 	orderBookLocals map[string]*OrderBookLocal // key: symbol
-	orderLocals     map[string]*swagger.Order  // key: OrderID
+	orderLocals     map[string]*Order          // key: OrderID
+	orderClOrdIDs   map[string]string          // key: ClOrdID, value: OrderID
 	orderBookLoaded map[string]bool            // key: symbol
+
+	orderLocalsMutex sync.RWMutex
+	instruments      *Instruments
+	validation       int32 // ValidationMode, set atomically
+	riskMutex        sync.RWMutex
+	risk             RiskManager
+	killed           int32 // set by KillSwitch
+
+	inflightMutex  sync.Mutex
+	inflight       map[string]*InFlightOrder // key: ClOrdID
+	clOrdIDPrefix  string
+	clOrdIDSession string
+	clOrdIDSeq     uint64
+
+	positionLocalsMutex sync.RWMutex
+	positionLocals      map[string]*Position // key: symbol
+
+	orderGroupsMutex sync.RWMutex
+	orderGroups      map[string]*OrderGroup // key: ClOrdLinkID
+
+	tableHandlersMutex sync.RWMutex
+	tableHandlers      map[string]map[int]tableHandler // key: table
+	tableHandlerSeq    int
 }
 
-// New allows the use of the public or private and websocket api
+// New allows the use of the public or private and websocket api. It never
+// fails: when host does not form a valid URL, which NewWithOptions reports,
+// the client is built anyway and its requests fail.
 func New(host string, key string, secret string) *BitMEX {
+	opts := Options{Host: host, Key: key, Secret: secret}
+	if b, err := NewWithOptions(opts); err == nil {
+		return b
+	}
+	opts.RESTURL = "https://" + host + "/api/v1"
+	opts.WSURL = "wss://" + host + "/realtime"
+	opts.Timeout = 10 * time.Second
+	return newBitMEX(opts, &proxyDialer{})
+}
+
+// Options configures a client created with NewWithOptions. The zero values
+// select the defaults of New.
+type Options struct {
+	Host   string // HostReal or HostTestnet, the host of the default URLs
+	Key    string
+	Secret string
+
+	// RESTURL is the REST base path, https://Host/api/v1 by default
+	RESTURL string
+	// WSURL is the websocket endpoint. By default it is /realtime on the
+	// host of RESTURL, with ws or wss following its http or https scheme.
+	WSURL string
+
+	TLSConfig        *tls.Config       // of the REST and websocket connections
+	UserAgent        string            // of REST requests
+	Timeout          time.Duration     // of each REST attempt, without retry waits; 10s by default
+	HandshakeTimeout time.Duration     // of the websocket, 2s by default
+	Headers          map[string]string // sent with REST requests and the websocket handshake
+	Proxy            ProxyConfig       // of the REST and websocket connections
+}
+
+// NewWithOptions is like New with the endpoints, transport and headers of
+// opts, e.g. to target a local stand-in of BitMEX
+func NewWithOptions(opts Options) (*BitMEX, error) {
+	if opts.RESTURL == "" {
+		opts.RESTURL = "https://" + opts.Host + "/api/v1"
+	}
+	rest, err := url.Parse(opts.RESTURL)
+	if err != nil {
+		return nil, fmt.Errorf("rest url: %v", err)
+	}
+	if rest.Scheme != "http" && rest.Scheme != "https" {
+		return nil, fmt.Errorf("rest url %q: scheme must be http or https", opts.RESTURL)
+	}
+	if rest.Host == "" {
+		return nil, fmt.Errorf("rest url %q: no host", opts.RESTURL)
+	}
+	if opts.Host == "" {
+		opts.Host = rest.Host
+	}
+	if opts.WSURL == "" {
+		ws := url.URL{Scheme: "wss", Host: rest.Host, Path: "/realtime"}
+		if rest.Scheme == "http" {
+			ws.Scheme = "ws"
+		}
+		opts.WSURL = ws.String()
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	proxy, err := newProxyDialer(opts.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	return newBitMEX(opts, proxy), nil
+}
+
+// newBitMEX builds a client from checked opts
+func newBitMEX(opts Options, proxy *proxyDialer) *BitMEX {
 	b := &BitMEX{}
-	b.Key = key
-	b.Secret = secret
+	b.Key = opts.Key
+	b.Secret = opts.Secret
 	b.emitter = emission.NewEmitter()
 	b.orderBookLocals = make(map[string]*OrderBookLocal)
-	b.orderLocals = make(map[string]*swagger.Order)
+	b.orderLocals = make(map[string]*Order)
+	b.orderClOrdIDs = make(map[string]string)
+	b.inflight = make(map[string]*InFlightOrder)
+	b.positionLocals = make(map[string]*Position)
 	b.orderBookLoaded = make(map[string]bool)
+	b.orderGroups = make(map[string]*OrderGroup)
+	b.instruments = newInstruments()
+	b.tableHandlers = make(map[string]map[int]tableHandler)
 	b.ws = recws.RecConn{
 		SubscribeHandler: b.subscribeHandler,
+		HandshakeTimeout: opts.HandshakeTimeout,
+		TLSClientConfig:  opts.TLSConfig,
+		NetDialContext:   b.dialContext,
 	}
-	b.host = host
-	b.ctx = MakeContext(key, secret, host, 10)
-	b.timeout = 10 * time.Second
+	b.host = opts.Host
+	b.wsURL = opts.WSURL
+	b.ctx = MakeContext(opts.Key, opts.Secret, opts.Host, int64(opts.Timeout/time.Second))
+	b.timeout = opts.Timeout
 	b.cfg = GetConfiguration(b.ctx)
-	b.httpClient = &http.Client{
-		Timeout: b.timeout,
+	b.cfg.BasePath = opts.RESTURL
+	if opts.UserAgent != "" {
+		b.cfg.UserAgent = opts.UserAgent
+	}
+	for k, v := range opts.Headers {
+		b.cfg.AddDefaultHeader(k, v)
 	}
+	b.cfg.ExpiresAt = b.restExpires
+	b.cfg.Signer = currentSigner{b}
+	b.restAuthExpiry = time.Duration(b.cfg.ExpireTime) * time.Second
+	b.wsAuthExpiry = DefaultWSAuthExpiry
+	b.limiter = newRateLimiter()
+	b.retrier = newRetrier()
+	b.proxy = proxy
+	b.baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+	b.baseTransport.TLSClientConfig = opts.TLSConfig
+	b.baseTransport.DialContext = b.dialContext
+	b.baseTransport.Proxy = b.environmentProxy
+	b.httpClient = &http.Client{Transport: b.transport(b.baseTransport)}
 	b.cfg.HTTPClient = b.httpClient
 	b.client = swagger.NewAPIClient(b.cfg)
 	return b
 }
 
-// SetHttpProxy proxyURL: http://127.0.0.1:1080
-func (b *BitMEX) SetHttpProxy(proxyURL string) error {
-	proxyURL_, err := url.Parse(proxyURL)
-	if err != nil {
-		return err
-	}
-
-	//adding the proxy settings to the Transport object
-	transport := &http.Transport{
-		Proxy: http.ProxyURL(proxyURL_),
-	}
-
-	//adding the Transport object to the http Client
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   b.timeout,
-	}
-	b.httpClient = client
-	b.cfg.HTTPClient = client
-	b.proxyURL = proxyURL
-	return nil
-}
-
-// SetProxy proxyURL: 127.0.0.1:1080
-func (b *BitMEX) SetProxy(socks5Proxy string) error {
-	// socks5Proxy := "127.0.0.1:1080"
-	dialer, err := proxy.SOCKS5("tcp", socks5Proxy, nil, proxy.Direct)
-	if err != nil {
-		log.Fatal("Error creating dialer, aborting.")
-	}
-
-	dialFunc := func(ctx context.Context, network, addr string) (net.Conn, error) {
-		return dialer.Dial(network, addr)
-	}
-
-	tr := &http.Transport{DialContext: dialFunc} // Dial: dialer.Dial,
-	client := &http.Client{Transport: tr, Timeout: b.timeout}
-
-	b.httpClient = client
-	b.cfg.HTTPClient = client
-	b.proxyURL = "http://" + socks5Proxy
-	return nil
-}
-
 func (b *BitMEX) GetRateLimit() RateLimit {
 	b.rateLimitMutex.RLock()
 	defer b.rateLimitMutex.RUnlock()
@@ -132,6 +223,49 @@ func (b *BitMEX) GetRateLimitPublic() RateLimit {
 	return b.rateLimitPublic
 }
 
+// authContext returns ctx carrying the client's API key, as required by the
+// swagger services. A nil ctx falls back to the client's default context.
+func (b *BitMEX) authContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return b.ctx
+	}
+	if _, ok := ctx.Value(swagger.ContextAPIKey).(swagger.APIKey); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, swagger.ContextAPIKey, b.ctx.Value(swagger.ContextAPIKey))
+}
+
+// WithTraceID returns a context whose REST requests carry id in the
+// X-Request-Id header and in the APIError they fail with
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, swagger.ContextTraceID, id)
+}
+
+// TraceID returns the trace ID of ctx, if any
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(swagger.ContextTraceID).(string)
+	return id
+}
+
+// detach returns a context for follow-up requests of ctx: without its
+// deadline and cancellation, with its credentials and trace ID
+func detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if ctx == nil {
+		return detached
+	}
+	if key, ok := ctx.Value(swagger.ContextAPIKey).(swagger.APIKey); ok {
+		detached = context.WithValue(detached, swagger.ContextAPIKey, key)
+	}
+	if id := TraceID(ctx); id != "" {
+		detached = WithTraceID(detached, id)
+	}
+	return detached
+}
+
 func MakeContext(key string, secret string, host string, timeout int64) context.Context {
 	return context.WithValue(context.TODO(), swagger.ContextAPIKey, swagger.APIKey{
 		Key:     key,
@@ -0,0 +1,464 @@
+package bitmex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+const (
+	bracketEntry      = "E"
+	bracketStopLoss   = "SL"
+	bracketTakeProfit = "TP"
+)
+
+// BracketParams describes an entry order with a stop-loss and a take-profit
+type BracketParams struct {
+	Symbol   string
+	Side     Side    // side of the entry order
+	OrdType  OrdType // entry order type, Limit or Market
+	OrderQty int64   // entry quantity
+	Price    Price   // entry limit price
+
+	StopLoss    Price    // stop-loss trigger price, zero = none
+	TakeProfit  Price    // take-profit limit price, zero = none
+	StopTrigger ExecInst // ExecMarkPrice, ExecLastPrice or ExecIndexPrice for the stop, optional
+
+	// Prefix of the clOrdIDs of every leg. Generated by PlaceBracket when
+	// empty; required by RecoverBracket.
+	Prefix string
+}
+
+// Bracket manages an entry order and its exits on the client side.
+// Each partial fill of the entry places or resizes reduce-only stop-loss and
+// take-profit orders for the filled quantity. When an exit fills the other
+// exit, and whatever is left of the entry, is canceled.
+type Bracket struct {
+	b      *BitMEX
+	params BracketParams
+
+	m          sync.Mutex
+	entry      swagger.Order
+	stopLoss   swagger.Order
+	takeProfit swagger.Order
+	seq        map[string]int // key: leg, last clOrdID sequence
+	replaced   int64          // quantity filled by exits that were replaced
+	err        error
+
+	pendingMutex sync.Mutex
+	pending      []swagger.Order // stream updates not merged yet
+
+	remove func()
+	wake   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// PlaceBracket places the entry order and starts managing its exits.
+// The order stream must be subscribed for exits to be placed.
+func (b *BitMEX) PlaceBracket(ctx context.Context, params BracketParams) (k *Bracket, err error) {
+	if params.OrderQty <= 0 {
+		err = errors.New("bracket: orderQty must be positive")
+		return
+	}
+	if params.Prefix == "" {
+		params.Prefix = "bk" + newLinkID()[:12]
+	}
+	k = newBracket(b, params)
+
+	var order swagger.Order
+	order, err = b.PlaceOrder2Context(ctx, params.Side, params.OrdType, Price{}, params.Price, params.OrderQty, -1,
+		"", 0, params.Symbol, k.clOrdID(bracketEntry), "bracket entry")
+	if err != nil {
+		k.finish()
+		return
+	}
+	k.m.Lock()
+	k.merge(&order)
+	k.m.Unlock()
+	k.notify()
+	return
+}
+
+// RecoverBracket resumes managing a bracket after a restart or reconnect.
+// Its state is rebuilt from the orders whose clOrdID starts with params.Prefix.
+func (b *BitMEX) RecoverBracket(ctx context.Context, params BracketParams) (k *Bracket, err error) {
+	if params.Prefix == "" {
+		err = errors.New("bracket: prefix required")
+		return
+	}
+	var orders []swagger.Order
+	orders, err = b.bracketOrders(ctx, params.Symbol, params.Prefix)
+	if err != nil {
+		return
+	}
+	k = newBracket(b, params)
+	sort.SliceStable(orders, func(i, j int) bool {
+		_, si := k.leg(orders[i].ClOrdID)
+		_, sj := k.leg(orders[j].ClOrdID)
+		return si < sj
+	})
+	k.m.Lock()
+	for i := range orders {
+		k.merge(&orders[i])
+	}
+	found := k.entry.OrderID != ""
+	k.m.Unlock()
+	if !found {
+		k.finish()
+		k, err = nil, NotFound
+		return
+	}
+	k.notify()
+	return
+}
+
+// bracketPageSize is the number of orders RecoverBracket fetches per
+// request, the most BitMEX returns
+const bracketPageSize = 500
+
+// bracketOrders returns the orders of the bracket prefix, newest first. The
+// order history is paged back until the entry, the oldest leg, is found.
+func (b *BitMEX) bracketOrders(ctx context.Context, symbol string, prefix string) (legs []swagger.Order, err error) {
+	auth := b.authContext(ctx)
+	for start := 0; ; start += bracketPageSize {
+		params := map[string]interface{}{
+			"reverse": true,
+			"count":   float32(bracketPageSize),
+			"start":   float32(start),
+		}
+		if symbol != "" {
+			params["symbol"] = symbol
+		}
+		orders, response, err := b.client.OrderApi.OrderGetOrders(auth, params)
+		if err != nil {
+			return nil, err
+		}
+		b.onResponse(response)
+
+		entry := false
+		for _, o := range orders {
+			if strings.HasPrefix(o.ClOrdID, prefix+"-") {
+				legs = append(legs, o)
+				entry = entry || o.ClOrdID == prefix+"-"+bracketEntry
+			}
+		}
+		if entry || len(orders) < bracketPageSize {
+			return legs, nil
+		}
+	}
+}
+
+func newBracket(b *BitMEX, params BracketParams) *Bracket {
+	k := &Bracket{
+		b:      b,
+		params: params,
+		seq:    make(map[string]int),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	k.remove = b.WatchOrders(k.onOrders)
+	go k.run()
+	return k
+}
+
+// Prefix returns the clOrdID prefix shared by all legs
+func (k *Bracket) Prefix() string {
+	return k.params.Prefix
+}
+
+// Entry returns the latest state of the entry order
+func (k *Bracket) Entry() swagger.Order {
+	k.m.Lock()
+	defer k.m.Unlock()
+	return k.entry
+}
+
+// StopLoss returns the latest state of the stop-loss order
+func (k *Bracket) StopLoss() swagger.Order {
+	k.m.Lock()
+	defer k.m.Unlock()
+	return k.stopLoss
+}
+
+// TakeProfit returns the latest state of the take-profit order
+func (k *Bracket) TakeProfit() swagger.Order {
+	k.m.Lock()
+	defer k.m.Unlock()
+	return k.takeProfit
+}
+
+// Err returns the last error met while managing the exits
+func (k *Bracket) Err() error {
+	k.m.Lock()
+	defer k.m.Unlock()
+	return k.err
+}
+
+// Done is closed when the bracket is flat and no leg is working
+func (k *Bracket) Done() <-chan struct{} {
+	return k.done
+}
+
+// Cancel cancels every working leg and stops managing the bracket.
+// The filled position is left untouched.
+func (k *Bracket) Cancel(ctx context.Context) (err error) {
+	var working []string
+	k.m.Lock()
+	for _, o := range []*swagger.Order{&k.entry, &k.stopLoss, &k.takeProfit} {
+		if isWorking(o) {
+			working = append(working, o.OrderID)
+		}
+	}
+	k.m.Unlock()
+
+	for _, orderID := range working {
+		var order swagger.Order
+		order, err = k.b.CancelOrderContext(ctx, orderID)
+		if err != nil {
+			return
+		}
+		k.m.Lock()
+		k.merge(&order)
+		k.m.Unlock()
+	}
+	k.finish()
+	return
+}
+
+func (k *Bracket) clOrdID(leg string) string {
+	if leg == bracketEntry {
+		return k.params.Prefix + "-" + leg
+	}
+	k.seq[leg]++
+	return k.params.Prefix + "-" + leg + strconv.Itoa(k.seq[leg])
+}
+
+// leg returns the leg name of a clOrdID and its sequence
+func (k *Bracket) leg(clOrdID string) (leg string, seq int) {
+	if !strings.HasPrefix(clOrdID, k.params.Prefix+"-") {
+		return
+	}
+	s := clOrdID[len(k.params.Prefix)+1:]
+	for _, name := range []string{bracketEntry, bracketStopLoss, bracketTakeProfit} {
+		if strings.HasPrefix(s, name) {
+			seq, _ = strconv.Atoi(s[len(name):])
+			return name, seq
+		}
+	}
+	return
+}
+
+// merge stores an order update into the matching leg. Caller holds k.m.
+func (k *Bracket) merge(o *swagger.Order) bool {
+	name, seq := k.leg(o.ClOrdID)
+	var cur *swagger.Order
+	switch name {
+	case bracketEntry:
+		cur = &k.entry
+	case bracketStopLoss:
+		cur = &k.stopLoss
+	case bracketTakeProfit:
+		cur = &k.takeProfit
+	default:
+		return false
+	}
+	if seq > k.seq[name] {
+		k.seq[name] = seq
+	}
+	if cur.OrderID != "" && cur.OrderID != o.OrderID {
+		if _, curSeq := k.leg(cur.ClOrdID); curSeq > seq {
+			// an update of an older, replaced leg
+			return false
+		}
+		k.replaced += cur.CumQty
+	}
+	if cur.OrderID == o.OrderID && o.Timestamp.Before(cur.Timestamp) {
+		return false
+	}
+	*cur = *o
+	return true
+}
+
+// onOrders queues the updates of our legs; it runs on the websocket
+// goroutine so it must not wait for a reconcile in progress.
+func (k *Bracket) onOrders(orders []*swagger.Order, action string) {
+	queued := false
+	k.pendingMutex.Lock()
+	for _, o := range orders {
+		if name, _ := k.leg(o.ClOrdID); name != "" {
+			k.pending = append(k.pending, *o)
+			queued = true
+		}
+	}
+	k.pendingMutex.Unlock()
+	if queued {
+		k.notify()
+	}
+}
+
+func (k *Bracket) notify() {
+	select {
+	case k.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (k *Bracket) run() {
+	for {
+		select {
+		case <-k.done:
+			return
+		case <-k.wake:
+			k.step()
+		}
+	}
+}
+
+// step merges the queued updates and reconciles the exits. The rest calls
+// are made without k.m held so readers and Cancel are not held up by them.
+func (k *Bracket) step() {
+	k.pendingMutex.Lock()
+	pending := k.pending
+	k.pending = nil
+	k.pendingMutex.Unlock()
+
+	k.m.Lock()
+	for i := range pending {
+		k.merge(&pending[i])
+	}
+	calls, flat, err := k.reconcile()
+	k.m.Unlock()
+
+	var orders []swagger.Order
+	for _, call := range calls {
+		if err != nil {
+			break
+		}
+		var order swagger.Order
+		order, err = call()
+		if err == nil {
+			orders = append(orders, order)
+		}
+	}
+
+	k.m.Lock()
+	defer k.m.Unlock()
+	for i := range orders {
+		k.merge(&orders[i])
+	}
+	if err != nil {
+		k.err = err
+		log.Printf("bracket %v: %v", k.params.Prefix, err)
+		return
+	}
+	if flat || k.settled() {
+		k.finish()
+	}
+}
+
+func (k *Bracket) finish() {
+	k.once.Do(func() {
+		k.remove()
+		close(k.done)
+	})
+}
+
+func isWorking(o *swagger.Order) bool {
+	return o.OrderID != "" && (o.OrdStatus == OS_NEW || o.OrdStatus == OS_PARTIALLY_FILLED)
+}
+
+// bracketCall is a rest call planned by reconcile
+type bracketCall func() (swagger.Order, error)
+
+// reconcile plans the calls that bring the exits in line with the filled
+// entry quantity. flat is set when the bracket is finished once the calls
+// succeed. Caller holds k.m.
+func (k *Bracket) reconcile() (calls []bracketCall, flat bool, err error) {
+	filled := k.entry.CumQty
+	exited := k.replaced + k.stopLoss.CumQty + k.takeProfit.CumQty
+	remaining := filled - exited
+
+	if k.entry.OrdStatus == OS_REJECTED {
+		k.finish()
+		err = fmt.Errorf("entry rejected: %v", k.entry.OrdRejReason)
+		return
+	}
+
+	cancel := func(o *swagger.Order) {
+		if isWorking(o) {
+			orderID := o.OrderID
+			calls = append(calls, func() (swagger.Order, error) {
+				return k.b.CancelOrder(orderID)
+			})
+		}
+	}
+
+	exitFilled := k.stopLoss.OrdStatus == OS_FILLED || k.takeProfit.OrdStatus == OS_FILLED
+	if exitFilled && remaining <= 0 {
+		cancel(&k.entry)
+		cancel(&k.stopLoss)
+		cancel(&k.takeProfit)
+		flat = true
+		return
+	}
+
+	exitSide := k.params.Side.Opposite()
+
+	legs := []struct {
+		name  string
+		order *swagger.Order
+		price Price
+	}{
+		{bracketStopLoss, &k.stopLoss, k.params.StopLoss},
+		{bracketTakeProfit, &k.takeProfit, k.params.TakeProfit},
+	}
+	for _, leg := range legs {
+		if leg.price.IsZero() {
+			continue
+		}
+		o := leg.order
+		switch {
+		case remaining <= 0:
+			cancel(o)
+		case isWorking(o):
+			if o.LeavesQty != remaining {
+				orderID := o.OrderID
+				calls = append(calls, func() (swagger.Order, error) {
+					return k.b.AmendOrder2(orderID, "", "", 0, 0, 0, remaining, Price{}, Price{}, Price{}, "bracket resize")
+				})
+			}
+		case o.OrdStatus == OS_REJECTED:
+			// don't retry a rejected exit on every update
+		default:
+			price, clOrdID := leg.price, k.clOrdID(leg.name)
+			if leg.name == bracketStopLoss {
+				calls = append(calls, func() (swagger.Order, error) {
+					return k.b.PlaceOrder2(exitSide, OrdTypeStop, price, Price{}, remaining, -1,
+						"", ExecReduceOnly|k.params.StopTrigger, k.params.Symbol, clOrdID, "bracket stop-loss")
+				})
+			} else {
+				calls = append(calls, func() (swagger.Order, error) {
+					return k.b.PlaceOrder2(exitSide, OrdTypeLimit, Price{}, price, remaining, -1,
+						"", ExecReduceOnly, k.params.Symbol, clOrdID, "bracket take-profit")
+				})
+			}
+		}
+	}
+	return
+}
+
+// settled reports whether the entry is done and no exit is left to work.
+// Caller holds k.m.
+func (k *Bracket) settled() bool {
+	remaining := k.entry.CumQty - k.replaced - k.stopLoss.CumQty - k.takeProfit.CumQty
+	entryDone := k.entry.OrderID != "" && !isWorking(&k.entry)
+	return entryDone && remaining <= 0 && !isWorking(&k.stopLoss) && !isWorking(&k.takeProfit)
+}
@@ -0,0 +1,303 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// fakeOrderServer keeps orders placed, amended and canceled through the rest api
+type fakeOrderServer struct {
+	m      sync.Mutex
+	orders map[string]*swagger.Order // key: OrderID
+	placed []string                  // OrderIDs, oldest first
+}
+
+func newFakeOrderServer() (*fakeOrderServer, *httptest.Server) {
+	f := &fakeOrderServer{orders: make(map[string]*swagger.Order)}
+	return f, httptest.NewServer(f)
+}
+
+func (f *fakeOrderServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	form := map[string]string{}
+	json.Unmarshal(body, &form)
+	number := func(key string) float64 {
+		v, _ := strconv.ParseFloat(form[key], 64)
+		return v
+	}
+
+	var order *swagger.Order
+	switch r.Method {
+	case http.MethodPost:
+		order = &swagger.Order{
+			OrderID:   "id-" + form["clOrdID"],
+			ClOrdID:   form["clOrdID"],
+			Symbol:    form["symbol"],
+			Side:      form["side"],
+			OrdType:   form["ordType"],
+			ExecInst:  form["execInst"],
+			OrderQty:  int64(number("orderQty")),
+			LeavesQty: int64(number("orderQty")),
+			Price:     number("price"),
+			StopPx:    number("stopPx"),
+			OrdStatus: OS_NEW,
+			Timestamp: time.Now(),
+		}
+		f.orders[order.OrderID] = order
+		f.placed = append(f.placed, order.OrderID)
+	case http.MethodGet:
+		// newest first with reverse=true, 100 orders by default
+		query := r.URL.Query()
+		list := []*swagger.Order{}
+		for _, id := range f.placed {
+			if o := f.orders[id]; query.Get("symbol") == "" || o.Symbol == query.Get("symbol") {
+				list = append(list, o)
+			}
+		}
+		if query.Get("reverse") == "true" {
+			for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+				list[i], list[j] = list[j], list[i]
+			}
+		}
+		start, _ := strconv.Atoi(query.Get("start"))
+		count, _ := strconv.Atoi(query.Get("count"))
+		if count == 0 {
+			count = 100
+		}
+		if start > len(list) {
+			start = len(list)
+		}
+		if start+count < len(list) {
+			list = list[:start+count]
+		}
+		json.NewEncoder(w).Encode(list[start:])
+		return
+	case http.MethodPut:
+		order = f.orders[form["orderID"]]
+		if leaves := number("leavesQty"); leaves > 0 {
+			order.LeavesQty = int64(leaves)
+			order.OrderQty = order.CumQty + order.LeavesQty
+		}
+		if stopPx := number("stopPx"); stopPx > 0 {
+			order.StopPx = stopPx
+		}
+		order.Timestamp = time.Now()
+	case http.MethodDelete:
+		order = f.orders[form["orderID"]]
+		order.OrdStatus = OS_CANCELED
+		order.LeavesQty = 0
+		order.Timestamp = time.Now()
+		json.NewEncoder(w).Encode([]*swagger.Order{order})
+		return
+	}
+	json.NewEncoder(w).Encode(order)
+}
+
+func (f *fakeOrderServer) get(orderID string) swagger.Order {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if o, ok := f.orders[orderID]; ok {
+		return *o
+	}
+	return swagger.Order{}
+}
+
+// fill fills qty of an order and returns the stream update
+// fill executes qty of an order and returns the partial update BitMEX sends
+// for it
+func (f *fakeOrderServer) fill(orderID string, qty int64) *Response {
+	f.m.Lock()
+	defer f.m.Unlock()
+	o := f.orders[orderID]
+	o.CumQty += qty
+	o.LeavesQty -= qty
+	o.OrdStatus = OS_PARTIALLY_FILLED
+	if o.LeavesQty == 0 {
+		o.OrdStatus = OS_FILLED
+	}
+	o.Timestamp = time.Now()
+	raw, _ := json.Marshal([]map[string]interface{}{{
+		"orderID":   o.OrderID,
+		"ordStatus": o.OrdStatus,
+		"cumQty":    o.CumQty,
+		"leavesQty": o.LeavesQty,
+		"timestamp": o.Timestamp,
+	}})
+	return &Response{Action: bitmexActionUpdateData, raw: raw}
+}
+
+// orderMessage returns an order stream message carrying orders
+func orderMessage(action string, orders ...*swagger.Order) *Response {
+	raw, _ := json.Marshal(orders)
+	return &Response{Action: action, Data: orders, raw: raw}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %v", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBitMEX_Bracket(t *testing.T) {
+	f, srv := newFakeOrderServer()
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	k, err := b.PlaceBracket(nil, BracketParams{
+		Symbol:     "XBTUSD",
+		Side:       SIDE_BUY,
+		OrdType:    ORD_TYPE_LIMIT,
+		OrderQty:   100,
+		Price:      MustPrice("5000"),
+		StopLoss:   MustPrice("4000"),
+		TakeProfit: MustPrice("6000"),
+		Prefix:     "t1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := k.Entry()
+	b.processOrder(orderMessage(bitmexActionInsertData, &entry))
+
+	b.processOrder(f.fill(entry.OrderID, 40))
+	waitFor(t, "exits", func() bool {
+		return f.get("id-t1-SL1").LeavesQty == 40 && f.get("id-t1-TP1").LeavesQty == 40
+	})
+	if sl := f.get("id-t1-SL1"); sl.Side != SIDE_SELL || sl.ExecInst != "ReduceOnly" || sl.StopPx != 4000 {
+		t.Errorf("stop-loss %#v", sl)
+	}
+
+	b.processOrder(f.fill(entry.OrderID, 60))
+	waitFor(t, "resize", func() bool {
+		return f.get("id-t1-SL1").LeavesQty == 100 && f.get("id-t1-TP1").LeavesQty == 100
+	})
+
+	tp := f.get("id-t1-TP1")
+	b.processOrder(orderMessage(bitmexActionInsertData, &tp))
+	b.processOrder(f.fill(tp.OrderID, 100))
+	select {
+	case <-k.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("bracket not done")
+	}
+	if status := f.get("id-t1-SL1").OrdStatus; status != OS_CANCELED {
+		t.Errorf("stop-loss status %v", status)
+	}
+}
+
+func TestBitMEX_RecoverBracket(t *testing.T) {
+	f, srv := newFakeOrderServer()
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	// the bracket is preceded by a long history and followed by other orders
+	f.m.Lock()
+	add := func(clOrdID string, status string) {
+		o := &swagger.Order{OrderID: "id-" + clOrdID, ClOrdID: clOrdID, Symbol: "XBTUSD", Side: SIDE_BUY,
+			OrderQty: 100, LeavesQty: 100, OrdStatus: status}
+		f.orders[o.OrderID] = o
+		f.placed = append(f.placed, o.OrderID)
+	}
+	for i := 0; i < 700; i++ {
+		add("old"+strconv.Itoa(i), OS_FILLED)
+	}
+	add("r1-E", OS_FILLED)
+	add("r1-SL1", OS_NEW)
+	add("r1-TP1", OS_NEW)
+	for i := 0; i < 600; i++ {
+		add("new"+strconv.Itoa(i), OS_CANCELED)
+	}
+	f.m.Unlock()
+
+	k, err := b.RecoverBracket(nil, BracketParams{Symbol: "XBTUSD", Side: SIDE_BUY, OrderQty: 100, Prefix: "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Entry().OrderID != "id-r1-E" {
+		t.Errorf("entry %#v", k.Entry())
+	}
+	k.m.Lock()
+	sl, tp := k.stopLoss.ClOrdID, k.takeProfit.ClOrdID
+	k.m.Unlock()
+	if sl != "r1-SL1" || tp != "r1-TP1" {
+		t.Errorf("exits %v %v", sl, tp)
+	}
+
+	if _, err := b.RecoverBracket(nil, BracketParams{Symbol: "XBTUSD", Prefix: "missing"}); err != NotFound {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestBitMEX_BracketCallsUnlocked(t *testing.T) {
+	f, srv := newFakeOrderServer()
+	defer srv.Close()
+
+	// amends wait until released
+	release := make(chan struct{})
+	amending := make(chan struct{}, 2)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			amending <- struct{}{}
+			<-release
+		}
+		f.ServeHTTP(w, r)
+	})
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	k, err := b.PlaceBracket(nil, BracketParams{
+		Symbol:   "XBTUSD",
+		Side:     SIDE_BUY,
+		OrdType:  ORD_TYPE_LIMIT,
+		OrderQty: 100,
+		Price:    MustPrice("5000"),
+		StopLoss: MustPrice("4000"),
+		Prefix:   "t2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := k.Entry()
+	b.processOrder(orderMessage(bitmexActionInsertData, &entry))
+	b.processOrder(f.fill(entry.OrderID, 40))
+	waitFor(t, "stop-loss", func() bool { return f.get("id-t2-SL1").LeavesQty == 40 })
+
+	b.processOrder(f.fill(entry.OrderID, 60))
+	select {
+	case <-amending:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no resize")
+	}
+	got := make(chan swagger.Order)
+	go func() { got <- k.Entry() }()
+	select {
+	case e := <-got:
+		if e.CumQty != 100 {
+			t.Errorf("entry cumQty %v", e.CumQty)
+		}
+	case <-time.After(time.Second):
+		t.Error("bracket locked during the resize")
+	}
+	close(release)
+	waitFor(t, "resize", func() bool { return f.get("id-t2-SL1").LeavesQty == 100 })
+}
@@ -0,0 +1,395 @@
+package bitmex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// BulkBatchSize is the maximum number of orders sent in one bulk request.
+// Larger inputs are split into several requests.
+var BulkBatchSize = 100
+
+// OrderRequest describes one order for PlaceOrders
+type OrderRequest struct {
+	Symbol          string
+	Side            Side
+	OrdType         OrdType
+	OrderQty        int64
+	Price           Price // Limit order only
+	StopPx          Price
+	DisplayQty      *int64 // nil = fully visible, 0 = hidden
+	TimeInForce     TimeInForce
+	ExecInst        ExecInst
+	ClOrdID         string // generated when empty
+	ClOrdLinkID     string
+	ContingencyType ContingencyType
+	PegPriceType    PegPriceType
+	PegOffsetValue  Price
+	Text            string
+}
+
+// AmendRequest describes one amendment for AmendOrders.
+// Either OrderID or OrigClOrdID must be set.
+type AmendRequest struct {
+	Symbol         string // used only to group requests
+	OrderID        string
+	OrigClOrdID    string
+	ClOrdID        string
+	OrderQty       int64
+	LeavesQty      int64
+	Price          Price
+	StopPx         Price
+	PegOffsetValue Price
+	Text           string
+}
+
+// BulkResult is the outcome of one entry of a bulk request, in the same
+// position as the request it belongs to.
+type BulkResult struct {
+	Order Order
+	Err   error
+}
+
+// OrderRejectedError is returned for an order the exchange accepted in a
+// bulk request but rejected individually.
+type OrderRejectedError struct {
+	OrderID string
+	ClOrdID string
+	Reason  string
+}
+
+func (e *OrderRejectedError) Error() string {
+	return fmt.Sprintf("order rejected: orderID=%v clOrdID=%v reason=%v", e.OrderID, e.ClOrdID, e.Reason)
+}
+
+func (r *OrderRequest) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	params["symbol"] = r.Symbol
+	if r.Side != "" {
+		params["side"] = string(r.Side)
+	}
+	if r.OrdType != "" {
+		params["ordType"] = string(r.OrdType)
+	}
+	params["orderQty"] = r.OrderQty
+	if !r.Price.IsZero() {
+		params["price"] = r.Price
+	}
+	if !r.StopPx.IsZero() {
+		params["stopPx"] = r.StopPx
+	}
+	if r.DisplayQty != nil {
+		params["displayQty"] = *r.DisplayQty
+	}
+	if r.TimeInForce != "" {
+		params["timeInForce"] = string(r.TimeInForce)
+	}
+	if r.ExecInst != 0 {
+		params["execInst"] = r.ExecInst.String()
+	}
+	if r.ClOrdID != "" {
+		params["clOrdID"] = r.ClOrdID
+	}
+	if r.ClOrdLinkID != "" {
+		params["clOrdLinkID"] = r.ClOrdLinkID
+	}
+	if r.ContingencyType != "" {
+		params["contingencyType"] = string(r.ContingencyType)
+	}
+	if r.PegPriceType != "" {
+		params["pegPriceType"] = string(r.PegPriceType)
+	}
+	if !r.PegOffsetValue.IsZero() {
+		params["pegOffsetValue"] = r.PegOffsetValue
+	}
+	if r.Text == "" {
+		params["text"] = `open with bitmex api`
+	} else {
+		params["text"] = r.Text
+	}
+	return params
+}
+
+func (r *AmendRequest) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	if r.OrderID != "" {
+		params["orderID"] = r.OrderID
+	}
+	if r.OrigClOrdID != "" {
+		params["origClOrdID"] = r.OrigClOrdID
+	}
+	if r.ClOrdID != "" {
+		params["clOrdID"] = r.ClOrdID
+	}
+	if r.OrderQty != 0 {
+		params["orderQty"] = r.OrderQty
+	}
+	if r.LeavesQty != 0 {
+		params["leavesQty"] = r.LeavesQty
+	}
+	if !r.Price.IsZero() {
+		params["price"] = r.Price
+	}
+	if !r.StopPx.IsZero() {
+		params["stopPx"] = r.StopPx
+	}
+	if !r.PegOffsetValue.IsZero() {
+		params["pegOffsetValue"] = r.PegOffsetValue
+	}
+	if r.Text != "" {
+		params["text"] = r.Text
+	}
+	return params
+}
+
+// bulkBatches groups the indexes of n requests by symbol, keeping the input
+// order, and splits every group into batches of at most BulkBatchSize.
+func bulkBatches(n int, symbol func(i int) string) [][]int {
+	size := BulkBatchSize
+	if size <= 0 {
+		size = 1
+	}
+	var symbols []string
+	groups := map[string][]int{}
+	for i := 0; i < n; i++ {
+		s := symbol(i)
+		if _, ok := groups[s]; !ok {
+			symbols = append(symbols, s)
+		}
+		groups[s] = append(groups[s], i)
+	}
+	var batches [][]int
+	for _, s := range symbols {
+		group := groups[s]
+		for len(group) > size {
+			batches = append(batches, group[:size])
+			group = group[size:]
+		}
+		batches = append(batches, group)
+	}
+	return batches
+}
+
+// PlaceOrders places several orders with as few bulk requests as possible.
+// Orders are grouped by symbol and split into batches of BulkBatchSize.
+// The returned results are in the same order as orders. err is the first
+// request-level error; the entries of a failed batch carry it as well.
+// Orders that fail validation against the instrument registry carry an
+// *InvalidOrderError and are not sent. When a batch fails without telling
+// whether it reached BitMEX, its orders are resolved by clOrdID; those still
+// unknown carry an *OrderUnknownError.
+func (b *BitMEX) PlaceOrders(ctx context.Context, orders []OrderRequest) (results []BulkResult, err error) {
+	results = make([]BulkResult, len(orders))
+	// validation may round, work on a copy
+	orders = append([]OrderRequest(nil), orders...)
+	for i := range orders {
+		results[i].Err = b.checkOrderRequest(&orders[i])
+		if orders[i].ClOrdID == "" {
+			orders[i].ClOrdID = b.NewClOrdID()
+		}
+	}
+	err = b.sendOrders(ctx, orders, results)
+	return
+}
+
+// sendOrders places the validated orders, those whose result carries no
+// error yet, and returns the first request-level error
+func (b *BitMEX) sendOrders(ctx context.Context, orders []OrderRequest, results []BulkResult) (err error) {
+	batches := bulkBatches(len(orders), func(i int) string { return orders[i].Symbol })
+	for _, batch := range batches {
+		batch = validOnly(results, batch)
+		if len(batch) == 0 {
+			continue
+		}
+		var list []map[string]interface{}
+		for _, i := range batch {
+			list = append(list, orders[i].params())
+			b.addInFlight(orders[i].ClOrdID, orders[i].Symbol)
+		}
+		out, e := b.sendBulk(ctx, list, false)
+		if e == nil {
+			for _, i := range batch {
+				b.removeInFlight(orders[i].ClOrdID)
+			}
+			mapBulkResults(results, batch, out, func(i int, o *swagger.Order) bool {
+				return orders[i].ClOrdID == o.ClOrdID
+			})
+			continue
+		}
+		for _, i := range batch {
+			if ambiguous(e) {
+				order, unknown := b.resolveFailed(ctx, orders[i].ClOrdID, orders[i].Symbol, e)
+				if unknown == nil {
+					results[i] = bulkResult(order)
+					continue
+				}
+				results[i].Err = unknown
+			} else {
+				b.removeInFlight(orders[i].ClOrdID)
+				results[i].Err = e
+			}
+			if err == nil {
+				err = results[i].Err
+			}
+		}
+	}
+	return
+}
+
+// AmendOrders amends several orders with as few bulk requests as possible.
+// Requests are grouped by Symbol; results follow the order of amends.
+func (b *BitMEX) AmendOrders(ctx context.Context, amends []AmendRequest) (results []BulkResult, err error) {
+	results = make([]BulkResult, len(amends))
+	amends = append([]AmendRequest(nil), amends...)
+	for i := range amends {
+		results[i].Err = b.checkAmendRequest(&amends[i])
+	}
+	batches := bulkBatches(len(amends), func(i int) string { return amends[i].Symbol })
+	for _, batch := range batches {
+		batch = validOnly(results, batch)
+		if len(batch) == 0 {
+			continue
+		}
+		var list []map[string]interface{}
+		for _, i := range batch {
+			list = append(list, amends[i].params())
+		}
+		out, e := b.sendBulk(ctx, list, true)
+		if e == nil {
+			mapBulkResults(results, batch, out, func(i int, o *swagger.Order) bool {
+				a := &amends[i]
+				if a.OrderID != "" {
+					return a.OrderID == o.OrderID
+				}
+				if a.ClOrdID != "" {
+					return a.ClOrdID == o.ClOrdID
+				}
+				return a.OrigClOrdID != "" && a.OrigClOrdID == o.ClOrdID
+			})
+		} else {
+			for _, i := range batch {
+				results[i].Err = e
+			}
+			if err == nil {
+				err = e
+			}
+		}
+	}
+	return
+}
+
+func (b *BitMEX) sendBulk(ctx context.Context, list []map[string]interface{}, amend bool) (orders []swagger.Order, err error) {
+	var response *http.Response
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return
+	}
+	params := map[string]interface{}{}
+	params["orders"] = string(data)
+
+	ctx = withRequestCost(b.authContext(ctx), bulkCost(len(list), amend))
+	if amend {
+		orders, response, err = b.client.OrderApi.OrderAmendBulk(ctx, params)
+	} else {
+		orders, response, err = b.client.OrderApi.OrderNewBulk(ctx, params)
+	}
+	if err != nil {
+		return
+	}
+	b.onResponse(response)
+	return
+}
+
+// validOnly drops the entries of batch that failed validation
+func validOnly(results []BulkResult, batch []int) []int {
+	var valid []int
+	for _, i := range batch {
+		if results[i].Err == nil {
+			valid = append(valid, i)
+		}
+	}
+	return valid
+}
+
+func (b *BitMEX) checkOrderRequest(r *OrderRequest) error {
+	if err := checkEnums(r.Side, r.OrdType, r.TimeInForce, r.ExecInst); err != nil {
+		return err
+	}
+	if r.ContingencyType != "" && !r.ContingencyType.Valid() {
+		return fmt.Errorf("invalid contingencyType %q", r.ContingencyType)
+	}
+	if r.PegPriceType != "" && !r.PegPriceType.Valid() {
+		return fmt.Errorf("invalid pegPriceType %q", r.PegPriceType)
+	}
+	qty := float64(r.OrderQty)
+	var display float64
+	if r.DisplayQty != nil {
+		display = float64(*r.DisplayQty)
+	}
+	err := b.checkOrder(r.Symbol, string(r.Side), &r.Price, &r.StopPx, qtyField{"orderQty", &qty}, qtyField{"displayQty", &display})
+	r.OrderQty = int64(qty)
+	if r.DisplayQty != nil {
+		d := int64(display)
+		r.DisplayQty = &d
+	}
+	if err != nil {
+		return err
+	}
+	return b.checkRisk(&RiskOrder{Symbol: r.Symbol, Side: r.Side, OrdType: r.OrdType, OrderQty: r.OrderQty,
+		Price: r.Price.Float64(), StopPx: r.StopPx.Float64(), ExecInst: r.ExecInst})
+}
+
+func (b *BitMEX) checkAmendRequest(r *AmendRequest) error {
+	qty, leaves := float64(r.OrderQty), float64(r.LeavesQty)
+	err := b.checkAmend(r.OrderID, &r.Price, &r.StopPx, qtyField{"orderQty", &qty}, qtyField{"leavesQty", &leaves})
+	r.OrderQty, r.LeavesQty = int64(qty), int64(leaves)
+	if err != nil {
+		return err
+	}
+	return b.checkAmendRisk(r.OrderID, r.OrderQty, r.LeavesQty, r.Price, r.StopPx)
+}
+
+// mapBulkResults stores the orders returned for batch into results.
+// BitMEX answers in request order, so results are matched by position;
+// match is only consulted when the exchange returned a different count.
+func mapBulkResults(results []BulkResult, batch []int, orders []swagger.Order, match func(i int, o *swagger.Order) bool) {
+	if len(orders) == len(batch) {
+		for k, i := range batch {
+			results[i] = bulkResult(orders[k])
+		}
+		return
+	}
+
+	used := make([]bool, len(orders))
+	for _, i := range batch {
+		found := false
+		for k := range orders {
+			if !used[k] && match(i, &orders[k]) {
+				used[k] = true
+				results[i] = bulkResult(orders[k])
+				found = true
+				break
+			}
+		}
+		if !found {
+			results[i].Err = NotFound
+		}
+	}
+}
+
+func bulkResult(order swagger.Order) BulkResult {
+	result := BulkResult{Order: OrderFrom(&order)}
+	if order.OrdStatus == OS_REJECTED {
+		result.Err = &OrderRejectedError{
+			OrderID: order.OrderID,
+			ClOrdID: order.ClOrdID,
+			Reason:  order.OrdRejReason,
+		}
+	}
+	return result
+}
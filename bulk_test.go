@@ -0,0 +1,86 @@
+package bitmex
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBulkBatches(t *testing.T) {
+	old := BulkBatchSize
+	defer func() { BulkBatchSize = old }()
+	BulkBatchSize = 2
+
+	symbols := []string{"XBTUSD", "ETHUSD", "XBTUSD", "XBTUSD", "ETHUSD"}
+	batches := bulkBatches(len(symbols), func(i int) string { return symbols[i] })
+	expected := [][]int{{0, 2}, {3}, {1, 4}}
+	if len(batches) != len(expected) {
+		t.Fatalf("batches %v", batches)
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if batches[i][j] != expected[i][j] {
+				t.Fatalf("batches %v", batches)
+			}
+		}
+	}
+}
+
+func TestBitMEX_PlaceOrders(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		var form map[string]string
+		json.Unmarshal(body, &form)
+		var orders []map[string]interface{}
+		if err := json.Unmarshal([]byte(form["orders"]), &orders); err != nil {
+			t.Error(err)
+		}
+		var resp []map[string]interface{}
+		for _, o := range orders {
+			status := OS_NEW
+			if o["orderQty"].(float64) > 1000 {
+				status = OS_REJECTED
+			}
+			resp = append(resp, map[string]interface{}{
+				"orderID":      o["clOrdID"],
+				"clOrdID":      o["clOrdID"],
+				"symbol":       o["symbol"],
+				"ordStatus":    status,
+				"ordRejReason": "Invalid orderQty",
+			})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	results, err := b.PlaceOrders(context.Background(), []OrderRequest{
+		{Symbol: "XBTUSD", Side: SIDE_BUY, OrderQty: 10, Price: MustPrice("3000"), ClOrdID: "a"},
+		{Symbol: "ETHUSD", Side: SIDE_BUY, OrderQty: 10, Price: MustPrice("100"), ClOrdID: "b"},
+		{Symbol: "XBTUSD", Side: SIDE_BUY, OrderQty: 5000, Price: MustPrice("3000"), ClOrdID: "c"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 requests, got %v", calls)
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		if results[i].Order.ClOrdID.Or("") != id {
+			t.Errorf("result %v: clOrdID %v", i, results[i].Order.ClOrdID.V)
+		}
+	}
+	if results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("unexpected errors %v %v", results[0].Err, results[1].Err)
+	}
+	if _, ok := results[2].Err.(*OrderRejectedError); !ok {
+		t.Errorf("expected rejection, got %v", results[2].Err)
+	}
+}
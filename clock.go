@@ -0,0 +1,137 @@
+package bitmex
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultWSAuthExpiry is how far in the future the authKeyExpires of the
+// websocket authentication lies
+var DefaultWSAuthExpiry = 412 * time.Second
+
+// ClockSample is a measurement of the server clock
+type ClockSample struct {
+	Offset  time.Duration // server time minus local time
+	Latency time.Duration // round trip of the measurement, 0 when unknown
+	At      time.Time     // local time of the measurement
+}
+
+// ClockOffset returns how far the server clock is ahead of the local clock,
+// as last measured by SyncClock or the websocket welcome message. Requests
+// are signed with expiries on the server clock.
+func (b *BitMEX) ClockOffset() time.Duration {
+	b.clockMutex.RLock()
+	defer b.clockMutex.RUnlock()
+	return b.clock.Offset
+}
+
+// ClockSample returns the last measurement of the server clock
+func (b *BitMEX) ClockSample() ClockSample {
+	b.clockMutex.RLock()
+	defer b.clockMutex.RUnlock()
+	return b.clock
+}
+
+// SyncClock measures the server clock offset with GetVersion
+func (b *BitMEX) SyncClock(ctx context.Context) error {
+	sent := time.Now()
+	version, rtt, err := b.GetVersionContext(ctx)
+	if err != nil {
+		return err
+	}
+	// the server stamped the response about halfway through the round trip
+	server := time.Unix(0, version.Timestamp*int64(time.Millisecond))
+	b.setClock(ClockSample{
+		Offset:  server.Sub(sent.Add(rtt / 2)),
+		Latency: rtt,
+		At:      sent,
+	})
+	return nil
+}
+
+// SetClockSync runs SyncClock every interval in the background; 0 stops it
+func (b *BitMEX) SetClockSync(interval time.Duration) {
+	b.clockMutex.Lock()
+	defer b.clockMutex.Unlock()
+	if b.clockStop != nil {
+		close(b.clockStop)
+		b.clockStop = nil
+	}
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	b.clockStop = stop
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+			err := b.SyncClock(ctx)
+			cancel()
+			if err != nil {
+				log.Printf("clock sync: %v", err)
+			}
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+			}
+		}
+	}()
+}
+
+// SetAuthExpiry sets how long signed REST requests and the websocket
+// authentication stay valid after they are sent. The defaults are 5s and
+// DefaultWSAuthExpiry; zero keeps the current value. REST expiries are
+// whole seconds.
+func (b *BitMEX) SetAuthExpiry(rest time.Duration, ws time.Duration) {
+	b.clockMutex.Lock()
+	defer b.clockMutex.Unlock()
+	if rest > 0 {
+		b.restAuthExpiry = (rest + time.Second - 1) / time.Second * time.Second
+	}
+	if ws > 0 {
+		b.wsAuthExpiry = ws
+	}
+}
+
+// restExpires returns the api-expires time of a REST request signed now
+func (b *BitMEX) restExpires() time.Time {
+	b.clockMutex.RLock()
+	defer b.clockMutex.RUnlock()
+	return time.Now().Add(b.clock.Offset + b.restAuthExpiry)
+}
+
+// wsExpires returns the authKeyExpires time of a websocket authentication
+// sent now
+func (b *BitMEX) wsExpires() time.Time {
+	b.clockMutex.RLock()
+	defer b.clockMutex.RUnlock()
+	return time.Now().Add(b.clock.Offset + b.wsAuthExpiry)
+}
+
+// onWelcome measures the clock offset from the timestamp of the websocket
+// welcome message, received at local time at. The one-way latency is taken
+// as half the round trip of the last REST measurement.
+func (b *BitMEX) onWelcome(timestamp string, at time.Time) {
+	server, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return
+	}
+	b.clockMutex.RLock()
+	latency := b.clock.Latency
+	b.clockMutex.RUnlock()
+	b.setClock(ClockSample{
+		Offset:  server.Add(latency / 2).Sub(at),
+		Latency: latency,
+		At:      at,
+	})
+}
+
+func (b *BitMEX) setClock(sample ClockSample) {
+	b.clockMutex.Lock()
+	defer b.clockMutex.Unlock()
+	b.clock = sample
+}
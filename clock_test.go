@@ -0,0 +1,111 @@
+package bitmex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBitMEX_SyncClock(t *testing.T) {
+	const ahead = 30 * time.Second
+	var m sync.Mutex
+	var expires string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" || r.URL.Path == "" {
+			now := time.Now().Add(ahead).UnixNano() / int64(time.Millisecond)
+			fmt.Fprintf(w, `{"name":"BitMEX API","version":"1.2.0","timestamp":%d}`, now)
+			return
+		}
+		m.Lock()
+		expires = r.Header.Get("api-expires")
+		m.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetAuthExpiry(10*time.Second, time.Minute)
+
+	if err := b.SyncClock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d := b.ClockOffset() - ahead; d < -time.Second || d > time.Second {
+		t.Fatalf("offset %v, want about %v", b.ClockOffset(), ahead)
+	}
+	if b.ClockSample().Latency <= 0 {
+		t.Error("latency not measured")
+	}
+
+	if _, err := b.GetWallet(); err != nil {
+		t.Fatal(err)
+	}
+	m.Lock()
+	got, _ := strconv.ParseInt(expires, 10, 64)
+	m.Unlock()
+	if want := time.Now().Add(ahead + 10*time.Second).Unix(); got < want-1 || got > want+1 {
+		t.Errorf("api-expires %v, want %v", got, want)
+	}
+
+	msg, err := b.getAuthMessage("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := msg.Args[1].(int64)
+	if want := time.Now().Add(ahead + time.Minute).Unix(); nonce < want-1 || nonce > want+1 {
+		t.Errorf("authKeyExpires %v, want %v", nonce, want)
+	}
+}
+
+func TestBitMEX_WelcomeClock(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	at := time.Date(2019, 4, 8, 7, 44, 0, 0, time.UTC)
+
+	resp, err := decodeMessage([]byte(`{"info":"Welcome to the BitMEX Realtime API.","version":"2019-04-05T22:50:29.000Z","timestamp":"2019-04-08T07:44:07.283Z","docs":"https://www.bitmex.com/app/wsAPI","limit":{"remaining":39}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.onWelcome(resp.Timestamp, at)
+	if want := 7283 * time.Millisecond; b.ClockOffset() != want {
+		t.Errorf("offset %v, want %v", b.ClockOffset(), want)
+	}
+
+	// a malformed timestamp keeps the last measurement
+	b.onWelcome("yesterday", at)
+	if b.ClockOffset() != 7283*time.Millisecond {
+		t.Errorf("offset %v after a bad timestamp", b.ClockOffset())
+	}
+}
+
+func TestBitMEX_SetAuthExpiryConcurrent(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 100; i++ {
+			b.SetAuthExpiry(time.Duration(i)*time.Second, time.Duration(i)*time.Minute)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			b.cfg.Expires()
+			if _, err := b.getAuthMessage("key"); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	got, _ := strconv.ParseInt(b.cfg.Expires(), 10, 64)
+	if want := time.Now().Add(100 * time.Second).Unix(); got < want-1 || got > want+1 {
+		t.Errorf("api-expires %v, want %v", got, want)
+	}
+}
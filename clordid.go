@@ -0,0 +1,177 @@
+package bitmex
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// MaxClOrdIDPrefix is the longest strategy prefix; BitMEX accepts clOrdIDs
+// of up to 36 characters
+const MaxClOrdIDPrefix = 16
+
+// OrderUnknownError is returned for an order whose submission failed
+// without telling whether BitMEX received it, and whose outcome could not be
+// resolved yet. The order stays in flight until ResolveOrder or the order
+// stream tells.
+type OrderUnknownError struct {
+	ClOrdID string
+	Symbol  string
+	Err     error // the error of the submission
+}
+
+func (e *OrderUnknownError) Error() string {
+	return fmt.Sprintf("order outcome unknown: clOrdID=%v symbol=%v: %v", e.ClOrdID, e.Symbol, e.Err)
+}
+
+func (e *OrderUnknownError) Unwrap() error {
+	return e.Err
+}
+
+// InFlightOrder is an order submitted and not yet acknowledged
+type InFlightOrder struct {
+	ClOrdID string
+	Symbol  string
+	Sent    time.Time
+}
+
+// SetClOrdIDPrefix sets the strategy prefix of generated clOrdIDs
+func (b *BitMEX) SetClOrdIDPrefix(prefix string) error {
+	if len(prefix) > MaxClOrdIDPrefix {
+		return fmt.Errorf("clOrdID prefix %q longer than %v", prefix, MaxClOrdIDPrefix)
+	}
+	b.inflightMutex.Lock()
+	defer b.inflightMutex.Unlock()
+	b.clOrdIDPrefix = prefix
+	return nil
+}
+
+// NewClOrdID returns a unique clOrdID: the strategy prefix, a random
+// session part and a sequence number
+func (b *BitMEX) NewClOrdID() string {
+	b.inflightMutex.Lock()
+	if b.clOrdIDSession == "" {
+		var buf [6]byte
+		rand.Read(buf[:])
+		b.clOrdIDSession = hex.EncodeToString(buf[:])
+	}
+	b.clOrdIDSeq++
+	id := b.clOrdIDPrefix + b.clOrdIDSession + "-" + strconv.FormatUint(b.clOrdIDSeq, 36)
+	b.inflightMutex.Unlock()
+	return id
+}
+
+// InFlightOrders returns the orders submitted and not yet acknowledged, the
+// oldest first
+func (b *BitMEX) InFlightOrders() (orders []InFlightOrder) {
+	b.inflightMutex.Lock()
+	for _, o := range b.inflight {
+		orders = append(orders, *o)
+	}
+	b.inflightMutex.Unlock()
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Sent.Before(orders[j].Sent) })
+	return
+}
+
+func (b *BitMEX) addInFlight(clOrdID string, symbol string) {
+	b.inflightMutex.Lock()
+	defer b.inflightMutex.Unlock()
+	b.inflight[clOrdID] = &InFlightOrder{ClOrdID: clOrdID, Symbol: symbol, Sent: time.Now()}
+}
+
+func (b *BitMEX) removeInFlight(clOrdID string) {
+	b.inflightMutex.Lock()
+	defer b.inflightMutex.Unlock()
+	delete(b.inflight, clOrdID)
+}
+
+// OrderByClOrdID returns the latest state of an order seen on the order
+// stream
+func (b *BitMEX) OrderByClOrdID(clOrdID string) (order swagger.Order, ok bool) {
+	b.orderLocalsMutex.RLock()
+	defer b.orderLocalsMutex.RUnlock()
+	orderID, ok := b.orderClOrdIDs[clOrdID]
+	if !ok {
+		return
+	}
+	o, ok := b.orderLocals[orderID]
+	if ok {
+		order = o.Swagger()
+	}
+	return
+}
+
+// ResolveOrder tells whether the order of clOrdID reached BitMEX, from the
+// order stream or else GetOrderByClOrdID. It returns NotFound for an order
+// that didn't; either answer removes the order from the in-flight registry.
+func (b *BitMEX) ResolveOrder(clOrdID string, symbol string) (order swagger.Order, err error) {
+	return b.ResolveOrderContext(context.Background(), clOrdID, symbol)
+}
+
+// ResolveOrderContext is like ResolveOrder but takes a context
+func (b *BitMEX) ResolveOrderContext(ctx context.Context, clOrdID string, symbol string) (order swagger.Order, err error) {
+	order, ok := b.OrderByClOrdID(clOrdID)
+	if !ok {
+		order, err = b.GetOrderByClOrdIDContext(ctx, clOrdID, symbol)
+	}
+	if err == nil || err == NotFound {
+		b.removeInFlight(clOrdID)
+	}
+	return
+}
+
+// submitOrder sends a new order with a clOrdID, generated when params has
+// none, and resolves an ambiguous failure by the clOrdID
+func (b *BitMEX) submitOrder(ctx context.Context, symbol string, params map[string]interface{}) (order swagger.Order, err error) {
+	var response *http.Response
+
+	clOrdID, _ := params["clOrdID"].(string)
+	if clOrdID == "" {
+		clOrdID = b.NewClOrdID()
+		params["clOrdID"] = clOrdID
+	}
+	b.addInFlight(clOrdID, symbol)
+
+	order, response, err = b.client.OrderApi.OrderNew(ctx, symbol, params)
+	if err == nil {
+		b.removeInFlight(clOrdID)
+		b.onResponse(response)
+		return
+	}
+	if !ambiguous(err) {
+		b.removeInFlight(clOrdID)
+		return
+	}
+	return b.resolveFailed(ctx, clOrdID, symbol, err)
+}
+
+// resolveFailed resolves an order whose submission failed with err. The
+// lookup doesn't share the deadline of ctx, which may be what failed.
+func (b *BitMEX) resolveFailed(ctx context.Context, clOrdID string, symbol string, err error) (swagger.Order, error) {
+	order, e := b.ResolveOrderContext(detach(ctx), clOrdID, symbol)
+	switch {
+	case e == nil:
+		return order, nil
+	case e == NotFound:
+		return order, err
+	}
+	return order, &OrderUnknownError{ClOrdID: clOrdID, Symbol: symbol, Err: err}
+}
+
+// ambiguous reports whether a failed request may have been processed
+func ambiguous(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 && apiErr.StatusCode != http.StatusServiceUnavailable
+	}
+	var rateErr *RateLimitError
+	return !errors.As(err, &rateErr) && !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrAmendSuperseded)
+}
@@ -0,0 +1,163 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestBitMEX_NewClOrdID(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	if err := b.SetClOrdIDPrefix(strings.Repeat("x", MaxClOrdIDPrefix+1)); err == nil {
+		t.Error("long prefix accepted")
+	}
+	if err := b.SetClOrdIDPrefix(strings.Repeat("x", MaxClOrdIDPrefix)); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for i := 0; i < 100000; i++ {
+		id := b.NewClOrdID()
+		if seen[id] || !strings.HasPrefix(id, strings.Repeat("x", MaxClOrdIDPrefix)) || len(id) > 36 {
+			t.Fatalf("clOrdID %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+// clOrdIDServer fails new orders with failStatus, placing them when place
+// is set, and fails lookups with lookupStatus
+type clOrdIDServer struct {
+	m            sync.Mutex
+	failStatus   int
+	place        bool
+	lookupStatus int
+	placed       []swagger.Order
+}
+
+func (s *clOrdIDServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/order":
+		var form map[string]interface{}
+		json.Unmarshal(body, &form)
+		order := swagger.Order{OrderID: "o" + form["clOrdID"].(string), ClOrdID: form["clOrdID"].(string), Symbol: "XBTUSD", OrdStatus: OS_NEW}
+		if s.failStatus == 0 || s.place {
+			s.placed = append(s.placed, order)
+		}
+		if s.failStatus != 0 {
+			w.WriteHeader(s.failStatus)
+			return
+		}
+		json.NewEncoder(w).Encode(order)
+	case r.Method == http.MethodPost && r.URL.Path == "/order/bulk":
+		w.WriteHeader(s.failStatus)
+	case r.Method == http.MethodGet && r.URL.Path == "/order":
+		if s.lookupStatus != 0 {
+			w.WriteHeader(s.lookupStatus)
+			return
+		}
+		orders := []swagger.Order{}
+		for _, o := range s.placed {
+			if strings.Contains(r.URL.Query().Get("filter"), `"`+o.ClOrdID+`"`) {
+				orders = append(orders, o)
+			}
+		}
+		json.NewEncoder(w).Encode(orders)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestBitMEX_SubmitOrder(t *testing.T) {
+	s := &clOrdIDServer{}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetRetryPolicy(RetryPolicy{})
+	b.SetClOrdIDPrefix("mm1")
+
+	order, err := b.NewOrder(SIDE_BUY, ORD_TYPE_LIMIT, MustPrice("5000"), 10, false, "", "XBTUSD")
+	if err != nil || !strings.HasPrefix(order.ClOrdID, "mm1") {
+		t.Fatalf("order %+v err %v", order, err)
+	}
+
+	// placed, answered 502: resolved by clOrdID
+	s.failStatus, s.place = 502, true
+	order, err = b.PlaceOrder(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, "", 0, "XBTUSD")
+	if err != nil || order.OrderID == "" {
+		t.Fatalf("order %+v err %v", order, err)
+	}
+
+	// not placed: the 502 is returned
+	s.place = false
+	var apiErr *APIError
+	if _, err = b.PlaceOrder(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, "", 0, "XBTUSD"); !errors.As(err, &apiErr) || apiErr.StatusCode != 502 {
+		t.Fatalf("got %v, want 502", err)
+	}
+	// a 400 is not looked up
+	s.failStatus = 400
+	if _, err = b.PlaceOrder(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, "", 0, "XBTUSD"); !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Fatalf("got %v, want 400", err)
+	}
+	if n := len(b.InFlightOrders()); n != 0 {
+		t.Errorf("%v orders in flight", n)
+	}
+
+	// unresolved: in flight until the order stream tells
+	s.failStatus, s.place, s.lookupStatus = 502, true, 500
+	_, err = b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, -1, "", 0, "XBTUSD", "mine", "")
+	var unknown *OrderUnknownError
+	if !errors.As(err, &unknown) || unknown.ClOrdID != "mine" || !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want *OrderUnknownError", err)
+	}
+	if inflight := b.InFlightOrders(); len(inflight) != 1 || inflight[0].ClOrdID != "mine" {
+		t.Fatalf("in flight %+v", inflight)
+	}
+	b.processOrder(orderMessage(bitmexActionInsertData, &swagger.Order{OrderID: "omine", ClOrdID: "mine", Symbol: "XBTUSD"}))
+	if n := len(b.InFlightOrders()); n != 0 {
+		t.Errorf("%v orders in flight", n)
+	}
+	if order, ok := b.OrderByClOrdID("mine"); !ok || order.OrderID != "omine" {
+		t.Errorf("cached %+v", order)
+	}
+}
+
+func TestBitMEX_PlaceOrdersUnknown(t *testing.T) {
+	s := &clOrdIDServer{failStatus: 504}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetRetryPolicy(RetryPolicy{})
+
+	// the first order is known from the stream, the other one is not found
+	b.processOrder(orderMessage(bitmexActionInsertData, &swagger.Order{OrderID: "o1", ClOrdID: "a", Symbol: "XBTUSD", OrdStatus: OS_NEW}))
+	results, err := b.PlaceOrders(nil, []OrderRequest{
+		{Symbol: "XBTUSD", Side: SideBuy, OrdType: OrdTypeLimit, OrderQty: 10, Price: MustPrice("5000"), ClOrdID: "a"},
+		{Symbol: "XBTUSD", Side: SideBuy, OrdType: OrdTypeLimit, OrderQty: 10, Price: MustPrice("4000")},
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 504 {
+		t.Fatalf("got %v, want 504", err)
+	}
+	if results[0].Err != nil || results[0].Order.OrderID != "o1" {
+		t.Errorf("first result %+v", results[0])
+	}
+	if results[1].Err != err {
+		t.Errorf("second result %+v", results[1])
+	}
+	if n := len(b.InFlightOrders()); n != 0 {
+		t.Errorf("%v orders in flight", n)
+	}
+}
@@ -0,0 +1,92 @@
+package bitmex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestBitMEX_Context(t *testing.T) {
+	var m sync.Mutex
+	headers := map[string]http.Header{}
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		headers[r.URL.Path] = r.Header.Clone()
+		m.Unlock()
+		switch r.URL.Path {
+		case "/order":
+			// hangs until the client gives up
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		case "/trade/bucketed":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"Invalid orderID","name":"HTTPError"}}`))
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := b.CancelOrderContext(ctx, "a"); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want canceled", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := b.GetOrdersRawContext(ctx, "XBTUSD", ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want deadline exceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("deadline took %v", d)
+	}
+
+	// credentials come from b, the trace ID from the context
+	ctx = WithTraceID(context.Background(), "trace-1")
+	_, err := b.PositionUpdateLeverageContext(ctx, 10, "XBTUSD")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.TraceID != "trace-1" {
+		t.Fatalf("got %v, want an APIError of trace-1", err)
+	}
+	if _, err = b.GetBucketedContext(ctx, "XBTUSD", "1m", false, "", "", 0, -1, false, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	private, public := headers["/position/leverage"], headers["/trade/bucketed"]
+	if private.Get("api-key") != "key" || private.Get(swagger.TraceIDHeader) != "trace-1" {
+		t.Errorf("private request headers %v", private)
+	}
+	if public.Get("api-key") != "" || public.Get(swagger.TraceIDHeader) != "trace-1" {
+		t.Errorf("public request headers %v", public)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	ctx, cancel := context.WithCancel(WithTraceID(b.authContext(context.Background()), "t"))
+	cancel()
+	detached := detach(ctx)
+	if detached.Err() != nil || TraceID(detached) != "t" {
+		t.Errorf("detached context err %v trace %q", detached.Err(), TraceID(detached))
+	}
+	if _, ok := detached.Value(swagger.ContextAPIKey).(swagger.APIKey); !ok {
+		t.Error("credentials lost")
+	}
+}
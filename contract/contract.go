@@ -0,0 +1,133 @@
+// Package contract computes values and PnL of BitMEX contracts.
+//
+// Values are in the base units of the settlement currency (XBt for XBT
+// settled contracts) and follow the sign convention of the exchange, so that
+// Value at the entry price matches Position.PosCost and at the mark price
+// matches Position.MarkValue. The multiplier of an inverse contract is
+// negative, which makes the value of an inverse long negative.
+//
+// The formulas per contract type are:
+//
+//	inverse (XBTUSD):        value = multiplier * qty / price
+//	quanto (ETHUSD):         value = multiplier * qty * price
+//	linear (futures in XBT): value = multiplier * qty * price
+package contract
+
+import (
+	"math"
+
+	bitmex "github.com/sumorf/bitmex-api"
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// Contract holds the instrument fields the contract math depends on
+type Contract struct {
+	Symbol        string
+	IsInverse     bool
+	IsQuanto      bool
+	Multiplier    float64 // settlement units per contract and price unit
+	SettlCurrency string  // XBt, USDt, ...
+	QuoteCurrency string
+}
+
+// FromInstrument returns the contract of an instrument record
+func FromInstrument(v *swagger.Instrument) Contract {
+	return Contract{
+		Symbol:        v.Symbol,
+		IsInverse:     v.IsInverse,
+		IsQuanto:      v.IsQuanto,
+		Multiplier:    float64(v.Multiplier),
+		SettlCurrency: v.SettlCurrency,
+		QuoteCurrency: v.QuoteCurrency,
+	}
+}
+
+// FromSpec returns the contract of an instrument from the registry
+func FromSpec(s bitmex.InstrumentSpec) Contract {
+	return Contract{
+		Symbol:        s.Symbol,
+		IsInverse:     s.IsInverse,
+		IsQuanto:      s.IsQuanto,
+		Multiplier:    s.Multiplier,
+		SettlCurrency: s.SettlCurrency,
+		QuoteCurrency: s.QuoteCurrency,
+	}
+}
+
+// Value returns the signed value of qty contracts at price, in settlement
+// units. qty is negative for a short position.
+func (c Contract) Value(qty int64, price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	if c.IsInverse {
+		return c.Multiplier * float64(qty) / price
+	}
+	return c.Multiplier * float64(qty) * price
+}
+
+// Notional returns the absolute value of qty contracts at price, in
+// settlement units
+func (c Contract) Notional(qty int64, price float64) float64 {
+	return math.Abs(c.Value(qty, price))
+}
+
+// NotionalUSD converts Notional to USD. settlPrice is the USD price of one
+// whole settlement currency unit, e.g. the XBTUSD price for XBt.
+func (c Contract) NotionalUSD(qty int64, price float64, settlPrice float64) float64 {
+	return c.Notional(qty, price) / c.settlScale() * settlPrice
+}
+
+// UnrealisedPnl returns the PnL of a position of qty contracts entered at
+// entryPrice and marked at markPrice, in settlement units
+func (c Contract) UnrealisedPnl(qty int64, entryPrice float64, markPrice float64) float64 {
+	return c.Value(qty, markPrice) - c.Value(qty, entryPrice)
+}
+
+// RealisedPnl returns the PnL of closing qty contracts of a position entered
+// at entryPrice at exitPrice, minus the fees paid on both trades. feeRate is
+// the taker or maker fee of the instrument; a negative rate is a rebate.
+func (c Contract) RealisedPnl(qty int64, entryPrice float64, exitPrice float64, feeRate float64) float64 {
+	fees := c.Fee(qty, entryPrice, feeRate) + c.Fee(qty, exitPrice, feeRate)
+	return c.UnrealisedPnl(qty, entryPrice, exitPrice) - fees
+}
+
+// Fee returns the fee for trading qty contracts at price
+func (c Contract) Fee(qty int64, price float64, feeRate float64) float64 {
+	return c.Notional(qty, price) * feeRate
+}
+
+// BreakEven returns the exit price at which a position of qty contracts
+// entered at entryPrice has zero PnL after paying feeRate on entry and exit.
+// It is the same for every contract type: value is linear in price or in its
+// inverse, and the fee is proportional to value.
+func (c Contract) BreakEven(qty int64, entryPrice float64, feeRate float64) float64 {
+	if qty < 0 {
+		return entryPrice * (1 - feeRate) / (1 + feeRate)
+	}
+	return entryPrice * (1 + feeRate) / (1 - feeRate)
+}
+
+// EntryPrice returns the average entry price of a position from its cost,
+// the inverse of Value
+func (c Contract) EntryPrice(qty int64, cost float64) float64 {
+	if qty == 0 || cost == 0 || c.Multiplier == 0 {
+		return 0
+	}
+	if c.IsInverse {
+		return c.Multiplier * float64(qty) / cost
+	}
+	return cost / (c.Multiplier * float64(qty))
+}
+
+func (c Contract) settlScale() float64 {
+	switch c.SettlCurrency {
+	case "XBt":
+		return 1e8
+	case "USDt":
+		return 1e6
+	case "Gwei":
+		return 1e9
+	}
+	return 1
+}
@@ -0,0 +1,90 @@
+package contract
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+var (
+	xbtusd = Contract{Symbol: "XBTUSD", IsInverse: true, Multiplier: -100000000, SettlCurrency: "XBt", QuoteCurrency: "USD"}
+	ethusd = Contract{Symbol: "ETHUSD", IsQuanto: true, Multiplier: 100, SettlCurrency: "XBt", QuoteCurrency: "USD"}
+	ethxbt = Contract{Symbol: "ETHM19", Multiplier: 100000000, SettlCurrency: "XBt", QuoteCurrency: "XBT"}
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6*math.Max(1, math.Abs(b))
+}
+
+func TestContract_Value(t *testing.T) {
+	tests := []struct {
+		c     Contract
+		qty   int64
+		price float64
+		value float64
+	}{
+		{xbtusd, 10000, 10000, -100000000}, // $10000 long is 1 XBT
+		{xbtusd, -10000, 5000, 200000000},
+		{ethusd, 1, 200, 20000}, // 100 XBt per $1
+		{ethxbt, 2, 0.03, 6000000},
+	}
+	for _, tt := range tests {
+		if v := tt.c.Value(tt.qty, tt.price); !near(v, tt.value) {
+			t.Errorf("%v Value(%v, %v) = %v, want %v", tt.c.Symbol, tt.qty, tt.price, v, tt.value)
+		}
+		if p := tt.c.EntryPrice(tt.qty, tt.value); !near(p, tt.price) {
+			t.Errorf("%v EntryPrice = %v, want %v", tt.c.Symbol, p, tt.price)
+		}
+	}
+}
+
+func TestContract_Pnl(t *testing.T) {
+	// long $10000 from 10000 to 12500: 1 XBT -> 0.8 XBT worth of contracts
+	if pnl := xbtusd.UnrealisedPnl(10000, 10000, 12500); !near(pnl, 20000000) {
+		t.Errorf("XBTUSD long pnl %v", pnl)
+	}
+	if pnl := xbtusd.UnrealisedPnl(-10000, 10000, 12500); !near(pnl, -20000000) {
+		t.Errorf("XBTUSD short pnl %v", pnl)
+	}
+	// quanto pnl is linear in the ETH price whatever the XBT price
+	if pnl := ethusd.UnrealisedPnl(100, 200, 210); !near(pnl, 100000) {
+		t.Errorf("ETHUSD pnl %v", pnl)
+	}
+	fee := 0.00075
+	pnl := ethusd.RealisedPnl(100, 200, 210, fee)
+	if want := 100000 - (2000000+2100000)*fee; !near(pnl, want) {
+		t.Errorf("ETHUSD realised pnl %v, want %v", pnl, want)
+	}
+}
+
+func TestContract_BreakEven(t *testing.T) {
+	fee := 0.00075
+	for _, c := range []Contract{xbtusd, ethusd, ethxbt} {
+		for _, qty := range []int64{100, -100} {
+			entry := 200.0
+			be := c.BreakEven(qty, entry, fee)
+			pnl := c.RealisedPnl(qty, entry, be, fee)
+			if math.Abs(pnl) > 1e-9*c.Notional(qty, entry) {
+				t.Errorf("%v qty=%v break-even %v leaves pnl %v", c.Symbol, qty, be, pnl)
+			}
+		}
+	}
+}
+
+func TestContract_NotionalUSD(t *testing.T) {
+	if usd := xbtusd.NotionalUSD(-500, 8000, 8000); !near(usd, 500) {
+		t.Errorf("XBTUSD notional %v", usd)
+	}
+	// 1 ETHUSD contract at $200 is 20000 XBt, $2 at XBT=$10000
+	if usd := ethusd.NotionalUSD(1, 200, 10000); !near(usd, 2) {
+		t.Errorf("ETHUSD notional %v", usd)
+	}
+}
+
+func TestFromInstrument(t *testing.T) {
+	c := FromInstrument(&swagger.Instrument{Symbol: "XBTUSD", IsInverse: true, Multiplier: -100000000, SettlCurrency: "XBt"})
+	if c.Value(1, 1) != -100000000 {
+		t.Errorf("contract %#v", c)
+	}
+}
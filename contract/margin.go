@@ -0,0 +1,196 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	bitmex "github.com/sumorf/bitmex-api"
+)
+
+var (
+	ErrLeverageTooHigh   = errors.New("contract: leverage above the maximum of the risk limit")
+	ErrRiskLimitExceeded = errors.New("contract: position value above the risk limit")
+	ErrNoMargin          = errors.New("contract: cross margin position needs a margin balance")
+	ErrNoStepMargin      = errors.New("contract: risk limit above the base needs a step margin")
+)
+
+// RiskModel holds the margin requirements of an instrument. Every RiskStep
+// above the base RiskLimit adds StepMargin to both margin rates; XBTUSD adds
+// 0.5% per step of 100 XBT. The instrument does not publish the step rate, so
+// it must be set to use a risk limit above the base.
+type RiskModel struct {
+	Contract Contract
+
+	RiskLimit   float64 // base risk limit, settlement units
+	RiskStep    float64 // settlement units
+	InitMargin  float64 // base initial margin rate
+	MaintMargin float64 // base maintenance margin rate
+	StepMargin  float64 // rate added per step, required above the base risk limit
+	TakerFee    float64 // fee to close the position, part of the maintenance margin
+}
+
+// NewRiskModel returns the risk model of an instrument from the registry.
+// StepMargin is left for the caller to set.
+func NewRiskModel(s bitmex.InstrumentSpec) RiskModel {
+	return RiskModel{
+		Contract:    FromSpec(s),
+		RiskLimit:   s.RiskLimit,
+		RiskStep:    s.RiskStep,
+		InitMargin:  s.InitMargin,
+		MaintMargin: s.MaintMargin,
+		TakerFee:    s.TakerFee,
+	}
+}
+
+// PositionParams describes a position to evaluate
+type PositionParams struct {
+	Qty        int64   // negative for short
+	EntryPrice float64 // average entry price
+
+	// Leverage of an isolated position. Zero means cross margin, where the
+	// whole Margin balance backs the position.
+	Leverage float64
+	Margin   float64 // cross margin balance, settlement units
+
+	// RiskLimit selected for the position, the base risk limit when zero
+	RiskLimit float64
+}
+
+// MarginResult is the margin requirement of a position. Amounts are in
+// settlement units.
+type MarginResult struct {
+	Value           float64 // notional at the entry price
+	InitMarginRate  float64
+	MaintMarginRate float64
+	InitMargin      float64
+	MaintMargin     float64 // including the fee to close
+	Margin          float64 // margin backing the position
+	RiskLimit       float64
+
+	// LiquidationPrice is where the margin falls to the maintenance margin,
+	// BankruptcyPrice where it is lost entirely. Zero when never reached.
+	LiquidationPrice float64
+	BankruptcyPrice  float64
+}
+
+// Distance returns how far the liquidation price is from mark, as a fraction
+// of mark. It is +Inf when the position cannot be liquidated.
+func (r MarginResult) Distance(mark float64) float64 {
+	if r.LiquidationPrice <= 0 || mark <= 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(mark-r.LiquidationPrice) / mark
+}
+
+// LiquidationTooCloseError is returned by CheckOrder
+type LiquidationTooCloseError struct {
+	LiquidationPrice float64
+	MarkPrice        float64
+	Distance         float64
+}
+
+func (e *LiquidationTooCloseError) Error() string {
+	return fmt.Sprintf("contract: liquidation price %v within %.2f%% of mark %v", e.LiquidationPrice, e.Distance*100, e.MarkPrice)
+}
+
+// Calculate returns the margin requirement, liquidation and bankruptcy
+// prices of a position
+func (m RiskModel) Calculate(pos PositionParams) (r MarginResult, err error) {
+	c := m.Contract
+	r.Value = c.Notional(pos.Qty, pos.EntryPrice)
+	r.RiskLimit = pos.RiskLimit
+	if r.RiskLimit < m.RiskLimit {
+		r.RiskLimit = m.RiskLimit
+	}
+	if r.RiskLimit > 0 && r.Value > r.RiskLimit {
+		err = ErrRiskLimitExceeded
+		return
+	}
+
+	var steps float64
+	if m.RiskStep > 0 && r.RiskLimit > m.RiskLimit {
+		steps = math.Ceil((r.RiskLimit - m.RiskLimit) / m.RiskStep)
+	}
+	if steps > 0 && m.StepMargin <= 0 {
+		err = ErrNoStepMargin
+		return
+	}
+	r.InitMarginRate = m.InitMargin + steps*m.StepMargin
+	r.MaintMarginRate = m.MaintMargin + steps*m.StepMargin
+	r.InitMargin = r.Value * r.InitMarginRate
+	r.MaintMargin = r.Value * (r.MaintMarginRate + m.TakerFee)
+
+	if pos.Leverage > 0 {
+		if 1/pos.Leverage < r.InitMarginRate {
+			err = ErrLeverageTooHigh
+			return
+		}
+		r.Margin = r.Value / pos.Leverage
+	} else {
+		if pos.Margin <= 0 {
+			err = ErrNoMargin
+			return
+		}
+		r.Margin = pos.Margin
+	}
+	if pos.Qty == 0 {
+		return
+	}
+
+	cost := c.Value(pos.Qty, pos.EntryPrice)
+	r.BankruptcyPrice = m.priceAtLoss(pos.Qty, cost, r.Margin)
+	r.LiquidationPrice = m.priceAtLoss(pos.Qty, cost, r.Margin-r.MaintMargin)
+	return
+}
+
+// WhatIf returns the margin requirement of the position after an order of
+// qty contracts (negative to sell) fills at price
+func (m RiskModel) WhatIf(pos PositionParams, qty int64, price float64) (MarginResult, error) {
+	c := m.Contract
+	next := pos
+	next.Qty = pos.Qty + qty
+	switch {
+	case next.Qty == 0:
+		next.EntryPrice = 0
+	case pos.Qty == 0 || (pos.Qty > 0) != (next.Qty > 0):
+		// opened or flipped: the remainder was entered at price
+		next.EntryPrice = price
+	case (pos.Qty > 0) == (qty > 0):
+		// increased: average the cost
+		cost := c.Value(pos.Qty, pos.EntryPrice) + c.Value(qty, price)
+		next.EntryPrice = c.EntryPrice(next.Qty, cost)
+	}
+	return m.Calculate(next)
+}
+
+// CheckOrder evaluates an order with WhatIf and fails with a
+// *LiquidationTooCloseError when the resulting liquidation price is within
+// minDistance (a fraction, 0.1 = 10%) of mark
+func (m RiskModel) CheckOrder(pos PositionParams, qty int64, price float64, mark float64, minDistance float64) (r MarginResult, err error) {
+	r, err = m.WhatIf(pos, qty, price)
+	if err != nil {
+		return
+	}
+	if d := r.Distance(mark); d < minDistance {
+		err = &LiquidationTooCloseError{LiquidationPrice: r.LiquidationPrice, MarkPrice: mark, Distance: d}
+	}
+	return
+}
+
+// priceAtLoss returns the price where a position of qty contracts with the
+// given cost has lost loss, or 0 when no price is low (high) enough
+func (m RiskModel) priceAtLoss(qty int64, cost float64, loss float64) float64 {
+	value := cost - loss
+	if m.Contract.IsInverse {
+		// value = multiplier * qty / price keeps the sign of multiplier * qty
+		if value == 0 || (value > 0) != (m.Contract.Multiplier*float64(qty) > 0) {
+			return 0
+		}
+	}
+	p := m.Contract.EntryPrice(qty, value)
+	if p <= 0 || math.IsInf(p, 0) {
+		return 0
+	}
+	return p
+}
@@ -0,0 +1,134 @@
+package contract
+
+import (
+	"math"
+	"testing"
+)
+
+var xbtusdRisk = RiskModel{
+	Contract:    xbtusd,
+	RiskLimit:   20000000000, // 200 XBT
+	RiskStep:    10000000000,
+	InitMargin:  0.01,
+	MaintMargin: 0.005,
+	StepMargin:  0.005,
+	TakerFee:    0.00075,
+}
+
+func TestRiskModel_Calculate(t *testing.T) {
+	// 10x long $10000 at 10000: 1 XBT of value, 0.1 XBT of margin
+	r, err := xbtusdRisk.Calculate(PositionParams{Qty: 10000, EntryPrice: 10000, Leverage: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(r.Margin, 10000000) || !near(r.InitMargin, 1000000) {
+		t.Errorf("margin %v init %v", r.Margin, r.InitMargin)
+	}
+	// bankrupt when 1/p - 1/10000 = 0.1/10000
+	if !near(r.BankruptcyPrice, 10000/1.1) {
+		t.Errorf("bankruptcy %v", r.BankruptcyPrice)
+	}
+	if !(r.LiquidationPrice > r.BankruptcyPrice && r.LiquidationPrice < 10000) {
+		t.Errorf("liquidation %v", r.LiquidationPrice)
+	}
+	// the loss at liquidation leaves the maintenance margin
+	loss := -xbtusd.UnrealisedPnl(10000, 10000, r.LiquidationPrice)
+	if !near(r.Margin-loss, r.MaintMargin) {
+		t.Errorf("margin left %v, maintenance %v", r.Margin-loss, r.MaintMargin)
+	}
+
+	short, _ := xbtusdRisk.Calculate(PositionParams{Qty: -10000, EntryPrice: 10000, Leverage: 10})
+	if !near(short.BankruptcyPrice, 10000/0.9) || short.LiquidationPrice >= short.BankruptcyPrice {
+		t.Errorf("short bankruptcy %v liquidation %v", short.BankruptcyPrice, short.LiquidationPrice)
+	}
+
+	// an inverse 1x long is bankrupt at half the entry price
+	oneX, _ := xbtusdRisk.Calculate(PositionParams{Qty: 10000, EntryPrice: 10000, Leverage: 1})
+	if !near(oneX.BankruptcyPrice, 5000) {
+		t.Errorf("1x long bankruptcy %v", oneX.BankruptcyPrice)
+	}
+	// while a 1x short never is
+	oneX, _ = xbtusdRisk.Calculate(PositionParams{Qty: -10000, EntryPrice: 10000, Leverage: 1})
+	if oneX.BankruptcyPrice != 0 || oneX.LiquidationPrice < 100000 {
+		t.Errorf("1x short bankruptcy %v liquidation %v", oneX.BankruptcyPrice, oneX.LiquidationPrice)
+	}
+
+	// a linear long backed by more than its value cannot be liquidated
+	linear := RiskModel{Contract: ethxbt, InitMargin: 0.02, MaintMargin: 0.01}
+	r, _ = linear.Calculate(PositionParams{Qty: 2, EntryPrice: 0.03, Margin: 100000000})
+	if r.LiquidationPrice != 0 || !math.IsInf(r.Distance(0.03), 1) {
+		t.Errorf("linear liquidation %v", r.LiquidationPrice)
+	}
+}
+
+func TestRiskModel_RiskLimitTiers(t *testing.T) {
+	// the XBTUSD risk limit table published by BitMEX
+	tiers := []struct {
+		limit       float64 // XBT
+		init, maint float64
+	}{
+		{200, 0.01, 0.005},
+		{300, 0.015, 0.01},
+		{400, 0.02, 0.015},
+		{500, 0.025, 0.02},
+		{1000, 0.05, 0.045},
+	}
+	for _, tier := range tiers {
+		r, err := xbtusdRisk.Calculate(PositionParams{Qty: 10000, EntryPrice: 10000, Leverage: 1, RiskLimit: tier.limit * 1e8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !near(r.InitMarginRate, tier.init) || !near(r.MaintMarginRate, tier.maint) {
+			t.Errorf("%v XBT: rates %v %v, want %v %v", tier.limit, r.InitMarginRate, r.MaintMarginRate, tier.init, tier.maint)
+		}
+	}
+
+	noStep := xbtusdRisk
+	noStep.StepMargin = 0
+	if _, err := noStep.Calculate(PositionParams{Qty: 10000, EntryPrice: 10000, Leverage: 1, RiskLimit: 300e8}); err != ErrNoStepMargin {
+		t.Errorf("got %v, want ErrNoStepMargin", err)
+	}
+	if _, err := noStep.Calculate(PositionParams{Qty: 10000, EntryPrice: 10000, Leverage: 1}); err != nil {
+		t.Errorf("base risk limit: %v", err)
+	}
+}
+
+func TestRiskModel_RiskLimit(t *testing.T) {
+	pos := PositionParams{Qty: 3000000, EntryPrice: 10000, Leverage: 100}
+	if _, err := xbtusdRisk.Calculate(pos); err != ErrRiskLimitExceeded {
+		t.Errorf("300 XBT under a 200 XBT limit: %v", err)
+	}
+	pos.RiskLimit = 30000000000
+	if _, err := xbtusdRisk.Calculate(pos); err != ErrLeverageTooHigh {
+		t.Errorf("100x at 1.5%% initial margin: %v", err)
+	}
+	pos.Leverage = 25
+	r, err := xbtusdRisk.Calculate(pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(r.InitMarginRate, 0.015) || !near(r.MaintMarginRate, 0.01) {
+		t.Errorf("rates %v %v", r.InitMarginRate, r.MaintMarginRate)
+	}
+}
+
+func TestRiskModel_WhatIf(t *testing.T) {
+	pos := PositionParams{Qty: 10000, EntryPrice: 10000, Margin: 5000000}
+	r, err := xbtusdRisk.WhatIf(pos, 10000, 12500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1 XBT + 0.8 XBT of cost for $20000
+	if want := xbtusd.EntryPrice(20000, -180000000); !near(r.Value, 180000000) || want <= 10000 || want >= 12500 {
+		t.Errorf("value %v entry %v", r.Value, want)
+	}
+
+	if _, err := xbtusdRisk.CheckOrder(pos, 10000, 10000, 10000, 0.05); err == nil {
+		t.Error("2x cross margin 20x long within 5% of liquidation accepted")
+	} else if _, ok := err.(*LiquidationTooCloseError); !ok {
+		t.Errorf("error %v", err)
+	}
+	if _, err := xbtusdRisk.CheckOrder(pos, -5000, 10000, 10000, 0.01); err != nil {
+		t.Errorf("reducing order refused: %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Side is the side of an order
+type Side string
+
+const (
+	SideBuy  Side = SIDE_BUY
+	SideSell Side = SIDE_SELL
+)
+
+// Valid reports whether s is a known side
+func (s Side) Valid() bool {
+	return s == SideBuy || s == SideSell
+}
+
+// Opposite returns the other side
+func (s Side) Opposite() Side {
+	if s == SideBuy {
+		return SideSell
+	}
+	return SideBuy
+}
+
+// OrdType is the type of an order
+type OrdType string
+
+const (
+	OrdTypeMarket                    OrdType = ORD_TYPE_MARKET
+	OrdTypeLimit                     OrdType = ORD_TYPE_LIMIT
+	OrdTypeStop                      OrdType = ORD_TYPE_STOP
+	OrdTypeStopLimit                 OrdType = ORD_TYPE_STOP_LIMIT
+	OrdTypeMarketIfTouched           OrdType = ORD_TYPE_MARKET_IF_TOUCHED
+	OrdTypeLimitIfTouched            OrdType = ORD_TYPE_LIMIT_IF_TOUCHED
+	OrdTypeMarketWithLeftOverAsLimit OrdType = ORD_TYPE_MARKET_WITH_LEFT_OVER_AS_LIMIT
+	OrdTypePegged                    OrdType = "Pegged"
+)
+
+// Valid reports whether t is a known order type
+func (t OrdType) Valid() bool {
+	switch t {
+	case OrdTypeMarket, OrdTypeLimit, OrdTypeStop, OrdTypeStopLimit, OrdTypeMarketIfTouched,
+		OrdTypeLimitIfTouched, OrdTypeMarketWithLeftOverAsLimit, OrdTypePegged:
+		return true
+	}
+	return false
+}
+
+// IsConditional reports whether orders of type t wait for a trigger price
+func (t OrdType) IsConditional() bool {
+	switch t {
+	case OrdTypeStop, OrdTypeStopLimit, OrdTypeMarketIfTouched, OrdTypeLimitIfTouched:
+		return true
+	}
+	return false
+}
+
+// HasPrice reports whether orders of type t carry a limit price
+func (t OrdType) HasPrice() bool {
+	switch t {
+	case OrdTypeLimit, OrdTypeStopLimit, OrdTypeLimitIfTouched, OrdTypePegged:
+		return true
+	}
+	return false
+}
+
+// TimeInForce is the time in force of an order
+type TimeInForce string
+
+const (
+	TimeInForceDay               TimeInForce = "Day"
+	TimeInForceGoodTillCancel    TimeInForce = "GoodTillCancel"
+	TimeInForceImmediateOrCancel TimeInForce = "ImmediateOrCancel"
+	TimeInForceFillOrKill        TimeInForce = "FillOrKill"
+)
+
+// Valid reports whether t is a known time in force
+func (t TimeInForce) Valid() bool {
+	switch t {
+	case TimeInForceDay, TimeInForceGoodTillCancel, TimeInForceImmediateOrCancel, TimeInForceFillOrKill:
+		return true
+	}
+	return false
+}
+
+// OrdStatus is the status of an order
+type OrdStatus string
+
+const (
+	OrdStatusNew             OrdStatus = OS_NEW
+	OrdStatusPartiallyFilled OrdStatus = OS_PARTIALLY_FILLED
+	OrdStatusFilled          OrdStatus = OS_FILLED
+	OrdStatusCanceled        OrdStatus = OS_CANCELED
+	OrdStatusRejected        OrdStatus = OS_REJECTED
+	OrdStatusPendingNew      OrdStatus = "PendingNew"
+	OrdStatusPendingCancel   OrdStatus = "PendingCancel"
+	OrdStatusDoneForDay      OrdStatus = "DoneForDay"
+	OrdStatusExpired         OrdStatus = "Expired"
+	OrdStatusStopped         OrdStatus = "Stopped"
+)
+
+// IsOpen reports whether an order with status s may still trade
+func (s OrdStatus) IsOpen() bool {
+	switch s {
+	case OrdStatusNew, OrdStatusPartiallyFilled, OrdStatusPendingNew, OrdStatusPendingCancel:
+		return true
+	}
+	return false
+}
+
+// PegPriceType is the reference price of a pegged order
+type PegPriceType string
+
+const (
+	PegPriceTypeLastPeg         PegPriceType = "LastPeg"
+	PegPriceTypeMidPricePeg     PegPriceType = "MidPricePeg"
+	PegPriceTypeMarketPeg       PegPriceType = "MarketPeg"
+	PegPriceTypePrimaryPeg      PegPriceType = "PrimaryPeg"
+	PegPriceTypeTrailingStopPeg PegPriceType = "TrailingStopPeg"
+)
+
+// Valid reports whether t is a known peg price type
+func (t PegPriceType) Valid() bool {
+	switch t {
+	case PegPriceTypeLastPeg, PegPriceTypeMidPricePeg, PegPriceTypeMarketPeg, PegPriceTypePrimaryPeg, PegPriceTypeTrailingStopPeg:
+		return true
+	}
+	return false
+}
+
+// ContingencyType is the link between the orders of a group
+type ContingencyType string
+
+const (
+	ContingencyOCO  ContingencyType = "OneCancelsTheOther"
+	ContingencyOTO  ContingencyType = "OneTriggersTheOther"
+	ContingencyOUOA ContingencyType = "OneUpdatesTheOtherAbsolute"
+	ContingencyOUOP ContingencyType = "OneUpdatesTheOtherProportional"
+)
+
+// Valid reports whether t is a known contingency type
+func (t ContingencyType) Valid() bool {
+	switch t {
+	case ContingencyOCO, ContingencyOTO, ContingencyOUOA, ContingencyOUOP:
+		return true
+	}
+	return false
+}
+
+// ExecInst is a set of execution instructions. Combine them with |:
+//
+//	ExecClose | ExecParticipateDoNotInitiate
+type ExecInst uint
+
+const (
+	ExecReduceOnly               ExecInst = 1 << iota // only reduce the position
+	ExecClose                                         // close the position, implies ReduceOnly
+	ExecParticipateDoNotInitiate                      // post only
+	ExecMarkPrice                                     // trigger on the mark price
+	ExecIndexPrice                                    // trigger on the index price
+	ExecLastPrice                                     // trigger on the last price
+	ExecLastWithinMark
+	ExecAllOrNone // hidden orders only
+	ExecFixed
+)
+
+// wire names, in bit order
+var execInstNames = []string{
+	"ReduceOnly",
+	"Close",
+	"ParticipateDoNotInitiate",
+	"MarkPrice",
+	"IndexPrice",
+	"LastPrice",
+	"LastWithinMark",
+	"AllOrNone",
+	"Fixed",
+}
+
+const execTriggers = ExecMarkPrice | ExecIndexPrice | ExecLastPrice | ExecLastWithinMark
+
+// ParseExecInst parses a comma separated execInst such as "Close,LastPrice"
+func ParseExecInst(s string) (e ExecInst, err error) {
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		i := indexOf(execInstNames, name)
+		if i < 0 {
+			err = fmt.Errorf("unknown execInst %q", name)
+			return
+		}
+		e |= 1 << uint(i)
+	}
+	return
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has reports whether all instructions of f are set
+func (e ExecInst) Has(f ExecInst) bool {
+	return e&f == f
+}
+
+// String returns the wire format, e.g. "Close,ParticipateDoNotInitiate"
+func (e ExecInst) String() string {
+	var names []string
+	for i, name := range execInstNames {
+		if e&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Check validates the combination of instructions for an order of type
+// ordType. An empty ordType skips the checks that depend on it.
+func (e ExecInst) Check(ordType OrdType) error {
+	if n := bitCount(e & execTriggers); n > 1 {
+		return fmt.Errorf("execInst %v: more than one trigger price", e)
+	}
+	if ordType == "" {
+		return nil
+	}
+	if e&execTriggers != 0 && !ordType.IsConditional() {
+		return fmt.Errorf("execInst %v: trigger price on a %v order", e, ordType)
+	}
+	if e.Has(ExecParticipateDoNotInitiate) && !ordType.HasPrice() {
+		return fmt.Errorf("execInst %v: post only on a %v order", e, ordType)
+	}
+	return nil
+}
+
+func bitCount(e ExecInst) (n int) {
+	for ; e != 0; e &= e - 1 {
+		n++
+	}
+	return
+}
+
+// MarshalJSON encodes the wire format as a JSON string
+func (e ExecInst) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.String())
+}
+
+// UnmarshalJSON decodes the wire format. Instructions unknown to this
+// package are dropped rather than failing the whole message.
+func (e *ExecInst) UnmarshalJSON(data []byte) error {
+	var s string
+	if string(data) != "null" {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	*e = parseExecInst(s)
+	return nil
+}
+
+// parseExecInst is ParseExecInst without errors: unknown instructions are
+// dropped
+func parseExecInst(s string) (e ExecInst) {
+	for _, name := range strings.Split(s, ",") {
+		if i := indexOf(execInstNames, strings.TrimSpace(name)); i >= 0 {
+			e |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+// checkEnums validates the enumerated fields of a new order. Empty values
+// are left to the exchange defaults.
+func checkEnums(side Side, ordType OrdType, timeInForce TimeInForce, execInst ExecInst) error {
+	if side != "" && !side.Valid() {
+		return fmt.Errorf("invalid side %q", side)
+	}
+	if ordType != "" && !ordType.Valid() {
+		return fmt.Errorf("invalid ordType %q", ordType)
+	}
+	if timeInForce != "" && !timeInForce.Valid() {
+		return fmt.Errorf("invalid timeInForce %q", timeInForce)
+	}
+	return execInst.Check(ordType)
+}
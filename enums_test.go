@@ -0,0 +1,66 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExecInst(t *testing.T) {
+	e, err := ParseExecInst("LastPrice, ReduceOnly")
+	if err != nil || e != ExecReduceOnly|ExecLastPrice {
+		t.Fatalf("parse %v %v", e, err)
+	}
+	if s := e.String(); s != "ReduceOnly,LastPrice" {
+		t.Errorf("String %v", s)
+	}
+	if _, err := ParseExecInst("Clsoe"); err == nil {
+		t.Error("unknown execInst parsed")
+	}
+
+	tests := []struct {
+		e       ExecInst
+		ordType OrdType
+		ok      bool
+	}{
+		{ExecClose | ExecParticipateDoNotInitiate, OrdTypeLimit, true},
+		{ExecReduceOnly | ExecMarkPrice, OrdTypeStop, true},
+		{ExecMarkPrice | ExecLastPrice, OrdTypeStop, false},
+		{ExecMarkPrice, OrdTypeLimit, false},
+		{ExecParticipateDoNotInitiate, OrdTypeMarket, false},
+		{ExecParticipateDoNotInitiate, "", true},
+	}
+	for _, test := range tests {
+		if err := test.e.Check(test.ordType); (err == nil) != test.ok {
+			t.Errorf("%v on %v: %v", test.e, test.ordType, err)
+		}
+	}
+}
+
+func TestExecInst_JSON(t *testing.T) {
+	var o Order
+	if err := json.Unmarshal([]byte(`{"orderID":"a","side":"Sell","execInst":"Close,SomethingNew"}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Side != SideSell || o.ExecInst != ExecClose {
+		t.Errorf("order %+v", o)
+	}
+	data, _ := json.Marshal(o)
+	if string(data) != `{"orderID":"a","side":"Sell","execInst":"Close"}` {
+		t.Errorf("marshal %s", data)
+	}
+}
+
+func TestCheckEnums(t *testing.T) {
+	if err := checkEnums("buy", ORD_TYPE_LIMIT, "", 0); err == nil {
+		t.Error(`side "buy" accepted`)
+	}
+	if err := checkEnums(SIDE_BUY, ORD_TYPE_LIMIT, "GTC", 0); err == nil {
+		t.Error(`timeInForce "GTC" accepted`)
+	}
+	if err := checkEnums(SIDE_BUY, ORD_TYPE_LIMIT, "", ExecParticipateDoNotInitiate); err != nil {
+		t.Error(err)
+	}
+	if err := checkEnums(SideSell, OrdTypeStop, TimeInForceGoodTillCancel, ExecClose|ExecLastPrice); err != nil {
+		t.Error(err)
+	}
+}
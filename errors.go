@@ -0,0 +1,56 @@
+package bitmex
+
+import (
+	"errors"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// APIError is the error of a REST call answered with a status of 300 or
+// above. It carries the BitMEX error name and message of the body, the
+// request and the rate limit headers.
+type APIError = swagger.APIError
+
+// Kinds of API errors, to be used with errors.Is
+var (
+	ErrOverloaded          = swagger.ErrOverloaded
+	ErrRateLimited         = swagger.ErrRateLimited
+	ErrInsufficientBalance = swagger.ErrInsufficientBalance
+	ErrInvalidOrdStatus    = swagger.ErrInvalidOrdStatus
+	ErrDuplicateClOrdID    = swagger.ErrDuplicateClOrdID
+	ErrAuth                = swagger.ErrAuth
+)
+
+// IsOverloaded reports whether BitMEX rejected the request with 503
+// because the system is overloaded. It is safe to retry.
+func IsOverloaded(err error) bool {
+	return errors.Is(err, ErrOverloaded)
+}
+
+// IsRateLimited reports whether BitMEX answered 429, or the rate limiter
+// refused to send the request
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsInsufficientBalance reports whether the account can't afford the order
+func IsInsufficientBalance(err error) bool {
+	return errors.Is(err, ErrInsufficientBalance)
+}
+
+// IsInvalidOrdStatus reports whether the order is no longer in a state
+// allowing the request, e.g. the amendment or cancel of a filled order
+func IsInvalidOrdStatus(err error) bool {
+	return errors.Is(err, ErrInvalidOrdStatus)
+}
+
+// IsDuplicateClOrdID reports whether the clOrdID is already in use
+func IsDuplicateClOrdID(err error) bool {
+	return errors.Is(err, ErrDuplicateClOrdID)
+}
+
+// IsAuthError reports whether the API key, signature or permissions were
+// refused
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuth)
+}
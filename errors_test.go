@@ -0,0 +1,83 @@
+package bitmex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIError(t *testing.T) {
+	var status int
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Limit", "120")
+		w.Header().Set("X-Ratelimit-Remaining", "100")
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "2")
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetRetryPolicy(RetryPolicy{})
+
+	for _, v := range []struct {
+		status int
+		body   string
+		match  func(error) bool
+	}{
+		{503, `{"error":{"message":"The system is currently overloaded. Please try again later.","name":"HTTPError"}}`, IsOverloaded},
+		{429, `{"error":{"message":"Rate limit exceeded, retry in 2 seconds.","name":"RateLimitError"}}`, IsRateLimited},
+		{400, `{"error":{"message":"Account has insufficient Available Balance, 1000 XBt required","name":"ValidationError"}}`, IsInsufficientBalance},
+		{400, `{"error":{"message":"Invalid ordStatus","name":"HTTPError"}}`, IsInvalidOrdStatus},
+		{400, `{"error":{"message":"Duplicate clOrdID","name":"HTTPError"}}`, IsDuplicateClOrdID},
+		{401, `{"error":{"message":"Signature not valid.","name":"HTTPError"}}`, IsAuthError},
+		{403, `{"error":{"message":"This key is disabled.","name":"HTTPError"}}`, IsAuthError},
+	} {
+		status, body = v.status, v.body
+		// a 429 stops the limiter until Retry-After
+		b.SetRateLimitMode(RateLimitOff)
+
+		_, err := b.CancelOrder("a")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%v: got %T %v", v.status, err, err)
+		}
+		if apiErr.StatusCode != v.status || apiErr.Method != http.MethodDelete || apiErr.Path != "/order" ||
+			apiErr.Message == "" || apiErr.Name == "" || apiErr.RateLimitLimit != 120 || apiErr.RateLimitRemaining != 100 {
+			t.Errorf("%v: %+v", v.status, apiErr)
+		}
+		if !v.match(err) || !v.match(fmt.Errorf("wrapped: %w", err)) {
+			t.Errorf("%v: %v not matched", v.status, err)
+		}
+		matched := 0
+		for _, match := range []func(error) bool{IsOverloaded, IsRateLimited, IsInsufficientBalance, IsInvalidOrdStatus, IsDuplicateClOrdID, IsAuthError} {
+			if match(err) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			t.Errorf("%v: %v matched %v kinds", v.status, err, matched)
+		}
+		if v.status == http.StatusTooManyRequests && apiErr.RetryAfter != 2*time.Second {
+			t.Errorf("retry after %v", apiErr.RetryAfter)
+		}
+	}
+
+	status, body = http.StatusBadGateway, "<html>bad gateway</html>"
+	_, err := b.CancelOrder("a")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != body || apiErr.Name != "" {
+		t.Errorf("non-JSON body: %v", err)
+	}
+
+	if !IsRateLimited(&RateLimitError{Wait: time.Second}) {
+		t.Error("RateLimitError is not rate limited")
+	}
+}
@@ -1,6 +1,9 @@
 package bitmex
 
-import "github.com/chuckpreslar/emission"
+import (
+	"github.com/chuckpreslar/emission"
+	"github.com/sumorf/bitmex-api/swagger"
+)
 
 //On adds a listener to a specific event
 func (b *BitMEX) On(event interface{}, listener interface{}) *emission.Emitter {
@@ -16,3 +19,61 @@ func (b *BitMEX) Emit(event interface{}, arguments ...interface{}) *emission.Emi
 func (b *BitMEX) Off(event interface{}, listener interface{}) *emission.Emitter {
 	return b.emitter.Off(event, listener)
 }
+
+// tableHandler receives the decoded data of a websocket table before it is emitted
+type tableHandler func(data interface{}, action string)
+
+// onTable registers an internal handler for a websocket table. Handlers run
+// on the websocket goroutine and must not block. Unlike Off, the returned
+// function removes exactly this handler.
+func (b *BitMEX) onTable(table string, h tableHandler) (remove func()) {
+	b.tableHandlersMutex.Lock()
+	defer b.tableHandlersMutex.Unlock()
+
+	b.tableHandlerSeq++
+	id := b.tableHandlerSeq
+	if b.tableHandlers[table] == nil {
+		b.tableHandlers[table] = make(map[int]tableHandler)
+	}
+	b.tableHandlers[table][id] = h
+	return func() {
+		b.tableHandlersMutex.Lock()
+		defer b.tableHandlersMutex.Unlock()
+		delete(b.tableHandlers[table], id)
+	}
+}
+
+func (b *BitMEX) dispatch(table string, data interface{}, action string) {
+	b.tableHandlersMutex.RLock()
+	handlers := make([]tableHandler, 0, len(b.tableHandlers[table]))
+	for _, h := range b.tableHandlers[table] {
+		handlers = append(handlers, h)
+	}
+	b.tableHandlersMutex.RUnlock()
+
+	for _, h := range handlers {
+		h(data, action)
+	}
+}
+
+// WatchOrders registers a handler for the order table that runs before the
+// order event is emitted. Call remove to unregister it.
+func (b *BitMEX) WatchOrders(h func(orders []*swagger.Order, action string)) (remove func()) {
+	return b.onTable(BitmexWSOrder, func(data interface{}, action string) {
+		h(data.([]*swagger.Order), action)
+	})
+}
+
+// WatchInstruments registers a handler for the instrument table
+func (b *BitMEX) WatchInstruments(h func(instruments []*swagger.Instrument, action string)) (remove func()) {
+	return b.onTable(BitmexWSInstrument, func(data interface{}, action string) {
+		h(data.([]*swagger.Instrument), action)
+	})
+}
+
+// WatchTrades registers a handler for the trade table
+func (b *BitMEX) WatchTrades(h func(trades []*swagger.Trade, action string)) (remove func()) {
+	return b.onTable(BitmexWSTrade, func(data interface{}, action string) {
+		h(data.([]*swagger.Trade), action)
+	})
+}
@@ -23,7 +23,7 @@ func main() {
 	b.On(bitmex.BitmexWSOrderBookL2, func(m bitmex.OrderBookDataL2, symbol string) {
 		ob := m.OrderBook()
 		fmt.Printf("\rOrderbook Asks: %#v Bids: %#v                            ", ob.Asks[0], ob.Bids[0])
-	}).On(bitmex.BitmexWSOrder, func(m []*swagger.Order, action string) {
+	}).On(bitmex.BitmexWSOrder, func(m []*bitmex.Order, action string) {
 		fmt.Printf("Order action=%v orders=%#v\n", action, m)
 	}).On(bitmex.BitmexWSPosition, func(m []*swagger.Position, action string) {
 		fmt.Printf("Position action=%v positions=%#v\n", action, m)
@@ -36,10 +36,10 @@ func main() {
 	// Get orderbook by rest api
 	b.GetOrderBook(10, "XBTUSD")
 	// Place a limit buy order
-	b.PlaceOrder(bitmex.SIDE_BUY, bitmex.ORD_TYPE_LIMIT, 0, 6000.0, 1000, "", "", "XBTUSD")
+	b.PlaceOrder(bitmex.SIDE_BUY, bitmex.ORD_TYPE_LIMIT, bitmex.Price{}, bitmex.MustPrice("6000"), 1000, "", 0, "XBTUSD")
 	b.GetOrders("XBTUSD")
 	b.GetOrder("{OrderID}", "XBTUSD")
-	b.AmendOrder("{OrderID}", 6000.5)
+	b.AmendOrder("{OrderID}", bitmex.MustPrice("6000.5"))
 	b.CancelOrder("{OrderID}")
 	b.CancelAllOrders("XBTUSD")
 	b.GetPosition("XBTUSD")
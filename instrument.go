@@ -0,0 +1,365 @@
+package bitmex
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// ValidationMode tells PlaceOrder*/AmendOrder* what to do with a price or a
+// quantity that is off the tick or lot size of a known instrument
+type ValidationMode int
+
+const (
+	// ValidateReject returns an *InvalidOrderError without sending the order
+	ValidateReject ValidationMode = iota
+	// ValidateRound rounds toward the passive side: limit prices away from
+	// the market (buys down, sells up), stop prices further from triggering
+	// (buys up, sells down), quantities down to the lot size and risk limits
+	// up to the next risk step
+	ValidateRound
+	// ValidateOff sends orders unchanged
+	ValidateOff
+)
+
+// InstrumentSpec is the trading metadata of an instrument
+type InstrumentSpec struct {
+	Symbol           string
+	Underlying       string
+	QuoteCurrency    string
+	SettlCurrency    string
+	PositionCurrency string
+
+	TickSize    float64
+	LotSize     float64
+	MaxOrderQty float64
+	MaxPrice    float64
+	Multiplier  float64
+	IsInverse   bool
+	IsQuanto    bool
+
+	UnderlyingToSettleMultiplier float64
+	QuoteToSettleMultiplier      float64
+
+	MakerFee      float64
+	TakerFee      float64
+	SettlementFee float64
+
+	InitMargin  float64
+	MaintMargin float64
+	RiskLimit   float64
+	RiskStep    float64
+
+	// latest prices, kept up to date by the instrument stream
+	MarkPrice float64
+	LastPrice float64
+}
+
+// InvalidOrderError is returned for an order that does not fit its instrument
+type InvalidOrderError struct {
+	Symbol string
+	Field  string // price, stopPx, orderQty, ...
+	Value  float64
+	Reason string
+}
+
+func (e *InvalidOrderError) Error() string {
+	return fmt.Sprintf("invalid order: symbol=%v %v=%v %v", e.Symbol, e.Field, e.Value, e.Reason)
+}
+
+// Instruments is a registry of instrument metadata, loaded with
+// LoadInstruments and kept up to date by the instrument stream
+type Instruments struct {
+	m     sync.RWMutex
+	specs map[string]*InstrumentSpec // key: symbol
+}
+
+func newInstruments() *Instruments {
+	return &Instruments{specs: make(map[string]*InstrumentSpec)}
+}
+
+// Get returns the metadata of symbol
+func (r *Instruments) Get(symbol string) (spec InstrumentSpec, ok bool) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	s, ok := r.specs[symbol]
+	if ok {
+		spec = *s
+	}
+	return
+}
+
+// Symbols returns the known symbols in alphabetical order
+func (r *Instruments) Symbols() []string {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	symbols := make([]string, 0, len(r.specs))
+	for symbol := range r.specs {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// update merges instrument records. Stream updates only carry the changed
+// fields, so zero values are ignored; flags are taken from full records only.
+func (r *Instruments) update(instruments []*swagger.Instrument, full bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, v := range instruments {
+		if v.Symbol == "" {
+			continue
+		}
+		s, ok := r.specs[v.Symbol]
+		if !ok {
+			if !full {
+				continue
+			}
+			s = &InstrumentSpec{Symbol: v.Symbol}
+			r.specs[v.Symbol] = s
+		}
+		if full {
+			s.IsInverse = v.IsInverse
+			s.IsQuanto = v.IsQuanto
+		}
+		setString(&s.Underlying, v.Underlying)
+		setString(&s.QuoteCurrency, v.QuoteCurrency)
+		setString(&s.SettlCurrency, v.SettlCurrency)
+		setString(&s.PositionCurrency, v.PositionCurrency)
+		setFloat(&s.TickSize, v.TickSize)
+		setFloat(&s.LotSize, float64(v.LotSize))
+		setFloat(&s.MaxOrderQty, float64(v.MaxOrderQty))
+		setFloat(&s.MaxPrice, v.MaxPrice)
+		setFloat(&s.Multiplier, float64(v.Multiplier))
+		setFloat(&s.UnderlyingToSettleMultiplier, float64(v.UnderlyingToSettleMultiplier))
+		setFloat(&s.QuoteToSettleMultiplier, float64(v.QuoteToSettleMultiplier))
+		setFloat(&s.MakerFee, v.MakerFee)
+		setFloat(&s.TakerFee, v.TakerFee)
+		setFloat(&s.SettlementFee, v.SettlementFee)
+		setFloat(&s.InitMargin, v.InitMargin)
+		setFloat(&s.MaintMargin, v.MaintMargin)
+		setFloat(&s.RiskLimit, float64(v.RiskLimit))
+		setFloat(&s.RiskStep, float64(v.RiskStep))
+		setFloat(&s.MarkPrice, v.MarkPrice)
+		setFloat(&s.LastPrice, v.LastPrice)
+	}
+}
+
+func setString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+func setFloat(dst *float64, v float64) {
+	if v != 0 {
+		*dst = v
+	}
+}
+
+// Instruments returns the instrument registry
+func (b *BitMEX) Instruments() *Instruments {
+	return b.instruments
+}
+
+// LoadInstruments fills the instrument registry with the active instruments
+func (b *BitMEX) LoadInstruments() (err error) {
+	return b.LoadInstrumentsContext(context.Background())
+}
+
+// LoadInstrumentsContext is like LoadInstruments but takes a context
+func (b *BitMEX) LoadInstrumentsContext(ctx context.Context) (err error) {
+	var response *http.Response
+	var instruments []swagger.Instrument
+
+	instruments, response, err = b.client.InstrumentApi.InstrumentGetActive(ctx)
+	if err != nil {
+		return
+	}
+	b.onResponsePublic(response)
+
+	list := make([]*swagger.Instrument, len(instruments))
+	for i := range instruments {
+		list[i] = &instruments[i]
+	}
+	b.instruments.update(list, true)
+	return
+}
+
+// SetOrderValidation sets how orders are checked against the instrument
+// registry before they are sent. Orders for unknown symbols are never checked.
+func (b *BitMEX) SetOrderValidation(mode ValidationMode) {
+	atomic.StoreInt32(&b.validation, int32(mode))
+}
+
+// validationMode returns the mode set by SetOrderValidation
+func (b *BitMEX) validationMode() ValidationMode {
+	return ValidationMode(atomic.LoadInt32(&b.validation))
+}
+
+// RoundPrice rounds price to the tick size, up or down
+func (s *InstrumentSpec) RoundPrice(price float64, up bool) float64 {
+	return roundStep(price, s.TickSize, up)
+}
+
+// Notional returns the absolute value of qty contracts at price, in
+// settlement units (XBt for XBTUSD)
+func (s *InstrumentSpec) Notional(qty int64, price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	if s.IsInverse {
+		return math.Abs(s.Multiplier * float64(qty) / price)
+	}
+	return math.Abs(s.Multiplier * float64(qty) * price)
+}
+
+// RoundQty rounds qty down to the lot size
+func (s *InstrumentSpec) RoundQty(qty float64) float64 {
+	return roundStep(qty, s.LotSize, false)
+}
+
+func roundStep(v float64, step float64, up bool) float64 {
+	if step <= 0 {
+		return v
+	}
+	n := v / step
+	if up {
+		n = math.Ceil(n - 1e-9)
+	} else {
+		n = math.Floor(n + 1e-9)
+	}
+	// trim the float noise of n*step to the decimals of step
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(n*step, 'f', decimals(step), 64), 64)
+	return r
+}
+
+func decimals(step float64) int {
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			return len(s) - i - 1
+		}
+	}
+	return 0
+}
+
+func onStep(v float64, step float64) bool {
+	return step <= 0 || math.Abs(v/step-math.Round(v/step)) < 1e-9
+}
+
+// qtyField is a quantity checked against the lot size
+type qtyField struct {
+	name string
+	v    *float64
+}
+
+// checkOrder validates, or rounds in place, the price, stop price and
+// quantities of an order for symbol. side may be empty when unknown; prices
+// are then rejected rather than rounded. Zero values are not checked.
+// Prices are compared and rounded as exact decimals.
+func (b *BitMEX) checkOrder(symbol string, side string, price *Price, stopPx *Price, qtys ...qtyField) error {
+	mode := b.validationMode()
+	if mode == ValidateOff {
+		return nil
+	}
+	spec, ok := b.instruments.Get(symbol)
+	if !ok {
+		return nil
+	}
+
+	tick, max := PriceOf(spec.TickSize), PriceOf(spec.MaxPrice)
+	prices := []struct {
+		field string
+		v     *Price
+		up    bool // passive rounding direction
+	}{
+		{"price", price, side == SIDE_SELL},
+		{"stopPx", stopPx, side == SIDE_BUY},
+	}
+	for _, p := range prices {
+		if p.v == nil || p.v.Cmp(Price{}) <= 0 {
+			continue
+		}
+		if !p.v.OnTick(tick) {
+			if mode != ValidateRound || side == "" {
+				return &InvalidOrderError{symbol, p.field, p.v.Float64(), fmt.Sprintf("not a multiple of tick size %v", tick)}
+			}
+			*p.v = p.v.Round(tick, p.up)
+		}
+		if !max.IsZero() && p.v.Cmp(max) > 0 {
+			return &InvalidOrderError{symbol, p.field, p.v.Float64(), fmt.Sprintf("above max price %v", max)}
+		}
+	}
+
+	for _, q := range qtys {
+		field, qty := q.name, q.v
+		if qty == nil || *qty <= 0 {
+			continue
+		}
+		if !onStep(*qty, spec.LotSize) {
+			if mode != ValidateRound {
+				return &InvalidOrderError{symbol, field, *qty, fmt.Sprintf("not a multiple of lot size %v", spec.LotSize)}
+			}
+			rounded := spec.RoundQty(*qty)
+			if rounded <= 0 {
+				return &InvalidOrderError{symbol, field, *qty, fmt.Sprintf("below lot size %v", spec.LotSize)}
+			}
+			*qty = rounded
+		}
+		if spec.MaxOrderQty > 0 && *qty > spec.MaxOrderQty {
+			return &InvalidOrderError{symbol, field, *qty, fmt.Sprintf("above max order quantity %v", spec.MaxOrderQty)}
+		}
+	}
+	return nil
+}
+
+// checkAmend validates an amend of orderID. The symbol and side come from
+// the order stream; orders not seen there are sent unchecked.
+func (b *BitMEX) checkAmend(orderID string, price *Price, stopPx *Price, qtys ...qtyField) error {
+	if b.validationMode() == ValidateOff || orderID == "" {
+		return nil
+	}
+	order, ok := b.localOrder(orderID)
+	if !ok {
+		return nil
+	}
+	return b.checkOrder(order.Symbol, order.Side, price, stopPx, qtys...)
+}
+
+// checkRiskLimit validates, or rounds up in place, a risk limit of symbol
+// against the base risk limit and the risk step of the instrument
+func (b *BitMEX) checkRiskLimit(symbol string, riskLimit *int64) error {
+	if *riskLimit <= 0 {
+		return &InvalidOrderError{symbol, "riskLimit", float64(*riskLimit), "must be positive"}
+	}
+	mode := b.validationMode()
+	if mode == ValidateOff {
+		return nil
+	}
+	spec, ok := b.instruments.Get(symbol)
+	if !ok || spec.RiskLimit <= 0 {
+		return nil
+	}
+	base, step := int64(spec.RiskLimit), int64(spec.RiskStep)
+	if *riskLimit < base {
+		return &InvalidOrderError{symbol, "riskLimit", float64(*riskLimit), fmt.Sprintf("below base risk limit %v", base)}
+	}
+	if step <= 0 {
+		return nil
+	}
+	if r := (*riskLimit - base) % step; r != 0 {
+		if mode != ValidateRound {
+			return &InvalidOrderError{symbol, "riskLimit", float64(*riskLimit), fmt.Sprintf("not base %v plus a multiple of risk step %v", base, step)}
+		}
+		*riskLimit += step - r
+	}
+	return nil
+}
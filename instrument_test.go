@@ -0,0 +1,147 @@
+package bitmex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestInstruments_Update(t *testing.T) {
+	r := newInstruments()
+	r.update([]*swagger.Instrument{
+		{Symbol: "XBTUSD", TickSize: 0.5, LotSize: 1, MaxOrderQty: 10000000, Multiplier: -100000000, IsInverse: true, MakerFee: -0.00025},
+	}, true)
+	r.update([]*swagger.Instrument{{Symbol: "XBTUSD", MaxPrice: 1000000}}, false)
+	r.update([]*swagger.Instrument{{Symbol: "ETHUSD", TickSize: 0.05}}, false) // unknown, ignored
+
+	spec, ok := r.Get("XBTUSD")
+	if !ok || spec.TickSize != 0.5 || spec.MaxPrice != 1000000 || !spec.IsInverse || spec.MakerFee != -0.00025 {
+		t.Errorf("spec %#v", spec)
+	}
+	if symbols := r.Symbols(); len(symbols) != 1 {
+		t.Errorf("symbols %v", symbols)
+	}
+}
+
+func TestRoundStep(t *testing.T) {
+	tests := []struct {
+		v, step float64
+		up      bool
+		want    float64
+	}{
+		{5000.3, 0.5, false, 5000},
+		{5000.3, 0.5, true, 5000.5},
+		{5000.5, 0.5, true, 5000.5},
+		{0.30000001, 0.1, false, 0.3},
+		{0.00012345, 0.00001, true, 0.00013},
+		{150, 100, false, 100},
+	}
+	for _, tt := range tests {
+		if got := roundStep(tt.v, tt.step, tt.up); got != tt.want {
+			t.Errorf("roundStep(%v, %v, %v) = %v, want %v", tt.v, tt.step, tt.up, got, tt.want)
+		}
+	}
+}
+
+func TestBitMEX_CheckOrder(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	b.instruments.update([]*swagger.Instrument{
+		{Symbol: "XBTUSD", TickSize: 0.5, LotSize: 100, MaxOrderQty: 1000, MaxPrice: 1000000},
+	}, true)
+
+	price, stopPx, qty := MustPrice("5000.3"), MustPrice("4000.2"), 150.0
+	err := b.checkOrder("XBTUSD", SIDE_BUY, &price, &stopPx, qtyField{"orderQty", &qty})
+	if e, ok := err.(*InvalidOrderError); !ok || e.Field != "price" {
+		t.Errorf("reject: %v", err)
+	}
+
+	b.SetOrderValidation(ValidateRound)
+	if err := b.checkOrder("XBTUSD", SIDE_BUY, &price, &stopPx, qtyField{"orderQty", &qty}); err != nil {
+		t.Fatal(err)
+	}
+	if price != MustPrice("5000") || stopPx != MustPrice("4000.5") || qty != 100 {
+		t.Errorf("buy rounded to price=%v stopPx=%v qty=%v", price, stopPx, qty)
+	}
+	price, stopPx = MustPrice("5000.3"), MustPrice("4000.2")
+	b.checkOrder("XBTUSD", SIDE_SELL, &price, &stopPx)
+	if price != MustPrice("5000.5") || stopPx != MustPrice("4000") {
+		t.Errorf("sell rounded to price=%v stopPx=%v", price, stopPx)
+	}
+
+	// ticks that floats cannot represent are compared exactly
+	b.instruments.update([]*swagger.Instrument{{Symbol: "ETHXBT", TickSize: 0.00001}}, true)
+	price = MustPrice("0.03001")
+	if err := b.checkOrder("ETHXBT", SIDE_BUY, &price, nil); err != nil || price.String() != "0.03001" {
+		t.Errorf("on-tick price %v: %v", price, err)
+	}
+	price = MustPrice("0.030015")
+	if b.checkOrder("ETHXBT", SIDE_BUY, &price, nil); price.String() != "0.03001" {
+		t.Errorf("rounded to %v", price)
+	}
+
+	qty = 50
+	if err := b.checkOrder("XBTUSD", SIDE_BUY, nil, nil, qtyField{"orderQty", &qty}); err == nil {
+		t.Error("quantity below lot size accepted")
+	}
+	qty = 2000
+	if err := b.checkOrder("XBTUSD", SIDE_BUY, nil, nil, qtyField{"orderQty", &qty}); err == nil {
+		t.Error("quantity above max accepted")
+	}
+	price = MustPrice("5000.3")
+	if err := b.checkOrder("ETHUSD", SIDE_BUY, &price, nil); err != nil || price != MustPrice("5000.3") {
+		t.Errorf("unknown symbol checked: %v %v", err, price)
+	}
+}
+
+func TestBitMEX_PlaceOrdersValidation(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"orderID":"o1","ordStatus":"New"}]`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", TickSize: 0.5, LotSize: 1}}, true)
+
+	if _, err := b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000.3"), 10, -1, "", 0, "XBTUSD", "", ""); err == nil {
+		t.Error("off-tick order sent")
+	}
+
+	results, err := b.PlaceOrders(nil, []OrderRequest{
+		{Symbol: "XBTUSD", Side: SIDE_BUY, OrdType: ORD_TYPE_LIMIT, OrderQty: 10, Price: MustPrice("5000.3")},
+		{Symbol: "XBTUSD", Side: SIDE_BUY, OrdType: ORD_TYPE_LIMIT, OrderQty: 10, Price: MustPrice("5000")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := results[0].Err.(*InvalidOrderError); !ok {
+		t.Errorf("result 0: %v", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Order.OrderID != "o1" {
+		t.Errorf("result 1: %#v", results[1])
+	}
+	if requests != 1 {
+		t.Errorf("requests %v", requests)
+	}
+}
+
+func TestBitMEX_SetOrderValidationConcurrent(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", TickSize: 0.5, LotSize: 1}}, true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			b.SetOrderValidation(ValidationMode(i % 3))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		price := MustPrice("5000.3")
+		b.checkOrder("XBTUSD", SIDE_BUY, &price, nil)
+	}
+	<-done
+}
@@ -0,0 +1,161 @@
+package bitmex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// ErrKillSwitch is returned by every order-submitting method once KillSwitch
+// has been called, until ResetKillSwitch. Orders that can only reduce a
+// position, such as ClosePosition, are still accepted.
+var ErrKillSwitch = errors.New("kill switch engaged")
+
+// DefaultKillSwitchRetry is the wait between two attempts of a request
+// answered with 503 overload when KillSwitchOptions.RetryInterval is not set
+var DefaultKillSwitchRetry = 500 * time.Millisecond
+
+// KillSwitchOptions tells KillSwitch how to flatten the account
+type KillSwitchOptions struct {
+	// Slippage closes positions with limit orders priced this far through
+	// the mark price, e.g. 0.01 for 1%. Zero, or an instrument without a
+	// known tick size or mark price, closes at market.
+	Slippage float64
+
+	// DisableAPIKey disables the API key once positions are closed. It must
+	// then be enabled again on the website.
+	DisableAPIKey bool
+
+	RetryInterval time.Duration
+}
+
+// KillSwitchResult is what KillSwitch did
+type KillSwitchResult struct {
+	Canceled []swagger.Order // canceled orders
+	Closed   []swagger.Order // closing orders, one per open position
+	Disabled bool            // the API key was disabled
+}
+
+// KillSwitch blocks further order submissions, cancels the orders of every
+// symbol, closes every open position and optionally disables the API key.
+// Requests answered with 503 overload are retried until ctx is done; other
+// errors don't stop the remaining steps, the first one is returned.
+func (b *BitMEX) KillSwitch(ctx context.Context, opts KillSwitchOptions) (result KillSwitchResult, err error) {
+	atomic.StoreInt32(&b.killed, 1)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = DefaultKillSwitchRetry
+	}
+	auth := withPriority(b.authContext(ctx), PriorityCancel)
+	fail := func(e error) {
+		if err == nil {
+			err = e
+		}
+	}
+	retry := func(call func() (*http.Response, error)) error {
+		for {
+			response, e := call()
+			if e == nil {
+				b.onResponse(response)
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !IsOverloaded(e) {
+				return e
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(opts.RetryInterval):
+			}
+		}
+	}
+
+	// without a symbol every order is canceled
+	fail(retry(func() (response *http.Response, e error) {
+		result.Canceled, response, e = b.client.OrderApi.OrderCancelAll(auth, map[string]interface{}{})
+		return
+	}))
+
+	var positions []swagger.Position
+	e := retry(func() (response *http.Response, e error) {
+		params := map[string]interface{}{"filter": `{"isOpen":true}`}
+		positions, response, e = b.client.PositionApi.PositionGet(auth, params)
+		return
+	})
+	fail(e)
+	for i := range positions {
+		p := &positions[i]
+		if p.CurrentQty == 0 {
+			continue
+		}
+		params := map[string]interface{}{}
+		if price := b.closePrice(p, opts.Slippage); !price.IsZero() {
+			params["price"] = price.String()
+		}
+		var order swagger.Order
+		e := retry(func() (response *http.Response, e error) {
+			order, response, e = b.client.OrderApi.OrderClosePosition(auth, p.Symbol, params)
+			return
+		})
+		if e != nil {
+			fail(e)
+			continue
+		}
+		result.Closed = append(result.Closed, order)
+	}
+
+	if opts.DisableAPIKey {
+		e := retry(func() (response *http.Response, e error) {
+			_, response, e = b.client.APIKeyApi.APIKeyDisable(auth, b.Key)
+			return
+		})
+		fail(e)
+		result.Disabled = e == nil
+	}
+	return
+}
+
+// closePrice returns an aggressive limit price closing p, or zero for a
+// market close
+func (b *BitMEX) closePrice(p *swagger.Position, slippage float64) Price {
+	if slippage <= 0 {
+		return Price{}
+	}
+	spec, ok := b.instruments.Get(p.Symbol)
+	if !ok || spec.TickSize <= 0 {
+		return Price{}
+	}
+	mark := p.MarkPrice
+	if mark <= 0 {
+		mark = spec.MarkPrice
+	}
+	if mark <= 0 {
+		return Price{}
+	}
+	tick := PriceOf(spec.TickSize)
+	if p.CurrentQty > 0 {
+		// sell below the mark
+		return PriceOf(mark*(1-slippage)).Round(tick, false)
+	}
+	return PriceOf(mark*(1+slippage)).Round(tick, true)
+}
+
+// ResetKillSwitch accepts order submissions again
+func (b *BitMEX) ResetKillSwitch() {
+	atomic.StoreInt32(&b.killed, 0)
+}
+
+// KillSwitchEngaged reports whether order submissions are blocked
+func (b *BitMEX) KillSwitchEngaged() bool {
+	return atomic.LoadInt32(&b.killed) != 0
+}
@@ -0,0 +1,136 @@
+package bitmex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestBitMEX_KillSwitch(t *testing.T) {
+	var m sync.Mutex
+	var calls []string
+	overloaded := 2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		var form map[string]interface{}
+		json.Unmarshal(body, &form)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+
+		switch r.URL.Path {
+		case "/order/all":
+			if overloaded > 0 {
+				overloaded--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"error":{"message":"The system is currently overloaded.","name":"HTTPError"}}`))
+				return
+			}
+			w.Write([]byte(`[{"orderID":"a","ordStatus":"Canceled"},{"orderID":"b","ordStatus":"Canceled"}]`))
+		case "/position":
+			w.Write([]byte(`[{"symbol":"XBTUSD","currentQty":100,"markPrice":5000,"isOpen":true},
+				{"symbol":"ETHUSD","currentQty":-10,"markPrice":200,"isOpen":true}]`))
+		case "/order/closePosition":
+			if form["symbol"] == "XBTUSD" && fmt.Sprint(form["price"]) != "4950" {
+				t.Errorf("close price %v", form["price"])
+			}
+			if form["symbol"] == "ETHUSD" && form["price"] != nil {
+				t.Errorf("ETHUSD closed at %v", form["price"])
+			}
+			json.NewEncoder(w).Encode(swagger.Order{OrderID: "c-" + form["symbol"].(string), Symbol: form["symbol"].(string)})
+		case "/apiKey/disable":
+			w.Write([]byte(`{"id":"key","enabled":false}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetRetryPolicy(RetryPolicy{})
+	// ETHUSD has no tick size: closed at market
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", TickSize: 0.5}}, true)
+
+	result, err := b.KillSwitch(context.Background(), KillSwitchOptions{
+		Slippage: 0.01, DisableAPIKey: true, RetryInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Canceled) != 2 || len(result.Closed) != 2 || !result.Disabled {
+		t.Errorf("result %+v", result)
+	}
+	if len(calls) != 7 {
+		t.Errorf("calls %v", calls)
+	}
+
+	if _, err := b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 1, -1, "", 0, "XBTUSD", "", ""); err != ErrKillSwitch {
+		t.Errorf("order after kill switch: %v", err)
+	}
+	if results, _ := b.PlaceOrders(context.Background(), []OrderRequest{{Symbol: "XBTUSD", Side: SideBuy, OrderQty: 1}}); results[0].Err != ErrKillSwitch {
+		t.Errorf("bulk order after kill switch: %v", results[0].Err)
+	}
+	b.ResetKillSwitch()
+	if b.KillSwitchEngaged() {
+		t.Error("still engaged")
+	}
+}
+
+func TestBitMEX_KillSwitchContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := b.KillSwitch(ctx, KillSwitchOptions{RetryInterval: 5 * time.Millisecond}); err != context.DeadlineExceeded {
+		t.Errorf("err %v", err)
+	}
+	if !b.KillSwitchEngaged() {
+		t.Error("not engaged")
+	}
+}
+
+func TestBitMEX_KillSwitchClose(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orderID":"c","symbol":"XBTUSD","ordStatus":"New"}`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.updatePositions(&Response{Action: bitmexActionInitialData, raw: []byte(`[{"account":1,"symbol":"XBTUSD","currentQty":100}]`)})
+	atomic.StoreInt32(&b.killed, 1)
+
+	if _, err := b.ClosePosition(Price{}, "XBTUSD"); err != nil {
+		t.Errorf("close position: %v", err)
+	}
+	if _, err := b.CloseOrder(SideSell, OrdTypeLimit, MustPrice("5000"), 100, false, "", "XBTUSD"); err != nil {
+		t.Errorf("close order: %v", err)
+	}
+	if _, err := b.PlaceOrder2(SideSell, OrdTypeStop, MustPrice("4000"), Price{}, 100, -1, "", ExecReduceOnly, "XBTUSD", "", ""); err != nil {
+		t.Errorf("reduce-only order: %v", err)
+	}
+	if _, err := b.PlaceOrder2(SideSell, OrdTypeLimit, Price{}, MustPrice("5000"), 50, -1, "", 0, "XBTUSD", "", ""); err != nil {
+		t.Errorf("reducing order: %v", err)
+	}
+	if _, err := b.PlaceOrder2(SideBuy, OrdTypeLimit, Price{}, MustPrice("5000"), 1, -1, "", 0, "XBTUSD", "", ""); err != ErrKillSwitch {
+		t.Errorf("increasing order: %v", err)
+	}
+	if _, err := b.PlaceOrder2(SideSell, OrdTypeLimit, Price{}, MustPrice("5000"), 150, -1, "", 0, "XBTUSD", "", ""); err != ErrKillSwitch {
+		t.Errorf("flipping order: %v", err)
+	}
+}
@@ -0,0 +1,799 @@
+package bitmex
+
+import (
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// Order is an order that keeps track of which fields were sent
+type Order struct {
+	OrderID               string          `json:"orderID"`
+	ClOrdID               OptString       `json:"clOrdID"`
+	ClOrdLinkID           OptString       `json:"clOrdLinkID"`
+	Account               OptInt64        `json:"account"`
+	Symbol                OptString       `json:"symbol"`
+	Side                  Side            `json:"side,omitempty"`
+	SimpleOrderQty        OptFloat64      `json:"simpleOrderQty"`
+	OrderQty              OptInt64        `json:"orderQty"`
+	Price                 OptPrice        `json:"price"`
+	DisplayQty            OptInt64        `json:"displayQty"`
+	StopPx                OptPrice        `json:"stopPx"`
+	PegOffsetValue        OptPrice        `json:"pegOffsetValue"`
+	PegPriceType          PegPriceType    `json:"pegPriceType,omitempty"`
+	Currency              OptString       `json:"currency"`
+	SettlCurrency         OptString       `json:"settlCurrency"`
+	OrdType               OrdType         `json:"ordType,omitempty"`
+	TimeInForce           TimeInForce     `json:"timeInForce,omitempty"`
+	ExecInst              ExecInst        `json:"execInst,omitempty"`
+	ContingencyType       ContingencyType `json:"contingencyType,omitempty"`
+	ExDestination         OptString       `json:"exDestination"`
+	OrdStatus             OrdStatus       `json:"ordStatus,omitempty"`
+	Triggered             OptString       `json:"triggered"`
+	WorkingIndicator      OptBool         `json:"workingIndicator"`
+	OrdRejReason          OptString       `json:"ordRejReason"`
+	SimpleLeavesQty       OptFloat64      `json:"simpleLeavesQty"`
+	LeavesQty             OptInt64        `json:"leavesQty"`
+	SimpleCumQty          OptFloat64      `json:"simpleCumQty"`
+	CumQty                OptInt64        `json:"cumQty"`
+	AvgPx                 OptPrice        `json:"avgPx"`
+	MultiLegReportingType OptString       `json:"multiLegReportingType"`
+	Text                  OptString       `json:"text"`
+	TransactTime          OptTime         `json:"transactTime"`
+	Timestamp             OptTime         `json:"timestamp"`
+}
+
+// OrderFrom converts a swagger order. The swagger models cannot tell a zero
+// from an absent field, so zero fields are taken as absent.
+func OrderFrom(v *swagger.Order) Order {
+	return Order{
+		OrderID:               v.OrderID,
+		ClOrdID:               optString(v.ClOrdID),
+		ClOrdLinkID:           optString(v.ClOrdLinkID),
+		Account:               optInt64(v.Account),
+		Symbol:                optString(v.Symbol),
+		Side:                  Side(v.Side),
+		SimpleOrderQty:        optFloat64(v.SimpleOrderQty),
+		OrderQty:              optInt64(v.OrderQty),
+		Price:                 optPrice(v.Price),
+		DisplayQty:            optInt64(v.DisplayQty),
+		StopPx:                optPrice(v.StopPx),
+		PegOffsetValue:        optPrice(v.PegOffsetValue),
+		PegPriceType:          PegPriceType(v.PegPriceType),
+		Currency:              optString(v.Currency),
+		SettlCurrency:         optString(v.SettlCurrency),
+		OrdType:               OrdType(v.OrdType),
+		TimeInForce:           TimeInForce(v.TimeInForce),
+		ExecInst:              parseExecInst(v.ExecInst),
+		ContingencyType:       ContingencyType(v.ContingencyType),
+		ExDestination:         optString(v.ExDestination),
+		OrdStatus:             OrdStatus(v.OrdStatus),
+		Triggered:             optString(v.Triggered),
+		WorkingIndicator:      optBool(v.WorkingIndicator),
+		OrdRejReason:          optString(v.OrdRejReason),
+		SimpleLeavesQty:       optFloat64(v.SimpleLeavesQty),
+		LeavesQty:             optInt64(v.LeavesQty),
+		SimpleCumQty:          optFloat64(v.SimpleCumQty),
+		CumQty:                optInt64(v.CumQty),
+		AvgPx:                 optPrice(v.AvgPx),
+		MultiLegReportingType: optString(v.MultiLegReportingType),
+		Text:                  optString(v.Text),
+		TransactTime:          optTime(v.TransactTime),
+		Timestamp:             optTime(v.Timestamp),
+	}
+}
+
+// Swagger converts back to the swagger model; absent and null fields become
+// zero
+func (v *Order) Swagger() swagger.Order {
+	return swagger.Order{
+		OrderID:               v.OrderID,
+		ClOrdID:               v.ClOrdID.V,
+		ClOrdLinkID:           v.ClOrdLinkID.V,
+		Account:               v.Account.V,
+		Symbol:                v.Symbol.V,
+		Side:                  string(v.Side),
+		SimpleOrderQty:        v.SimpleOrderQty.V,
+		OrderQty:              v.OrderQty.V,
+		Price:                 v.Price.V.Float64(),
+		DisplayQty:            v.DisplayQty.V,
+		StopPx:                v.StopPx.V.Float64(),
+		PegOffsetValue:        v.PegOffsetValue.V.Float64(),
+		PegPriceType:          string(v.PegPriceType),
+		Currency:              v.Currency.V,
+		SettlCurrency:         v.SettlCurrency.V,
+		OrdType:               string(v.OrdType),
+		TimeInForce:           string(v.TimeInForce),
+		ExecInst:              v.ExecInst.String(),
+		ContingencyType:       string(v.ContingencyType),
+		ExDestination:         v.ExDestination.V,
+		OrdStatus:             string(v.OrdStatus),
+		Triggered:             v.Triggered.V,
+		WorkingIndicator:      v.WorkingIndicator.V,
+		OrdRejReason:          v.OrdRejReason.V,
+		SimpleLeavesQty:       v.SimpleLeavesQty.V,
+		LeavesQty:             v.LeavesQty.V,
+		SimpleCumQty:          v.SimpleCumQty.V,
+		CumQty:                v.CumQty.V,
+		AvgPx:                 v.AvgPx.V.Float64(),
+		MultiLegReportingType: v.MultiLegReportingType.V,
+		Text:                  v.Text.V,
+		TransactTime:          v.TransactTime.V,
+		Timestamp:             v.Timestamp.V,
+	}
+}
+
+// MarshalJSON encodes the present fields only
+func (v Order) MarshalJSON() ([]byte, error) {
+	return marshalPresent(v)
+}
+
+// Merge applies a partial update: fields present in u replace those of v,
+// including fields that u sets to null
+func (v *Order) Merge(u *Order) {
+	mergePresent(v, u)
+}
+
+// Execution is a fill or order event that keeps track of which fields were sent
+type Execution struct {
+	ExecID                string          `json:"execID"`
+	OrderID               OptString       `json:"orderID"`
+	ClOrdID               OptString       `json:"clOrdID"`
+	ClOrdLinkID           OptString       `json:"clOrdLinkID"`
+	Account               OptInt64        `json:"account"`
+	Symbol                OptString       `json:"symbol"`
+	Side                  Side            `json:"side,omitempty"`
+	LastQty               OptInt64        `json:"lastQty"`
+	LastPx                OptPrice        `json:"lastPx"`
+	UnderlyingLastPx      OptPrice        `json:"underlyingLastPx"`
+	LastMkt               OptString       `json:"lastMkt"`
+	LastLiquidityInd      OptString       `json:"lastLiquidityInd"`
+	SimpleOrderQty        OptFloat64      `json:"simpleOrderQty"`
+	OrderQty              OptInt64        `json:"orderQty"`
+	Price                 OptPrice        `json:"price"`
+	DisplayQty            OptInt64        `json:"displayQty"`
+	StopPx                OptPrice        `json:"stopPx"`
+	PegOffsetValue        OptPrice        `json:"pegOffsetValue"`
+	PegPriceType          PegPriceType    `json:"pegPriceType,omitempty"`
+	Currency              OptString       `json:"currency"`
+	SettlCurrency         OptString       `json:"settlCurrency"`
+	ExecType              OptString       `json:"execType"`
+	OrdType               OrdType         `json:"ordType,omitempty"`
+	TimeInForce           TimeInForce     `json:"timeInForce,omitempty"`
+	ExecInst              ExecInst        `json:"execInst,omitempty"`
+	ContingencyType       ContingencyType `json:"contingencyType,omitempty"`
+	ExDestination         OptString       `json:"exDestination"`
+	OrdStatus             OrdStatus       `json:"ordStatus,omitempty"`
+	Triggered             OptString       `json:"triggered"`
+	WorkingIndicator      OptBool         `json:"workingIndicator"`
+	OrdRejReason          OptString       `json:"ordRejReason"`
+	SimpleLeavesQty       OptFloat64      `json:"simpleLeavesQty"`
+	LeavesQty             OptInt64        `json:"leavesQty"`
+	SimpleCumQty          OptFloat64      `json:"simpleCumQty"`
+	CumQty                OptInt64        `json:"cumQty"`
+	AvgPx                 OptPrice        `json:"avgPx"`
+	Commission            OptFloat64      `json:"commission"`
+	TradePublishIndicator OptString       `json:"tradePublishIndicator"`
+	MultiLegReportingType OptString       `json:"multiLegReportingType"`
+	Text                  OptString       `json:"text"`
+	TrdMatchID            OptString       `json:"trdMatchID"`
+	ExecCost              OptInt64        `json:"execCost"`
+	ExecComm              OptInt64        `json:"execComm"`
+	HomeNotional          OptFloat64      `json:"homeNotional"`
+	ForeignNotional       OptFloat64      `json:"foreignNotional"`
+	TransactTime          OptTime         `json:"transactTime"`
+	Timestamp             OptTime         `json:"timestamp"`
+}
+
+// ExecutionFrom converts a swagger execution. The swagger models cannot tell a zero
+// from an absent field, so zero fields are taken as absent.
+func ExecutionFrom(v *swagger.Execution) Execution {
+	return Execution{
+		ExecID:                v.ExecID,
+		OrderID:               optString(v.OrderID),
+		ClOrdID:               optString(v.ClOrdID),
+		ClOrdLinkID:           optString(v.ClOrdLinkID),
+		Account:               optInt64(v.Account),
+		Symbol:                optString(v.Symbol),
+		Side:                  Side(v.Side),
+		LastQty:               optInt64(v.LastQty),
+		LastPx:                optPrice(v.LastPx),
+		UnderlyingLastPx:      optPrice(v.UnderlyingLastPx),
+		LastMkt:               optString(v.LastMkt),
+		LastLiquidityInd:      optString(v.LastLiquidityInd),
+		SimpleOrderQty:        optFloat64(v.SimpleOrderQty),
+		OrderQty:              optInt64(v.OrderQty),
+		Price:                 optPrice(v.Price),
+		DisplayQty:            optInt64(v.DisplayQty),
+		StopPx:                optPrice(v.StopPx),
+		PegOffsetValue:        optPrice(v.PegOffsetValue),
+		PegPriceType:          PegPriceType(v.PegPriceType),
+		Currency:              optString(v.Currency),
+		SettlCurrency:         optString(v.SettlCurrency),
+		ExecType:              optString(v.ExecType),
+		OrdType:               OrdType(v.OrdType),
+		TimeInForce:           TimeInForce(v.TimeInForce),
+		ExecInst:              parseExecInst(v.ExecInst),
+		ContingencyType:       ContingencyType(v.ContingencyType),
+		ExDestination:         optString(v.ExDestination),
+		OrdStatus:             OrdStatus(v.OrdStatus),
+		Triggered:             optString(v.Triggered),
+		WorkingIndicator:      optBool(v.WorkingIndicator),
+		OrdRejReason:          optString(v.OrdRejReason),
+		SimpleLeavesQty:       optFloat64(v.SimpleLeavesQty),
+		LeavesQty:             optInt64(v.LeavesQty),
+		SimpleCumQty:          optFloat64(v.SimpleCumQty),
+		CumQty:                optInt64(v.CumQty),
+		AvgPx:                 optPrice(v.AvgPx),
+		Commission:            optFloat64(v.Commission),
+		TradePublishIndicator: optString(v.TradePublishIndicator),
+		MultiLegReportingType: optString(v.MultiLegReportingType),
+		Text:                  optString(v.Text),
+		TrdMatchID:            optString(v.TrdMatchID),
+		ExecCost:              optInt64(v.ExecCost),
+		ExecComm:              optInt64(v.ExecComm),
+		HomeNotional:          optFloat64(v.HomeNotional),
+		ForeignNotional:       optFloat64(v.ForeignNotional),
+		TransactTime:          optTime(v.TransactTime),
+		Timestamp:             optTime(v.Timestamp),
+	}
+}
+
+// MarshalJSON encodes the present fields only
+func (v Execution) MarshalJSON() ([]byte, error) {
+	return marshalPresent(v)
+}
+
+// Merge applies a partial update: fields present in u replace those of v,
+// including fields that u sets to null
+func (v *Execution) Merge(u *Execution) {
+	mergePresent(v, u)
+}
+
+// Position is a position that keeps track of which fields were sent
+type Position struct {
+	Account              int64      `json:"account"`
+	Symbol               string     `json:"symbol"`
+	Currency             string     `json:"currency"`
+	Underlying           OptString  `json:"underlying"`
+	QuoteCurrency        OptString  `json:"quoteCurrency"`
+	Commission           OptFloat64 `json:"commission"`
+	InitMarginReq        OptFloat64 `json:"initMarginReq"`
+	MaintMarginReq       OptFloat64 `json:"maintMarginReq"`
+	RiskLimit            OptInt64   `json:"riskLimit"`
+	Leverage             OptFloat64 `json:"leverage"`
+	CrossMargin          OptBool    `json:"crossMargin"`
+	DeleveragePercentile OptFloat64 `json:"deleveragePercentile"`
+	RebalancedPnl        OptInt64   `json:"rebalancedPnl"`
+	PrevRealisedPnl      OptInt64   `json:"prevRealisedPnl"`
+	PrevUnrealisedPnl    OptInt64   `json:"prevUnrealisedPnl"`
+	PrevClosePrice       OptPrice   `json:"prevClosePrice"`
+	OpeningTimestamp     OptTime    `json:"openingTimestamp"`
+	OpeningQty           OptInt64   `json:"openingQty"`
+	OpeningCost          OptInt64   `json:"openingCost"`
+	OpeningComm          OptInt64   `json:"openingComm"`
+	OpenOrderBuyQty      OptInt64   `json:"openOrderBuyQty"`
+	OpenOrderBuyCost     OptInt64   `json:"openOrderBuyCost"`
+	OpenOrderBuyPremium  OptInt64   `json:"openOrderBuyPremium"`
+	OpenOrderSellQty     OptInt64   `json:"openOrderSellQty"`
+	OpenOrderSellCost    OptInt64   `json:"openOrderSellCost"`
+	OpenOrderSellPremium OptInt64   `json:"openOrderSellPremium"`
+	ExecBuyQty           OptInt64   `json:"execBuyQty"`
+	ExecBuyCost          OptInt64   `json:"execBuyCost"`
+	ExecSellQty          OptInt64   `json:"execSellQty"`
+	ExecSellCost         OptInt64   `json:"execSellCost"`
+	ExecQty              OptInt64   `json:"execQty"`
+	ExecCost             OptInt64   `json:"execCost"`
+	ExecComm             OptInt64   `json:"execComm"`
+	CurrentTimestamp     OptTime    `json:"currentTimestamp"`
+	CurrentQty           OptInt64   `json:"currentQty"`
+	CurrentCost          OptInt64   `json:"currentCost"`
+	CurrentComm          OptInt64   `json:"currentComm"`
+	RealisedCost         OptInt64   `json:"realisedCost"`
+	UnrealisedCost       OptInt64   `json:"unrealisedCost"`
+	GrossOpenCost        OptInt64   `json:"grossOpenCost"`
+	GrossOpenPremium     OptInt64   `json:"grossOpenPremium"`
+	GrossExecCost        OptInt64   `json:"grossExecCost"`
+	IsOpen               OptBool    `json:"isOpen"`
+	MarkPrice            OptPrice   `json:"markPrice"`
+	MarkValue            OptInt64   `json:"markValue"`
+	RiskValue            OptInt64   `json:"riskValue"`
+	HomeNotional         OptFloat64 `json:"homeNotional"`
+	ForeignNotional      OptFloat64 `json:"foreignNotional"`
+	PosState             OptString  `json:"posState"`
+	PosCost              OptInt64   `json:"posCost"`
+	PosCost2             OptInt64   `json:"posCost2"`
+	PosCross             OptInt64   `json:"posCross"`
+	PosInit              OptInt64   `json:"posInit"`
+	PosComm              OptInt64   `json:"posComm"`
+	PosLoss              OptInt64   `json:"posLoss"`
+	PosMargin            OptInt64   `json:"posMargin"`
+	PosMaint             OptInt64   `json:"posMaint"`
+	PosAllowance         OptInt64   `json:"posAllowance"`
+	TaxableMargin        OptInt64   `json:"taxableMargin"`
+	InitMargin           OptInt64   `json:"initMargin"`
+	MaintMargin          OptInt64   `json:"maintMargin"`
+	SessionMargin        OptInt64   `json:"sessionMargin"`
+	TargetExcessMargin   OptInt64   `json:"targetExcessMargin"`
+	VarMargin            OptInt64   `json:"varMargin"`
+	RealisedGrossPnl     OptInt64   `json:"realisedGrossPnl"`
+	RealisedTax          OptInt64   `json:"realisedTax"`
+	RealisedPnl          OptInt64   `json:"realisedPnl"`
+	UnrealisedGrossPnl   OptInt64   `json:"unrealisedGrossPnl"`
+	LongBankrupt         OptInt64   `json:"longBankrupt"`
+	ShortBankrupt        OptInt64   `json:"shortBankrupt"`
+	TaxBase              OptInt64   `json:"taxBase"`
+	IndicativeTaxRate    OptFloat64 `json:"indicativeTaxRate"`
+	IndicativeTax        OptInt64   `json:"indicativeTax"`
+	UnrealisedTax        OptInt64   `json:"unrealisedTax"`
+	UnrealisedPnl        OptInt64   `json:"unrealisedPnl"`
+	UnrealisedPnlPcnt    OptFloat64 `json:"unrealisedPnlPcnt"`
+	UnrealisedRoePcnt    OptFloat64 `json:"unrealisedRoePcnt"`
+	SimpleQty            OptFloat64 `json:"simpleQty"`
+	SimpleCost           OptFloat64 `json:"simpleCost"`
+	SimpleValue          OptFloat64 `json:"simpleValue"`
+	SimplePnl            OptFloat64 `json:"simplePnl"`
+	SimplePnlPcnt        OptFloat64 `json:"simplePnlPcnt"`
+	AvgCostPrice         OptPrice   `json:"avgCostPrice"`
+	AvgEntryPrice        OptPrice   `json:"avgEntryPrice"`
+	BreakEvenPrice       OptPrice   `json:"breakEvenPrice"`
+	MarginCallPrice      OptPrice   `json:"marginCallPrice"`
+	LiquidationPrice     OptPrice   `json:"liquidationPrice"`
+	BankruptPrice        OptPrice   `json:"bankruptPrice"`
+	Timestamp            OptTime    `json:"timestamp"`
+	LastPrice            OptPrice   `json:"lastPrice"`
+	LastValue            OptInt64   `json:"lastValue"`
+}
+
+// PositionFrom converts a swagger position. The swagger models cannot tell a zero
+// from an absent field, so zero fields are taken as absent.
+func PositionFrom(v *swagger.Position) Position {
+	return Position{
+		Account:              v.Account,
+		Symbol:               v.Symbol,
+		Currency:             v.Currency,
+		Underlying:           optString(v.Underlying),
+		QuoteCurrency:        optString(v.QuoteCurrency),
+		Commission:           optFloat64(v.Commission),
+		InitMarginReq:        optFloat64(v.InitMarginReq),
+		MaintMarginReq:       optFloat64(v.MaintMarginReq),
+		RiskLimit:            optInt64(v.RiskLimit),
+		Leverage:             optFloat64(v.Leverage),
+		CrossMargin:          optBool(v.CrossMargin),
+		DeleveragePercentile: optFloat64(v.DeleveragePercentile),
+		RebalancedPnl:        optInt64(v.RebalancedPnl),
+		PrevRealisedPnl:      optInt64(v.PrevRealisedPnl),
+		PrevUnrealisedPnl:    optInt64(v.PrevUnrealisedPnl),
+		PrevClosePrice:       optPrice(v.PrevClosePrice),
+		OpeningTimestamp:     optTime(v.OpeningTimestamp),
+		OpeningQty:           optInt64(v.OpeningQty),
+		OpeningCost:          optInt64(v.OpeningCost),
+		OpeningComm:          optInt64(v.OpeningComm),
+		OpenOrderBuyQty:      optInt64(v.OpenOrderBuyQty),
+		OpenOrderBuyCost:     optInt64(v.OpenOrderBuyCost),
+		OpenOrderBuyPremium:  optInt64(v.OpenOrderBuyPremium),
+		OpenOrderSellQty:     optInt64(v.OpenOrderSellQty),
+		OpenOrderSellCost:    optInt64(v.OpenOrderSellCost),
+		OpenOrderSellPremium: optInt64(v.OpenOrderSellPremium),
+		ExecBuyQty:           optInt64(v.ExecBuyQty),
+		ExecBuyCost:          optInt64(v.ExecBuyCost),
+		ExecSellQty:          optInt64(v.ExecSellQty),
+		ExecSellCost:         optInt64(v.ExecSellCost),
+		ExecQty:              optInt64(v.ExecQty),
+		ExecCost:             optInt64(v.ExecCost),
+		ExecComm:             optInt64(v.ExecComm),
+		CurrentTimestamp:     optTime(v.CurrentTimestamp),
+		CurrentQty:           optInt64(v.CurrentQty),
+		CurrentCost:          optInt64(v.CurrentCost),
+		CurrentComm:          optInt64(v.CurrentComm),
+		RealisedCost:         optInt64(v.RealisedCost),
+		UnrealisedCost:       optInt64(v.UnrealisedCost),
+		GrossOpenCost:        optInt64(v.GrossOpenCost),
+		GrossOpenPremium:     optInt64(v.GrossOpenPremium),
+		GrossExecCost:        optInt64(v.GrossExecCost),
+		IsOpen:               optBool(v.IsOpen),
+		MarkPrice:            optPrice(v.MarkPrice),
+		MarkValue:            optInt64(v.MarkValue),
+		RiskValue:            optInt64(v.RiskValue),
+		HomeNotional:         optFloat64(v.HomeNotional),
+		ForeignNotional:      optFloat64(v.ForeignNotional),
+		PosState:             optString(v.PosState),
+		PosCost:              optInt64(v.PosCost),
+		PosCost2:             optInt64(v.PosCost2),
+		PosCross:             optInt64(v.PosCross),
+		PosInit:              optInt64(v.PosInit),
+		PosComm:              optInt64(v.PosComm),
+		PosLoss:              optInt64(v.PosLoss),
+		PosMargin:            optInt64(v.PosMargin),
+		PosMaint:             optInt64(v.PosMaint),
+		PosAllowance:         optInt64(v.PosAllowance),
+		TaxableMargin:        optInt64(v.TaxableMargin),
+		InitMargin:           optInt64(v.InitMargin),
+		MaintMargin:          optInt64(v.MaintMargin),
+		SessionMargin:        optInt64(v.SessionMargin),
+		TargetExcessMargin:   optInt64(v.TargetExcessMargin),
+		VarMargin:            optInt64(v.VarMargin),
+		RealisedGrossPnl:     optInt64(v.RealisedGrossPnl),
+		RealisedTax:          optInt64(v.RealisedTax),
+		RealisedPnl:          optInt64(v.RealisedPnl),
+		UnrealisedGrossPnl:   optInt64(v.UnrealisedGrossPnl),
+		LongBankrupt:         optInt64(v.LongBankrupt),
+		ShortBankrupt:        optInt64(v.ShortBankrupt),
+		TaxBase:              optInt64(v.TaxBase),
+		IndicativeTaxRate:    optFloat64(v.IndicativeTaxRate),
+		IndicativeTax:        optInt64(v.IndicativeTax),
+		UnrealisedTax:        optInt64(v.UnrealisedTax),
+		UnrealisedPnl:        optInt64(v.UnrealisedPnl),
+		UnrealisedPnlPcnt:    optFloat64(v.UnrealisedPnlPcnt),
+		UnrealisedRoePcnt:    optFloat64(v.UnrealisedRoePcnt),
+		SimpleQty:            optFloat64(v.SimpleQty),
+		SimpleCost:           optFloat64(v.SimpleCost),
+		SimpleValue:          optFloat64(v.SimpleValue),
+		SimplePnl:            optFloat64(v.SimplePnl),
+		SimplePnlPcnt:        optFloat64(v.SimplePnlPcnt),
+		AvgCostPrice:         optPrice(v.AvgCostPrice),
+		AvgEntryPrice:        optPrice(v.AvgEntryPrice),
+		BreakEvenPrice:       optPrice(v.BreakEvenPrice),
+		MarginCallPrice:      optPrice(v.MarginCallPrice),
+		LiquidationPrice:     optPrice(v.LiquidationPrice),
+		BankruptPrice:        optPrice(v.BankruptPrice),
+		Timestamp:            optTime(v.Timestamp),
+		LastPrice:            optPrice(v.LastPrice),
+		LastValue:            optInt64(v.LastValue),
+	}
+}
+
+// MarshalJSON encodes the present fields only
+func (v Position) MarshalJSON() ([]byte, error) {
+	return marshalPresent(v)
+}
+
+// Merge applies a partial update: fields present in u replace those of v,
+// including fields that u sets to null
+func (v *Position) Merge(u *Position) {
+	mergePresent(v, u)
+}
+
+// Instrument is an instrument that keeps track of which fields were sent
+type Instrument struct {
+	Symbol                         string     `json:"symbol"`
+	RootSymbol                     OptString  `json:"rootSymbol"`
+	State                          OptString  `json:"state"`
+	Typ                            OptString  `json:"typ"`
+	Listing                        OptTime    `json:"listing"`
+	Front                          OptTime    `json:"front"`
+	Expiry                         OptTime    `json:"expiry"`
+	Settle                         OptTime    `json:"settle"`
+	RelistInterval                 OptTime    `json:"relistInterval"`
+	InverseLeg                     OptString  `json:"inverseLeg"`
+	SellLeg                        OptString  `json:"sellLeg"`
+	BuyLeg                         OptString  `json:"buyLeg"`
+	PositionCurrency               OptString  `json:"positionCurrency"`
+	Underlying                     OptString  `json:"underlying"`
+	QuoteCurrency                  OptString  `json:"quoteCurrency"`
+	UnderlyingSymbol               OptString  `json:"underlyingSymbol"`
+	Reference                      OptString  `json:"reference"`
+	ReferenceSymbol                OptString  `json:"referenceSymbol"`
+	CalcInterval                   OptTime    `json:"calcInterval"`
+	PublishInterval                OptTime    `json:"publishInterval"`
+	PublishTime                    OptTime    `json:"publishTime"`
+	MaxOrderQty                    OptInt64   `json:"maxOrderQty"`
+	MaxPrice                       OptPrice   `json:"maxPrice"`
+	LotSize                        OptInt64   `json:"lotSize"`
+	TickSize                       OptPrice   `json:"tickSize"`
+	Multiplier                     OptInt64   `json:"multiplier"`
+	SettlCurrency                  OptString  `json:"settlCurrency"`
+	UnderlyingToPositionMultiplier OptInt64   `json:"underlyingToPositionMultiplier"`
+	UnderlyingToSettleMultiplier   OptInt64   `json:"underlyingToSettleMultiplier"`
+	QuoteToSettleMultiplier        OptInt64   `json:"quoteToSettleMultiplier"`
+	IsQuanto                       OptBool    `json:"isQuanto"`
+	IsInverse                      OptBool    `json:"isInverse"`
+	InitMargin                     OptFloat64 `json:"initMargin"`
+	MaintMargin                    OptFloat64 `json:"maintMargin"`
+	RiskLimit                      OptInt64   `json:"riskLimit"`
+	RiskStep                       OptInt64   `json:"riskStep"`
+	Limit                          OptFloat64 `json:"limit"`
+	Capped                         OptBool    `json:"capped"`
+	Taxed                          OptBool    `json:"taxed"`
+	Deleverage                     OptBool    `json:"deleverage"`
+	MakerFee                       OptFloat64 `json:"makerFee"`
+	TakerFee                       OptFloat64 `json:"takerFee"`
+	SettlementFee                  OptFloat64 `json:"settlementFee"`
+	InsuranceFee                   OptFloat64 `json:"insuranceFee"`
+	FundingBaseSymbol              OptString  `json:"fundingBaseSymbol"`
+	FundingQuoteSymbol             OptString  `json:"fundingQuoteSymbol"`
+	FundingPremiumSymbol           OptString  `json:"fundingPremiumSymbol"`
+	FundingTimestamp               OptTime    `json:"fundingTimestamp"`
+	FundingInterval                OptTime    `json:"fundingInterval"`
+	FundingRate                    OptFloat64 `json:"fundingRate"`
+	IndicativeFundingRate          OptFloat64 `json:"indicativeFundingRate"`
+	RebalanceTimestamp             OptTime    `json:"rebalanceTimestamp"`
+	RebalanceInterval              OptTime    `json:"rebalanceInterval"`
+	OpeningTimestamp               OptTime    `json:"openingTimestamp"`
+	ClosingTimestamp               OptTime    `json:"closingTimestamp"`
+	SessionInterval                OptTime    `json:"sessionInterval"`
+	PrevClosePrice                 OptPrice   `json:"prevClosePrice"`
+	LimitDownPrice                 OptPrice   `json:"limitDownPrice"`
+	LimitUpPrice                   OptPrice   `json:"limitUpPrice"`
+	BankruptLimitDownPrice         OptPrice   `json:"bankruptLimitDownPrice"`
+	BankruptLimitUpPrice           OptPrice   `json:"bankruptLimitUpPrice"`
+	PrevTotalVolume                OptInt64   `json:"prevTotalVolume"`
+	TotalVolume                    OptInt64   `json:"totalVolume"`
+	Volume                         OptInt64   `json:"volume"`
+	Volume24h                      OptInt64   `json:"volume24h"`
+	PrevTotalTurnover              OptInt64   `json:"prevTotalTurnover"`
+	TotalTurnover                  OptInt64   `json:"totalTurnover"`
+	Turnover                       OptInt64   `json:"turnover"`
+	Turnover24h                    OptInt64   `json:"turnover24h"`
+	PrevPrice24h                   OptPrice   `json:"prevPrice24h"`
+	Vwap                           OptPrice   `json:"vwap"`
+	HighPrice                      OptPrice   `json:"highPrice"`
+	LowPrice                       OptPrice   `json:"lowPrice"`
+	LastPrice                      OptPrice   `json:"lastPrice"`
+	LastPriceProtected             OptPrice   `json:"lastPriceProtected"`
+	LastTickDirection              OptString  `json:"lastTickDirection"`
+	LastChangePcnt                 OptFloat64 `json:"lastChangePcnt"`
+	BidPrice                       OptPrice   `json:"bidPrice"`
+	MidPrice                       OptPrice   `json:"midPrice"`
+	AskPrice                       OptPrice   `json:"askPrice"`
+	ImpactBidPrice                 OptPrice   `json:"impactBidPrice"`
+	ImpactMidPrice                 OptPrice   `json:"impactMidPrice"`
+	ImpactAskPrice                 OptPrice   `json:"impactAskPrice"`
+	HasLiquidity                   OptBool    `json:"hasLiquidity"`
+	OpenInterest                   OptInt64   `json:"openInterest"`
+	OpenValue                      OptInt64   `json:"openValue"`
+	FairMethod                     OptString  `json:"fairMethod"`
+	FairBasisRate                  OptFloat64 `json:"fairBasisRate"`
+	FairBasis                      OptFloat64 `json:"fairBasis"`
+	FairPrice                      OptPrice   `json:"fairPrice"`
+	MarkMethod                     OptString  `json:"markMethod"`
+	MarkPrice                      OptPrice   `json:"markPrice"`
+	IndicativeTaxRate              OptFloat64 `json:"indicativeTaxRate"`
+	IndicativeSettlePrice          OptPrice   `json:"indicativeSettlePrice"`
+	SettledPrice                   OptPrice   `json:"settledPrice"`
+	Timestamp                      OptTime    `json:"timestamp"`
+}
+
+// InstrumentFrom converts a swagger instrument. The swagger models cannot tell a zero
+// from an absent field, so zero fields are taken as absent.
+func InstrumentFrom(v *swagger.Instrument) Instrument {
+	return Instrument{
+		Symbol:                         v.Symbol,
+		RootSymbol:                     optString(v.RootSymbol),
+		State:                          optString(v.State),
+		Typ:                            optString(v.Typ),
+		Listing:                        optTime(v.Listing),
+		Front:                          optTime(v.Front),
+		Expiry:                         optTime(v.Expiry),
+		Settle:                         optTime(v.Settle),
+		RelistInterval:                 optTime(v.RelistInterval),
+		InverseLeg:                     optString(v.InverseLeg),
+		SellLeg:                        optString(v.SellLeg),
+		BuyLeg:                         optString(v.BuyLeg),
+		PositionCurrency:               optString(v.PositionCurrency),
+		Underlying:                     optString(v.Underlying),
+		QuoteCurrency:                  optString(v.QuoteCurrency),
+		UnderlyingSymbol:               optString(v.UnderlyingSymbol),
+		Reference:                      optString(v.Reference),
+		ReferenceSymbol:                optString(v.ReferenceSymbol),
+		CalcInterval:                   optTime(v.CalcInterval),
+		PublishInterval:                optTime(v.PublishInterval),
+		PublishTime:                    optTime(v.PublishTime),
+		MaxOrderQty:                    optInt64(v.MaxOrderQty),
+		MaxPrice:                       optPrice(v.MaxPrice),
+		LotSize:                        optInt64(v.LotSize),
+		TickSize:                       optPrice(v.TickSize),
+		Multiplier:                     optInt64(v.Multiplier),
+		SettlCurrency:                  optString(v.SettlCurrency),
+		UnderlyingToPositionMultiplier: optInt64(v.UnderlyingToPositionMultiplier),
+		UnderlyingToSettleMultiplier:   optInt64(v.UnderlyingToSettleMultiplier),
+		QuoteToSettleMultiplier:        optInt64(v.QuoteToSettleMultiplier),
+		IsQuanto:                       optBool(v.IsQuanto),
+		IsInverse:                      optBool(v.IsInverse),
+		InitMargin:                     optFloat64(v.InitMargin),
+		MaintMargin:                    optFloat64(v.MaintMargin),
+		RiskLimit:                      optInt64(v.RiskLimit),
+		RiskStep:                       optInt64(v.RiskStep),
+		Limit:                          optFloat64(v.Limit),
+		Capped:                         optBool(v.Capped),
+		Taxed:                          optBool(v.Taxed),
+		Deleverage:                     optBool(v.Deleverage),
+		MakerFee:                       optFloat64(v.MakerFee),
+		TakerFee:                       optFloat64(v.TakerFee),
+		SettlementFee:                  optFloat64(v.SettlementFee),
+		InsuranceFee:                   optFloat64(v.InsuranceFee),
+		FundingBaseSymbol:              optString(v.FundingBaseSymbol),
+		FundingQuoteSymbol:             optString(v.FundingQuoteSymbol),
+		FundingPremiumSymbol:           optString(v.FundingPremiumSymbol),
+		FundingTimestamp:               optTime(v.FundingTimestamp),
+		FundingInterval:                optTime(v.FundingInterval),
+		FundingRate:                    optFloat64(v.FundingRate),
+		IndicativeFundingRate:          optFloat64(v.IndicativeFundingRate),
+		RebalanceTimestamp:             optTime(v.RebalanceTimestamp),
+		RebalanceInterval:              optTime(v.RebalanceInterval),
+		OpeningTimestamp:               optTime(v.OpeningTimestamp),
+		ClosingTimestamp:               optTime(v.ClosingTimestamp),
+		SessionInterval:                optTime(v.SessionInterval),
+		PrevClosePrice:                 optPrice(v.PrevClosePrice),
+		LimitDownPrice:                 optPrice(v.LimitDownPrice),
+		LimitUpPrice:                   optPrice(v.LimitUpPrice),
+		BankruptLimitDownPrice:         optPrice(v.BankruptLimitDownPrice),
+		BankruptLimitUpPrice:           optPrice(v.BankruptLimitUpPrice),
+		PrevTotalVolume:                optInt64(v.PrevTotalVolume),
+		TotalVolume:                    optInt64(v.TotalVolume),
+		Volume:                         optInt64(v.Volume),
+		Volume24h:                      optInt64(v.Volume24h),
+		PrevTotalTurnover:              optInt64(v.PrevTotalTurnover),
+		TotalTurnover:                  optInt64(v.TotalTurnover),
+		Turnover:                       optInt64(v.Turnover),
+		Turnover24h:                    optInt64(v.Turnover24h),
+		PrevPrice24h:                   optPrice(v.PrevPrice24h),
+		Vwap:                           optPrice(v.Vwap),
+		HighPrice:                      optPrice(v.HighPrice),
+		LowPrice:                       optPrice(v.LowPrice),
+		LastPrice:                      optPrice(v.LastPrice),
+		LastPriceProtected:             optPrice(v.LastPriceProtected),
+		LastTickDirection:              optString(v.LastTickDirection),
+		LastChangePcnt:                 optFloat64(v.LastChangePcnt),
+		BidPrice:                       optPrice(v.BidPrice),
+		MidPrice:                       optPrice(v.MidPrice),
+		AskPrice:                       optPrice(v.AskPrice),
+		ImpactBidPrice:                 optPrice(v.ImpactBidPrice),
+		ImpactMidPrice:                 optPrice(v.ImpactMidPrice),
+		ImpactAskPrice:                 optPrice(v.ImpactAskPrice),
+		HasLiquidity:                   optBool(v.HasLiquidity),
+		OpenInterest:                   optInt64(v.OpenInterest),
+		OpenValue:                      optInt64(v.OpenValue),
+		FairMethod:                     optString(v.FairMethod),
+		FairBasisRate:                  optFloat64(v.FairBasisRate),
+		FairBasis:                      optFloat64(v.FairBasis),
+		FairPrice:                      optPrice(v.FairPrice),
+		MarkMethod:                     optString(v.MarkMethod),
+		MarkPrice:                      optPrice(v.MarkPrice),
+		IndicativeTaxRate:              optFloat64(v.IndicativeTaxRate),
+		IndicativeSettlePrice:          optPrice(v.IndicativeSettlePrice),
+		SettledPrice:                   optPrice(v.SettledPrice),
+		Timestamp:                      optTime(v.Timestamp),
+	}
+}
+
+// MarshalJSON encodes the present fields only
+func (v Instrument) MarshalJSON() ([]byte, error) {
+	return marshalPresent(v)
+}
+
+// Merge applies a partial update: fields present in u replace those of v,
+// including fields that u sets to null
+func (v *Instrument) Merge(u *Instrument) {
+	mergePresent(v, u)
+}
+
+// Margin is a margin account that keeps track of which fields were sent
+type Margin struct {
+	Account            int64      `json:"account"`
+	Currency           string     `json:"currency"`
+	RiskLimit          OptInt64   `json:"riskLimit"`
+	PrevState          OptString  `json:"prevState"`
+	State              OptString  `json:"state"`
+	Action             OptString  `json:"action"`
+	Amount             OptInt64   `json:"amount"`
+	PendingCredit      OptInt64   `json:"pendingCredit"`
+	PendingDebit       OptInt64   `json:"pendingDebit"`
+	ConfirmedDebit     OptInt64   `json:"confirmedDebit"`
+	PrevRealisedPnl    OptInt64   `json:"prevRealisedPnl"`
+	PrevUnrealisedPnl  OptInt64   `json:"prevUnrealisedPnl"`
+	GrossComm          OptInt64   `json:"grossComm"`
+	GrossOpenCost      OptInt64   `json:"grossOpenCost"`
+	GrossOpenPremium   OptInt64   `json:"grossOpenPremium"`
+	GrossExecCost      OptInt64   `json:"grossExecCost"`
+	GrossMarkValue     OptInt64   `json:"grossMarkValue"`
+	RiskValue          OptInt64   `json:"riskValue"`
+	TaxableMargin      OptInt64   `json:"taxableMargin"`
+	InitMargin         OptInt64   `json:"initMargin"`
+	MaintMargin        OptInt64   `json:"maintMargin"`
+	SessionMargin      OptInt64   `json:"sessionMargin"`
+	TargetExcessMargin OptInt64   `json:"targetExcessMargin"`
+	VarMargin          OptInt64   `json:"varMargin"`
+	RealisedPnl        OptInt64   `json:"realisedPnl"`
+	UnrealisedPnl      OptInt64   `json:"unrealisedPnl"`
+	IndicativeTax      OptInt64   `json:"indicativeTax"`
+	UnrealisedProfit   OptInt64   `json:"unrealisedProfit"`
+	SyntheticMargin    OptInt64   `json:"syntheticMargin"`
+	WalletBalance      OptInt64   `json:"walletBalance"`
+	MarginBalance      OptInt64   `json:"marginBalance"`
+	MarginBalancePcnt  OptFloat64 `json:"marginBalancePcnt"`
+	MarginLeverage     OptFloat64 `json:"marginLeverage"`
+	MarginUsedPcnt     OptFloat64 `json:"marginUsedPcnt"`
+	ExcessMargin       OptInt64   `json:"excessMargin"`
+	ExcessMarginPcnt   OptFloat64 `json:"excessMarginPcnt"`
+	AvailableMargin    OptInt64   `json:"availableMargin"`
+	WithdrawableMargin OptInt64   `json:"withdrawableMargin"`
+	Timestamp          OptTime    `json:"timestamp"`
+	GrossLastValue     OptInt64   `json:"grossLastValue"`
+	Commission         OptFloat64 `json:"commission"`
+}
+
+// MarginFrom converts a swagger margin. The swagger models cannot tell a zero
+// from an absent field, so zero fields are taken as absent.
+func MarginFrom(v *swagger.Margin) Margin {
+	return Margin{
+		Account:            v.Account,
+		Currency:           v.Currency,
+		RiskLimit:          optInt64(v.RiskLimit),
+		PrevState:          optString(v.PrevState),
+		State:              optString(v.State),
+		Action:             optString(v.Action),
+		Amount:             optInt64(v.Amount),
+		PendingCredit:      optInt64(v.PendingCredit),
+		PendingDebit:       optInt64(v.PendingDebit),
+		ConfirmedDebit:     optInt64(v.ConfirmedDebit),
+		PrevRealisedPnl:    optInt64(v.PrevRealisedPnl),
+		PrevUnrealisedPnl:  optInt64(v.PrevUnrealisedPnl),
+		GrossComm:          optInt64(v.GrossComm),
+		GrossOpenCost:      optInt64(v.GrossOpenCost),
+		GrossOpenPremium:   optInt64(v.GrossOpenPremium),
+		GrossExecCost:      optInt64(v.GrossExecCost),
+		GrossMarkValue:     optInt64(v.GrossMarkValue),
+		RiskValue:          optInt64(v.RiskValue),
+		TaxableMargin:      optInt64(v.TaxableMargin),
+		InitMargin:         optInt64(v.InitMargin),
+		MaintMargin:        optInt64(v.MaintMargin),
+		SessionMargin:      optInt64(v.SessionMargin),
+		TargetExcessMargin: optInt64(v.TargetExcessMargin),
+		VarMargin:          optInt64(v.VarMargin),
+		RealisedPnl:        optInt64(v.RealisedPnl),
+		UnrealisedPnl:      optInt64(v.UnrealisedPnl),
+		IndicativeTax:      optInt64(v.IndicativeTax),
+		UnrealisedProfit:   optInt64(v.UnrealisedProfit),
+		SyntheticMargin:    optInt64(v.SyntheticMargin),
+		WalletBalance:      optInt64(v.WalletBalance),
+		MarginBalance:      optInt64(v.MarginBalance),
+		MarginBalancePcnt:  optFloat64(v.MarginBalancePcnt),
+		MarginLeverage:     optFloat64(v.MarginLeverage),
+		MarginUsedPcnt:     optFloat64(v.MarginUsedPcnt),
+		ExcessMargin:       optInt64(v.ExcessMargin),
+		ExcessMarginPcnt:   optFloat64(v.ExcessMarginPcnt),
+		AvailableMargin:    optInt64(v.AvailableMargin),
+		WithdrawableMargin: optInt64(v.WithdrawableMargin),
+		Timestamp:          optTime(v.Timestamp),
+		GrossLastValue:     optInt64(v.GrossLastValue),
+		Commission:         optFloat64(v.Commission),
+	}
+}
+
+// MarshalJSON encodes the present fields only
+func (v Margin) MarshalJSON() ([]byte, error) {
+	return marshalPresent(v)
+}
+
+// Merge applies a partial update: fields present in u replace those of v,
+// including fields that u sets to null
+func (v *Margin) Merge(u *Margin) {
+	mergePresent(v, u)
+}
+
+// Trade is a public trade with exact size and price
+type Trade struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Symbol        string    `json:"symbol"`
+	Side          Side      `json:"side,omitempty"`
+	Size          int64     `json:"size,omitempty"`
+	Price         Price     `json:"price"`
+	TickDirection string    `json:"tickDirection,omitempty"`
+	TrdMatchID    string    `json:"trdMatchID,omitempty"`
+	GrossValue    int64     `json:"grossValue,omitempty"`
+}
+
+// TradeFrom converts a swagger trade
+func TradeFrom(t *swagger.Trade) Trade {
+	return Trade{
+		Timestamp:     t.Timestamp,
+		Symbol:        t.Symbol,
+		Side:          Side(t.Side),
+		Size:          t.Size,
+		Price:         PriceOf(t.Price),
+		TickDirection: t.TickDirection,
+		TrdMatchID:    t.TrdMatchID,
+		GrossValue:    t.GrossValue,
+	}
+}
@@ -0,0 +1,278 @@
+package bitmex
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// The Opt* types hold a model field in one of three states: absent
+// (Set == false), null (Set && Null) or a value (Set && !Null), which may be
+// zero. A market order has "price": null, a partial update leaves out the
+// fields that did not change. Enum fields (Side, OrdType, ...) are plain
+// typed values; their zero value stands for absent.
+
+// OptString is an optional, nullable string
+type OptString struct {
+	V    string
+	Set  bool // present
+	Null bool // present and null
+}
+
+// OptInt64 is an optional, nullable integer
+type OptInt64 struct {
+	V    int64
+	Set  bool // present
+	Null bool // present and null
+}
+
+// OptFloat64 is an optional, nullable float
+type OptFloat64 struct {
+	V    float64
+	Set  bool // present
+	Null bool // present and null
+}
+
+// OptBool is an optional, nullable bool
+type OptBool struct {
+	V    bool
+	Set  bool // present
+	Null bool // present and null
+}
+
+// OptTime is an optional, nullable timestamp
+type OptTime struct {
+	V    time.Time
+	Set  bool // present
+	Null bool // present and null
+}
+
+// OptPrice is an optional, nullable price. Unlike Price, a present zero is
+// encoded as 0 rather than null.
+type OptPrice struct {
+	V    Price
+	Set  bool // present
+	Null bool // present and null
+}
+
+// Valid reports whether o holds a value
+func (o OptString) Valid() bool { return o.Set && !o.Null }
+
+// Get returns the value and whether there is one
+func (o OptString) Get() (string, bool) { return o.V, o.Valid() }
+
+// Or returns the value, or def when there is none
+func (o OptString) Or(def string) string {
+	if o.Valid() {
+		return o.V
+	}
+	return def
+}
+
+// Valid reports whether o holds a value
+func (o OptInt64) Valid() bool { return o.Set && !o.Null }
+
+// Get returns the value and whether there is one
+func (o OptInt64) Get() (int64, bool) { return o.V, o.Valid() }
+
+// Or returns the value, or def when there is none
+func (o OptInt64) Or(def int64) int64 {
+	if o.Valid() {
+		return o.V
+	}
+	return def
+}
+
+// Valid reports whether o holds a value
+func (o OptFloat64) Valid() bool { return o.Set && !o.Null }
+
+// Get returns the value and whether there is one
+func (o OptFloat64) Get() (float64, bool) { return o.V, o.Valid() }
+
+// Or returns the value, or def when there is none
+func (o OptFloat64) Or(def float64) float64 {
+	if o.Valid() {
+		return o.V
+	}
+	return def
+}
+
+// Valid reports whether o holds a value
+func (o OptBool) Valid() bool { return o.Set && !o.Null }
+
+// Get returns the value and whether there is one
+func (o OptBool) Get() (bool, bool) { return o.V, o.Valid() }
+
+// Or returns the value, or def when there is none
+func (o OptBool) Or(def bool) bool {
+	if o.Valid() {
+		return o.V
+	}
+	return def
+}
+
+// Valid reports whether o holds a value
+func (o OptTime) Valid() bool { return o.Set && !o.Null }
+
+// Get returns the value and whether there is one
+func (o OptTime) Get() (time.Time, bool) { return o.V, o.Valid() }
+
+// Or returns the value, or def when there is none
+func (o OptTime) Or(def time.Time) time.Time {
+	if o.Valid() {
+		return o.V
+	}
+	return def
+}
+
+// Valid reports whether o holds a value
+func (o OptPrice) Valid() bool { return o.Set && !o.Null }
+
+// Get returns the value and whether there is one
+func (o OptPrice) Get() (Price, bool) { return o.V, o.Valid() }
+
+// Or returns the value, or def when there is none
+func (o OptPrice) Or(def Price) Price {
+	if o.Valid() {
+		return o.V
+	}
+	return def
+}
+
+func (o OptString) present() bool  { return o.Set }
+func (o OptInt64) present() bool   { return o.Set }
+func (o OptFloat64) present() bool { return o.Set }
+func (o OptBool) present() bool    { return o.Set }
+func (o OptTime) present() bool    { return o.Set }
+func (o OptPrice) present() bool   { return o.Set }
+
+func (o OptString) MarshalJSON() ([]byte, error)  { return encodeOpt(o.Valid(), o.V) }
+func (o OptInt64) MarshalJSON() ([]byte, error)   { return encodeOpt(o.Valid(), o.V) }
+func (o OptFloat64) MarshalJSON() ([]byte, error) { return encodeOpt(o.Valid(), o.V) }
+func (o OptBool) MarshalJSON() ([]byte, error)    { return encodeOpt(o.Valid(), o.V) }
+func (o OptTime) MarshalJSON() ([]byte, error)    { return encodeOpt(o.Valid(), o.V) }
+
+func (o OptPrice) MarshalJSON() ([]byte, error) {
+	if !o.Valid() {
+		return []byte("null"), nil
+	}
+	return []byte(o.V.String()), nil
+}
+
+func (o *OptString) UnmarshalJSON(data []byte) error {
+	return decodeOpt(data, &o.Set, &o.Null, &o.V)
+}
+
+func (o *OptInt64) UnmarshalJSON(data []byte) error {
+	return decodeOpt(data, &o.Set, &o.Null, &o.V)
+}
+
+func (o *OptFloat64) UnmarshalJSON(data []byte) error {
+	return decodeOpt(data, &o.Set, &o.Null, &o.V)
+}
+
+func (o *OptBool) UnmarshalJSON(data []byte) error {
+	return decodeOpt(data, &o.Set, &o.Null, &o.V)
+}
+
+func (o *OptTime) UnmarshalJSON(data []byte) error {
+	return decodeOpt(data, &o.Set, &o.Null, &o.V)
+}
+
+func (o *OptPrice) UnmarshalJSON(data []byte) error {
+	return decodeOpt(data, &o.Set, &o.Null, &o.V)
+}
+
+func encodeOpt(valid bool, v interface{}) ([]byte, error) {
+	if !valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+// decodeOpt is only called for present fields
+func decodeOpt(data []byte, set *bool, null *bool, v interface{}) error {
+	*set = true
+	*null = string(data) == "null"
+	// reset v: a null leaves the target untouched
+	reflect.ValueOf(v).Elem().Set(reflect.Zero(reflect.TypeOf(v).Elem()))
+	if *null {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// optional is implemented by the Opt* types
+type optional interface {
+	present() bool
+}
+
+// optString and friends convert a swagger field, taking zero as absent
+
+func optString(v string) OptString    { return OptString{V: v, Set: v != ""} }
+func optInt64(v int64) OptInt64       { return OptInt64{V: v, Set: v != 0} }
+func optFloat64(v float64) OptFloat64 { return OptFloat64{V: v, Set: v != 0} }
+func optBool(v bool) OptBool          { return OptBool{V: v, Set: v} }
+func optTime(v time.Time) OptTime     { return OptTime{V: v, Set: !v.IsZero()} }
+func optPrice(v float64) OptPrice     { return OptPrice{V: PriceOf(v), Set: v != 0} }
+
+// marshalPresent encodes a model struct as a JSON object of its present
+// fields. Fields that are not Opt* types, the keys and the enums, are
+// encoded unless zero.
+func marshalPresent(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < rt.NumField(); i++ {
+		field := rv.Field(i).Interface()
+		if o, ok := field.(optional); ok {
+			if !o.present() {
+				continue
+			}
+		} else if rv.Field(i).IsZero() {
+			continue
+		}
+		value, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := json.Marshal(jsonName(rt.Field(i)))
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// mergePresent copies the present fields of *src to *dst. Fields that are
+// not Opt* types are copied when non-zero.
+func mergePresent(dst interface{}, src interface{}) {
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src).Elem()
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		if o, ok := f.Interface().(optional); ok {
+			if !o.present() {
+				continue
+			}
+		} else if f.IsZero() {
+			continue
+		}
+		d.Field(i).Set(f)
+	}
+}
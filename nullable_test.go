@@ -0,0 +1,59 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrder_Presence(t *testing.T) {
+	var o Order
+	raw := `{"orderID":"a","symbol":"XBTUSD","ordType":"Market","price":null,"stopPx":0,"orderQty":100}`
+	if err := json.Unmarshal([]byte(raw), &o); err != nil {
+		t.Fatal(err)
+	}
+	if !o.Price.Set || !o.Price.Null || o.Price.Valid() {
+		t.Errorf("null price %+v", o.Price)
+	}
+	if p, ok := o.StopPx.Get(); !ok || !p.IsZero() {
+		t.Errorf("zero stopPx %+v", o.StopPx)
+	}
+	if o.PegOffsetValue.Set || o.LeavesQty.Or(-1) != -1 {
+		t.Errorf("absent fields %+v %+v", o.PegOffsetValue, o.LeavesQty)
+	}
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"orderID":"a","symbol":"XBTUSD","orderQty":100,"price":null,"stopPx":0,"ordType":"Market"}`
+	if string(data) != want {
+		t.Errorf("marshal\n got %s\nwant %s", data, want)
+	}
+}
+
+func TestOrder_Merge(t *testing.T) {
+	var o, u Order
+	json.Unmarshal([]byte(`{"orderID":"a","price":3600.5,"orderQty":100,"leavesQty":100,"text":"x"}`), &o)
+	json.Unmarshal([]byte(`{"orderID":"a","leavesQty":0,"cumQty":100,"text":null}`), &u)
+	o.Merge(&u)
+
+	if o.OrderID != "a" || o.Price.V != MustPrice("3600.5") || o.OrderQty.V != 100 {
+		t.Errorf("untouched fields %+v", o)
+	}
+	if !o.LeavesQty.Valid() || o.LeavesQty.V != 0 || o.CumQty.V != 100 {
+		t.Errorf("updated fields %+v %+v", o.LeavesQty, o.CumQty)
+	}
+	if !o.Text.Null || o.Text.V != "" {
+		t.Errorf("cleared field %+v", o.Text)
+	}
+}
+
+func TestPosition_Merge(t *testing.T) {
+	var p, u Position
+	json.Unmarshal([]byte(`{"account":1,"symbol":"XBTUSD","currency":"XBt","currentQty":10,"liquidationPrice":3000}`), &p)
+	json.Unmarshal([]byte(`{"account":1,"symbol":"XBTUSD","currency":"XBt","currentQty":0,"liquidationPrice":null}`), &u)
+	p.Merge(&u)
+	if !p.CurrentQty.Valid() || p.CurrentQty.V != 0 || p.LiquidationPrice.Valid() || p.Symbol != "XBTUSD" {
+		t.Errorf("position %+v", p)
+	}
+}
@@ -0,0 +1,118 @@
+package bitmex
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	var m sync.Mutex
+	headers := map[string]http.Header{}
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		headers[r.URL.Path] = r.Header.Clone()
+		m.Unlock()
+		if r.URL.Path != "/realtime" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		ts := time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"info":"Welcome to the BitMEX Realtime API.","timestamp":"`+ts+`"}`))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	b, err := NewWithOptions(Options{
+		Key:              "key",
+		Secret:           "secret",
+		RESTURL:          srv.URL + "/api/v1",
+		TLSConfig:        &tls.Config{RootCAs: pool},
+		UserAgent:        "relay-test",
+		HandshakeTimeout: 200 * time.Millisecond,
+		Headers:          map[string]string{"X-Relay": "r1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "wss://" + strings.TrimPrefix(srv.URL, "https://") + "/realtime"; b.wsURL != want {
+		t.Errorf("ws url %v, want %v", b.wsURL, want)
+	}
+
+	if _, err := b.GetWallet(); err != nil {
+		t.Fatal(err)
+	}
+	b.StartWS()
+	defer b.ws.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for b.ClockOffset() < 50*time.Minute && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if b.ClockOffset() < 50*time.Minute {
+		t.Errorf("no welcome message, offset %v", b.ClockOffset())
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	rest, ws := headers["/api/v1/user/wallet"], headers["/realtime"]
+	if rest.Get("User-Agent") != "relay-test" || rest.Get("X-Relay") != "r1" || rest.Get("api-key") != "key" {
+		t.Errorf("REST headers %v", rest)
+	}
+	if ws.Get("X-Relay") != "r1" {
+		t.Errorf("websocket headers %v", ws)
+	}
+}
+
+func TestNewWithOptions_Defaults(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	if b.cfg.BasePath != "https://testnet.bitmex.com/api/v1" || b.wsURL != "wss://testnet.bitmex.com/realtime" {
+		t.Errorf("urls %v %v", b.cfg.BasePath, b.wsURL)
+	}
+	if b.timeout != 10*time.Second || b.httpClient.Timeout != 0 {
+		t.Errorf("timeout %v, client timeout %v", b.timeout, b.httpClient.Timeout)
+	}
+
+	b, err := NewWithOptions(Options{RESTURL: "http://127.0.0.1:8080/api/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.wsURL != "ws://127.0.0.1:8080/realtime" || b.host != "127.0.0.1:8080" {
+		t.Errorf("ws url %v host %v", b.wsURL, b.host)
+	}
+}
+
+func TestNewWithOptions_BadURL(t *testing.T) {
+	for _, u := range []string{"127.0.0.1:8080/api/v1", "ftp://127.0.0.1/api/v1", "http:///api/v1", "http://[::1/api/v1"} {
+		if b, err := NewWithOptions(Options{RESTURL: u}); err == nil || b != nil {
+			t.Errorf("rest url %q accepted", u)
+		}
+	}
+}
+
+func TestNew_BadHost(t *testing.T) {
+	for _, host := range []string{"", "[::1"} {
+		b := New(host, "key", "secret")
+		if _, err := b.GetWallet(); err == nil {
+			t.Errorf("host %q: request sent", host)
+		}
+	}
+}
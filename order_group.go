@@ -0,0 +1,229 @@
+package bitmex
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// OrderGroup is a set of orders linked by a clOrdLinkID
+type OrderGroup struct {
+	LinkID          string
+	ContingencyType ContingencyType
+	Symbol          string
+
+	m      sync.RWMutex
+	ids    []string                  // OrderIDs in submission order
+	orders map[string]*swagger.Order // key: OrderID
+}
+
+func newOrderGroup(linkID string, contingencyType ContingencyType, symbol string) *OrderGroup {
+	return &OrderGroup{
+		LinkID:          linkID,
+		ContingencyType: contingencyType,
+		Symbol:          symbol,
+		orders:          make(map[string]*swagger.Order),
+	}
+}
+
+// Orders returns a copy of the orders of the group in submission order
+func (g *OrderGroup) Orders() []swagger.Order {
+	g.m.RLock()
+	defer g.m.RUnlock()
+
+	orders := make([]swagger.Order, 0, len(g.ids))
+	for _, id := range g.ids {
+		orders = append(orders, *g.orders[id])
+	}
+	return orders
+}
+
+// Open reports whether any order of the group is still working
+func (g *OrderGroup) Open() bool {
+	g.m.RLock()
+	defer g.m.RUnlock()
+
+	for _, o := range g.orders {
+		if o.OrdStatus == OS_NEW || o.OrdStatus == OS_PARTIALLY_FILLED || o.OrdStatus == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *OrderGroup) update(order swagger.Order) {
+	if order.OrderID == "" {
+		return
+	}
+
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if _, ok := g.orders[order.OrderID]; !ok {
+		g.ids = append(g.ids, order.OrderID)
+	}
+	g.orders[order.OrderID] = &order
+}
+
+// newLinkID returns a random clOrdLinkID
+func newLinkID() string {
+	var buf [12]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// PlaceOrderGroup submits orders as one contingent group in a single bulk
+// request. All orders must be for the same symbol. A clOrdLinkID is generated
+// and contingencyType (ContingencyOCO, ContingencyOTO, ...) is applied to
+// every order. Every order is validated first; when one fails nothing is
+// sent, so that a group never reaches the exchange with a leg missing.
+//
+// The group is tracked from before it is sent until ForgetOrderGroup is
+// called, and kept up to date from the order stream. When the request fails
+// without telling whether it reached BitMEX, the group is returned along with
+// the error and stays tracked; RefreshOrderGroup reconciles it by clOrdLinkID.
+func (b *BitMEX) PlaceOrderGroup(ctx context.Context, contingencyType ContingencyType, orders []OrderRequest) (group *OrderGroup, err error) {
+	if len(orders) < 2 {
+		err = errors.New("order group needs at least two orders")
+		return
+	}
+	symbol := orders[0].Symbol
+	for _, o := range orders {
+		if o.Symbol != symbol {
+			err = fmt.Errorf("order group mixes symbols %v and %v", symbol, o.Symbol)
+			return
+		}
+	}
+
+	linkID := newLinkID()
+	linked := make([]OrderRequest, len(orders))
+	for i, o := range orders {
+		o.ClOrdLinkID = linkID
+		o.ContingencyType = contingencyType
+		if o.ClOrdID == "" {
+			o.ClOrdID = b.NewClOrdID()
+		}
+		if err = b.checkOrderRequest(&o); err != nil {
+			return
+		}
+		linked[i] = o
+	}
+
+	group = newOrderGroup(linkID, contingencyType, symbol)
+	b.orderGroupsMutex.Lock()
+	b.orderGroups[linkID] = group
+	b.orderGroupsMutex.Unlock()
+
+	results := make([]BulkResult, len(linked))
+	err = b.sendOrders(ctx, linked, results)
+	live := false
+	for _, r := range results {
+		group.update(r.Order.Swagger())
+		var unknown *OrderUnknownError
+		if r.Err == nil || errors.As(r.Err, &unknown) {
+			live = true
+		}
+		if r.Err != nil && err == nil {
+			err = r.Err
+		}
+	}
+	if !live {
+		// no order of the group can be working
+		b.ForgetOrderGroup(linkID)
+		group = nil
+	}
+	return
+}
+
+// GetOrderGroup returns a tracked order group
+func (b *BitMEX) GetOrderGroup(linkID string) (group *OrderGroup, ok bool) {
+	b.orderGroupsMutex.RLock()
+	defer b.orderGroupsMutex.RUnlock()
+
+	group, ok = b.orderGroups[linkID]
+	return
+}
+
+// ForgetOrderGroup stops tracking an order group
+func (b *BitMEX) ForgetOrderGroup(linkID string) {
+	b.orderGroupsMutex.Lock()
+	defer b.orderGroupsMutex.Unlock()
+
+	delete(b.orderGroups, linkID)
+}
+
+// RefreshOrderGroup reloads the orders of a group by rest api
+func (b *BitMEX) RefreshOrderGroup(ctx context.Context, linkID string) (group *OrderGroup, err error) {
+	var response *http.Response
+	var orders []swagger.Order
+
+	group, ok := b.GetOrderGroup(linkID)
+	if !ok {
+		err = NotFound
+		return
+	}
+
+	params := map[string]interface{}{}
+	params["symbol"] = group.Symbol
+	params["filter"] = fmt.Sprintf(`{"clOrdLinkID":"%s"}`, linkID)
+
+	orders, response, err = b.client.OrderApi.OrderGetOrders(b.authContext(ctx), params)
+	if err != nil {
+		return
+	}
+	for _, o := range orders {
+		group.update(o)
+	}
+	b.onResponse(response)
+	return
+}
+
+// CancelOrderGroup cancels every open order of a group in one request
+func (b *BitMEX) CancelOrderGroup(ctx context.Context, linkID string) (orders []swagger.Order, err error) {
+	var response *http.Response
+
+	group, ok := b.GetOrderGroup(linkID)
+	if !ok {
+		err = NotFound
+		return
+	}
+
+	params := map[string]interface{}{}
+	params["symbol"] = group.Symbol
+	params["filter"] = fmt.Sprintf(`{"clOrdLinkID":"%s"}`, linkID)
+	params["text"] = "cancel order group with bitmex api"
+
+	orders, response, err = b.client.OrderApi.OrderCancelAll(b.authContext(ctx), params)
+	if err != nil {
+		return
+	}
+	for _, o := range orders {
+		group.update(o)
+	}
+	b.onResponse(response)
+	return
+}
+
+// updateOrderGroups applies order stream updates to the tracked groups
+func (b *BitMEX) updateOrderGroups(orders []*swagger.Order) {
+	b.orderGroupsMutex.RLock()
+	defer b.orderGroupsMutex.RUnlock()
+
+	if len(b.orderGroups) == 0 {
+		return
+	}
+	for _, o := range orders {
+		if o.ClOrdLinkID == "" {
+			continue
+		}
+		if group, ok := b.orderGroups[o.ClOrdLinkID]; ok {
+			group.update(*o)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package bitmex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestBitMEX_PlaceOrderGroup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var form map[string]string
+		json.Unmarshal(body, &form)
+		var orders []map[string]interface{}
+		json.Unmarshal([]byte(form["orders"]), &orders)
+		var resp []map[string]interface{}
+		for i, o := range orders {
+			resp = append(resp, map[string]interface{}{
+				"orderID":         []string{"o1", "o2"}[i],
+				"clOrdLinkID":     o["clOrdLinkID"],
+				"contingencyType": o["contingencyType"],
+				"symbol":          o["symbol"],
+				"ordStatus":       OS_NEW,
+			})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	group, err := b.PlaceOrderGroup(context.Background(), ContingencyOCO, []OrderRequest{
+		{Symbol: "XBTUSD", Side: SIDE_SELL, OrdType: ORD_TYPE_LIMIT, OrderQty: 10, Price: MustPrice("6000")},
+		{Symbol: "XBTUSD", Side: SIDE_SELL, OrdType: ORD_TYPE_STOP, OrderQty: 10, StopPx: MustPrice("4000")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := group.Orders()
+	if len(orders) != 2 {
+		t.Fatalf("orders %v", orders)
+	}
+	for _, o := range orders {
+		if o.ClOrdLinkID != group.LinkID || ContingencyType(o.ContingencyType) != ContingencyOCO {
+			t.Errorf("order not linked: %#v", o)
+		}
+	}
+
+	if g, ok := b.GetOrderGroup(group.LinkID); !ok || g != group {
+		t.Error("group not tracked")
+	}
+
+	b.updateOrderGroups([]*swagger.Order{
+		{OrderID: "o1", ClOrdLinkID: group.LinkID, OrdStatus: OS_FILLED},
+		{OrderID: "o2", ClOrdLinkID: group.LinkID, OrdStatus: OS_CANCELED},
+	})
+	if group.Open() {
+		t.Error("group should be done")
+	}
+
+	_, err = b.PlaceOrderGroup(context.Background(), ContingencyOCO, []OrderRequest{
+		{Symbol: "XBTUSD", OrderQty: 10},
+		{Symbol: "ETHUSD", OrderQty: 10},
+	})
+	if err == nil {
+		t.Error("expected error for mixed symbols")
+	}
+}
+
+func TestBitMEX_PlaceOrderGroupInvalidLeg(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", TickSize: 0.5, LotSize: 1}}, true)
+
+	// the stop is off tick: the entry must not be sent without it
+	group, err := b.PlaceOrderGroup(context.Background(), ContingencyOTO, []OrderRequest{
+		{Symbol: "XBTUSD", Side: SIDE_BUY, OrdType: ORD_TYPE_LIMIT, OrderQty: 10, Price: MustPrice("5000")},
+		{Symbol: "XBTUSD", Side: SIDE_SELL, OrdType: ORD_TYPE_STOP, OrderQty: 10, StopPx: MustPrice("4000.3")},
+	})
+	var invalid *InvalidOrderError
+	if !errors.As(err, &invalid) || group != nil {
+		t.Fatalf("got %v %v, want *InvalidOrderError", group, err)
+	}
+	if calls != 0 {
+		t.Errorf("%v requests sent", calls)
+	}
+	if len(b.orderGroups) != 0 {
+		t.Errorf("groups tracked %v", b.orderGroups)
+	}
+}
+
+func TestBitMEX_PlaceOrderGroupAmbiguous(t *testing.T) {
+	status := http.StatusGatewayTimeout
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the lookup by clOrdID fails as well
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":{"message":"failed","name":"HTTPError"}}`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetRetryPolicy(RetryPolicy{})
+	legs := []OrderRequest{
+		{Symbol: "XBTUSD", Side: SIDE_SELL, OrdType: ORD_TYPE_LIMIT, OrderQty: 10, Price: MustPrice("6000")},
+		{Symbol: "XBTUSD", Side: SIDE_SELL, OrdType: ORD_TYPE_STOP, OrderQty: 10, StopPx: MustPrice("4000")},
+	}
+
+	// the legs may be live: the group stays tracked
+	group, err := b.PlaceOrderGroup(context.Background(), ContingencyOCO, legs)
+	var unknown *OrderUnknownError
+	if !errors.As(err, &unknown) || group == nil {
+		t.Fatalf("got %v %v, want the group and *OrderUnknownError", group, err)
+	}
+	if g, ok := b.GetOrderGroup(group.LinkID); !ok || g != group {
+		t.Error("group not tracked")
+	}
+
+	// a rejected request leaves nothing to track
+	status = http.StatusBadRequest
+	group, err = b.PlaceOrderGroup(context.Background(), ContingencyOCO, legs)
+	if err == nil || group != nil {
+		t.Fatalf("got %v %v, want an error", group, err)
+	}
+	if len(b.orderGroups) != 1 {
+		t.Errorf("groups tracked %v", b.orderGroups)
+	}
+}
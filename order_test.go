@@ -19,3 +19,39 @@ func TestParseOrders(t *testing.T) {
 		t.Logf("%v", *v)
 	}
 }
+
+func TestBitMEX_ProcessOrderMerge(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	b.processOrder(&Response{Action: bitmexActionInsertData,
+		raw: []byte(`[{"orderID":"o1","clOrdID":"c1","symbol":"XBTUSD","side":"Buy","ordType":"StopLimit","ordStatus":"New","orderQty":50,"leavesQty":50,"price":4900,"stopPx":4950}]`)})
+
+	// a triggered stop limit amended to market clears the price, a fill
+	// clears leavesQty without any other field
+	b.processOrder(&Response{Action: bitmexActionUpdateData, raw: []byte(`[{"orderID":"o1","price":null}]`)})
+	var event []*Order
+	b.On(BitmexWSOrder, func(orders []*Order, action string) {
+		event = orders
+	})
+	b.processOrder(&Response{Action: bitmexActionUpdateData, raw: []byte(`[{"orderID":"o1","leavesQty":0,"cumQty":50}]`)})
+	if len(event) != 1 || event[0].Side != SideBuy || event[0].OrdType != OrdTypeStopLimit || !event[0].LeavesQty.Valid() {
+		t.Errorf("event %+v", event)
+	}
+
+	b.orderLocalsMutex.RLock()
+	o := *b.orderLocals["o1"]
+	b.orderLocalsMutex.RUnlock()
+	if !o.Price.Set || !o.Price.Null {
+		t.Errorf("price %+v, want null", o.Price)
+	}
+	if v, ok := o.LeavesQty.Get(); !ok || v != 0 {
+		t.Errorf("leavesQty %+v, want 0", o.LeavesQty)
+	}
+	if o.CumQty.V != 50 || o.StopPx.V != MustPrice("4950") || o.OrdStatus != OrdStatusNew {
+		t.Errorf("order %+v", o)
+	}
+
+	order, ok := b.OrderByClOrdID("c1")
+	if !ok || order.Price != 0 || order.LeavesQty != 0 || order.OrderQty != 50 {
+		t.Errorf("by clOrdID %+v", order)
+	}
+}
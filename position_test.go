@@ -0,0 +1,146 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestBitMEX_CheckRiskLimit(t *testing.T) {
+	b := New(HostTestnet, "key", "secret")
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", RiskLimit: 20000000000, RiskStep: 10000000000}}, true)
+
+	tests := []struct {
+		mode  ValidationMode
+		limit int64
+		want  int64
+		ok    bool
+	}{
+		{ValidateReject, 20000000000, 20000000000, true},
+		{ValidateReject, 50000000000, 50000000000, true},
+		{ValidateReject, 25000000000, 0, false},
+		{ValidateReject, 10000000000, 0, false},
+		{ValidateRound, 25000000000, 30000000000, true},
+		{ValidateOff, 25000000000, 25000000000, true},
+		{ValidateOff, 0, 0, false},
+	}
+	for _, test := range tests {
+		b.SetOrderValidation(test.mode)
+		limit := test.limit
+		err := b.checkRiskLimit("XBTUSD", &limit)
+		if (err == nil) != test.ok || (err == nil && limit != test.want) {
+			t.Errorf("mode %v limit %v: %v %v", test.mode, test.limit, limit, err)
+		}
+	}
+}
+
+func TestBitMEX_PositionEndpoints(t *testing.T) {
+	var forms []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var form map[string]interface{}
+		json.Unmarshal(body, &form)
+		form["path"] = r.URL.Path
+		forms = append(forms, form)
+		w.Header().Set("X-Ratelimit-Remaining", "42")
+		if r.URL.Path == "/order/closePosition" {
+			w.Write([]byte(`{"orderID":"c","symbol":"XBTUSD","execInst":"Close"}`))
+			return
+		}
+		w.Write([]byte(`{"symbol":"XBTUSD"}`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", SettlCurrency: CURRENCY_XBT, TickSize: 0.5,
+		RiskLimit: 20000000000, RiskStep: 10000000000}}, true)
+
+	if _, err := b.PositionIsolateMargin(true, "XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PositionTransferIsolatedMargin(XBt(-1234567), "XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PositionTransferIsolatedMargin(USD(10), "XBTUSD"); err == nil {
+		t.Error("transfer in USD accepted")
+	}
+	if _, err := b.PositionUpdateRiskLimit(30000000000, "XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PositionUpdateRiskLimit(30000000001, "XBTUSD"); err == nil {
+		t.Error("off-step risk limit accepted")
+	}
+	if _, err := b.ClosePosition(MustPrice("5000.5"), "XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ClosePosition(MustPrice("5000.3"), "XBTUSD"); err == nil {
+		t.Error("off-tick close price accepted")
+	}
+
+	want := []string{
+		"/position/isolate enabled=true",
+		"/position/transferMargin amount=-1234567",
+		"/position/riskLimit riskLimit=30000000000",
+		"/order/closePosition price=5000.5",
+	}
+	if len(forms) != len(want) {
+		t.Fatalf("requests %v", forms)
+	}
+	for i, form := range forms {
+		var got string
+		for k, v := range form {
+			if k != "path" && k != "symbol" {
+				got = fmt.Sprintf("%v %v=%v", form["path"], k, v)
+			}
+		}
+		if got != want[i] {
+			t.Errorf("request %v: %v, want %v", i, got, want[i])
+		}
+	}
+	if b.rateLimit.Remaining != 42 {
+		t.Errorf("rate limit %+v", b.rateLimit)
+	}
+}
+
+func TestBitMEX_ClosePositionRound(t *testing.T) {
+	var prices []interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var form map[string]interface{}
+		json.Unmarshal(body, &form)
+		prices = append(prices, form["price"])
+		w.Write([]byte(`{"orderID":"c","symbol":"XBTUSD","execInst":"Close"}`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", TickSize: 0.5}}, true)
+	b.SetOrderValidation(ValidateRound)
+
+	// a long position is closed by a sell, rounded up
+	b.updatePositions(&Response{Action: bitmexActionInitialData, raw: []byte(`[{"account":1,"symbol":"XBTUSD","currentQty":100}]`)})
+	if _, err := b.ClosePosition(MustPrice("5000.3"), "XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+	// a short position is closed by a buy, rounded down
+	b.updatePositions(&Response{Action: bitmexActionUpdateData, raw: []byte(`[{"account":1,"symbol":"XBTUSD","currentQty":-100}]`)})
+	if _, err := b.ClosePosition(MustPrice("5000.3"), "XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+	// a flat position gives no side to round toward
+	b.updatePositions(&Response{Action: bitmexActionUpdateData, raw: []byte(`[{"account":1,"symbol":"XBTUSD","currentQty":0}]`)})
+	if _, err := b.ClosePosition(MustPrice("5000.3"), "XBTUSD"); err == nil {
+		t.Error("off-tick close price accepted without a position")
+	}
+
+	if fmt.Sprint(prices) != "[5000.5 5000]" {
+		t.Errorf("prices %v, want [5000.5 5000]", prices)
+	}
+}
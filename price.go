@@ -0,0 +1,157 @@
+package bitmex
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PriceDecimals is the number of decimals kept by Price. It covers the
+// smallest tick size listed on BitMEX.
+const PriceDecimals = 8
+
+const priceScale = 100000000 // 10^PriceDecimals
+
+// Price is an exact decimal price. The zero value is no price, which is
+// encoded as JSON null like BitMEX does for unset prices.
+type Price struct {
+	v int64 // price * 10^PriceDecimals
+}
+
+// ParsePrice parses a decimal price such as "6543.5" without going through
+// a float
+func ParsePrice(s string) (Price, error) {
+	v, err := parseDecimal(strings.TrimSpace(s), PriceDecimals)
+	if err != nil {
+		return Price{}, fmt.Errorf("price %q: %v", s, err)
+	}
+	return Price{v}, nil
+}
+
+// MustPrice is like ParsePrice but panics on error. It is meant for
+// constants.
+func MustPrice(s string) Price {
+	p, err := ParsePrice(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// PriceOf converts a float price, such as a price field of the swagger
+// models. The float is read at its shortest decimal representation, so a
+// price decoded from JSON is converted exactly.
+func PriceOf(f float64) Price {
+	p, err := ParsePrice(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		// more decimals than kept, e.g. arithmetic noise
+		p, _ = ParsePrice(strconv.FormatFloat(f, 'f', PriceDecimals, 64))
+	}
+	return p
+}
+
+// Float64 returns the price as a float
+func (p Price) Float64() float64 {
+	f, _ := strconv.ParseFloat(p.String(), 64)
+	return f
+}
+
+// IsZero reports whether p is unset
+func (p Price) IsZero() bool {
+	return p.v == 0
+}
+
+// String returns the shortest decimal form, e.g. "6543.5"
+func (p Price) String() string {
+	sign, v := "", p.v
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	s := fmt.Sprintf("%v%d.%0*d", sign, v/priceScale, PriceDecimals, v%priceScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Add returns p+q
+func (p Price) Add(q Price) Price {
+	return Price{p.v + q.v}
+}
+
+// Sub returns p-q
+func (p Price) Sub(q Price) Price {
+	return Price{p.v - q.v}
+}
+
+// Cmp returns -1, 0 or +1 as p is below, equal to or above q
+func (p Price) Cmp(q Price) int {
+	switch {
+	case p.v < q.v:
+		return -1
+	case p.v > q.v:
+		return 1
+	}
+	return 0
+}
+
+// OnTick reports whether p is a multiple of tick
+func (p Price) OnTick(tick Price) bool {
+	return tick.v <= 0 || p.v%tick.v == 0
+}
+
+// Round rounds p to a multiple of tick, up or down
+func (p Price) Round(tick Price, up bool) Price {
+	if tick.v <= 0 {
+		return p
+	}
+	r := p.v % tick.v
+	if r == 0 {
+		return p
+	}
+	if r < 0 {
+		r += tick.v
+	}
+	v := p.v - r
+	if up {
+		v += tick.v
+	}
+	return Price{v}
+}
+
+// AddTicks returns p moved by n ticks
+func (p Price) AddTicks(tick Price, n int64) Price {
+	return Price{p.v + n*tick.v}
+}
+
+// MarshalJSON encodes the price as a JSON number, or null when zero
+func (p Price) MarshalJSON() ([]byte, error) {
+	if p.v == 0 {
+		return []byte("null"), nil
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number, a quoted number or null
+func (p *Price) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if string(data) == "null" || len(data) == 0 {
+		*p = Price{}
+		return nil
+	}
+	s := string(data)
+	if strings.ContainsAny(s, "eE") {
+		// exponent notation, not produced by BitMEX
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*p = PriceOf(f)
+		return nil
+	}
+	v, err := ParsePrice(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
@@ -0,0 +1,75 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestPrice(t *testing.T) {
+	p := MustPrice("0.1").Add(MustPrice("0.2"))
+	if p != MustPrice("0.3") || p.String() != "0.3" {
+		t.Errorf("0.1+0.2 = %v", p)
+	}
+	if s := MustPrice("-12.50").String(); s != "-12.5" {
+		t.Errorf("String %v", s)
+	}
+	if p := PriceOf(6543.5); p.String() != "6543.5" || p.Float64() != 6543.5 {
+		t.Errorf("PriceOf %v", p)
+	}
+	if p := PriceOf(0.1 + 0.2); p != MustPrice("0.3") {
+		t.Errorf("PriceOf(0.1+0.2) = %v", p)
+	}
+
+	tick := MustPrice("0.5")
+	if p := MustPrice("5000.3"); p.OnTick(tick) || p.Round(tick, false) != MustPrice("5000") || p.Round(tick, true) != MustPrice("5000.5") {
+		t.Errorf("rounding %v", p)
+	}
+	if p := MustPrice("-0.3").Round(tick, false); p != MustPrice("-0.5") {
+		t.Errorf("negative rounding %v", p)
+	}
+	if p := MustPrice("5000").AddTicks(tick, -3); p != MustPrice("4998.5") {
+		t.Errorf("AddTicks %v", p)
+	}
+	if _, err := ParsePrice("0.000000001"); err == nil {
+		t.Error("too many decimals parsed")
+	}
+}
+
+func TestPrice_JSON(t *testing.T) {
+	var o struct {
+		Price  Price `json:"price"`
+		StopPx Price `json:"stopPx"`
+		AvgPx  Price `json:"avgPx"`
+	}
+	raw := `{"price":0.00001234,"stopPx":null,"avgPx":"6543.5"}`
+	if err := json.Unmarshal([]byte(raw), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Price != MustPrice("0.00001234") || !o.StopPx.IsZero() || o.AvgPx != MustPrice("6543.5") {
+		t.Errorf("prices %#v", o)
+	}
+	data, _ := json.Marshal(struct {
+		Price  Price `json:"price"`
+		StopPx Price `json:"stopPx"`
+	}{MustPrice("0.00001234"), Price{}})
+	if string(data) != `{"price":0.00001234,"stopPx":null}` {
+		t.Errorf("marshal %s", data)
+	}
+}
+
+func TestOrderFrom(t *testing.T) {
+	var s swagger.Order
+	raw := `{"orderID":"a","orderQty":16777217,"cumQty":16777217,"price":3600.5}`
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		t.Fatal(err)
+	}
+	o := OrderFrom(&s)
+	if o.OrderQty.V != 16777217 || o.CumQty.V != 16777217 || o.Price.V != MustPrice("3600.5") || o.StopPx.Set {
+		t.Errorf("order %#v", o)
+	}
+	if back := o.Swagger(); back.OrderQty != s.OrderQty || back.Price != s.Price || back.OrderID != s.OrderID {
+		t.Errorf("swagger %#v", back)
+	}
+}
@@ -0,0 +1,296 @@
+package bitmex
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/proxy"
+)
+
+// ProxyConfig routes the REST and websocket connections through proxies
+type ProxyConfig struct {
+	// URLs are the proxies, tried in turn until one connects:
+	// http://[user:password@]host:port tunnels with HTTP CONNECT,
+	// socks5://[user:password@]host:port with SOCKS5 to the address of the
+	// host resolved locally, socks5h:// with SOCKS5 to the host name,
+	// resolved by the proxy. The proxy that connected last is tried first.
+	URLs []string
+
+	// NoProxy is a comma separated list of the hosts connected directly:
+	// host names, which also match their subdomains, IP addresses and CIDR
+	// ranges. "*" matches every host.
+	NoProxy string
+}
+
+// proxyDialer dials through the proxies of a ProxyConfig
+type proxyDialer struct {
+	proxies []*url.URL
+	noProxy []string
+
+	m    sync.Mutex
+	good int // index of the proxy that connected last
+}
+
+func newProxyDialer(cfg ProxyConfig) (*proxyDialer, error) {
+	d := &proxyDialer{}
+	for _, s := range cfg.URLs {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		switch u.Scheme {
+		case "http", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("proxy %v: unsupported scheme %q", u.Host, u.Scheme)
+		}
+		d.proxies = append(d.proxies, u)
+	}
+	for _, s := range strings.Split(cfg.NoProxy, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			d.noProxy = append(d.noProxy, strings.ToLower(s))
+		}
+	}
+	return d, nil
+}
+
+// bypass reports whether addr is connected directly
+func (d *proxyDialer) bypass(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, s := range d.noProxy {
+		if s == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(s); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if ip != nil {
+			if ip.Equal(net.ParseIP(s)) {
+				return true
+			}
+			continue
+		}
+		s = strings.TrimPrefix(s, ".")
+		if host == s || strings.HasSuffix(host, "."+s) {
+			return true
+		}
+	}
+	return false
+}
+
+// DialContext connects to addr through the first proxy that works, starting
+// with the one that connected last
+func (d *proxyDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var direct net.Dialer
+	if d == nil || len(d.proxies) == 0 || d.bypass(addr) {
+		return direct.DialContext(ctx, network, addr)
+	}
+
+	d.m.Lock()
+	start := d.good
+	d.m.Unlock()
+	var err error
+	for i := range d.proxies {
+		k := (start + i) % len(d.proxies)
+		var conn net.Conn
+		conn, err = dialProxy(ctx, d.proxies[k], network, addr)
+		if err == nil {
+			d.m.Lock()
+			d.good = k
+			d.m.Unlock()
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+func dialProxy(ctx context.Context, u *url.URL, network, addr string) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if u.Scheme == "http" {
+		conn, err = dialConnect(ctx, u, addr)
+	} else {
+		conn, err = dialSOCKS5(ctx, u, network, addr)
+	}
+	if err != nil {
+		// u.String() would show the password
+		return nil, fmt.Errorf("proxy %v://%v: %v", u.Scheme, u.Host, err)
+	}
+	return conn, nil
+}
+
+func dialSOCKS5(ctx context.Context, u *url.URL, network, addr string) (net.Conn, error) {
+	var auth *proxy.Auth
+	if u.User != nil {
+		auth = &proxy.Auth{User: u.User.Username()}
+		auth.Password, _ = u.User.Password()
+	}
+	if u.Scheme == "socks5" {
+		var err error
+		if addr, err = resolve(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	// without a forward dialer the connection to the proxy honours ctx
+	dialer, err := proxy.SOCKS5("tcp", u.Host, auth, nil)
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := dialer.(interface {
+		DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	}); ok {
+		return d.DialContext(ctx, network, addr)
+	}
+	return dialer.Dial(network, addr)
+}
+
+// resolve replaces the host name of addr by one of its addresses, IPv4
+// first
+func resolve(ctx context.Context, addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return addr, err
+	}
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return "", err
+	}
+	ip := ips[0]
+	for _, v := range ips {
+		if v.To4() != nil {
+			ip = v
+			break
+		}
+	}
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// dialConnect opens a tunnel to addr through the HTTP proxy u with CONNECT,
+// with basic authentication when u has a user. ctx bounds the connection
+// to the proxy and the CONNECT exchange.
+func dialConnect(ctx context.Context, u *url.URL, addr string) (net.Conn, error) {
+	var direct net.Dialer
+	conn, err := direct.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	r := bufio.NewReader(conn)
+	done := make(chan error, 1)
+	go func() {
+		if err := req.Write(conn); err != nil {
+			done <- err
+			return
+		}
+		resp, err := http.ReadResponse(r, req)
+		if err != nil {
+			done <- err
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("CONNECT %v: %v", addr, resp.Status)
+		}
+		done <- err
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		conn.Close()
+		<-done
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if r.Buffered() > 0 {
+		// the tunnel sent data along with the answer
+		return &bufferedConn{Conn: conn, r: r}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn reads the data left in r before the connection
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// SetProxyConfig routes the REST and websocket connections through the
+// proxies of cfg, from the next connection on. An empty cfg connects
+// directly, or through the proxy of the environment for REST requests.
+func (b *BitMEX) SetProxyConfig(cfg ProxyConfig) error {
+	d, err := newProxyDialer(cfg)
+	if err != nil {
+		return err
+	}
+	b.proxyMutex.Lock()
+	b.proxy = d
+	b.proxyMutex.Unlock()
+	b.baseTransport.CloseIdleConnections()
+	return nil
+}
+
+// SetHttpProxy proxyURL: http://127.0.0.1:1080
+func (b *BitMEX) SetHttpProxy(proxyURL string) error {
+	return b.SetProxyConfig(ProxyConfig{URLs: []string{proxyURL}})
+}
+
+// SetProxy proxyURL: 127.0.0.1:1080
+func (b *BitMEX) SetProxy(socks5Proxy string) error {
+	return b.SetProxyConfig(ProxyConfig{URLs: []string{"socks5://" + socks5Proxy}})
+}
+
+// dialContext connects the REST and websocket connections
+func (b *BitMEX) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	b.proxyMutex.RLock()
+	d := b.proxy
+	b.proxyMutex.RUnlock()
+	return d.DialContext(ctx, network, addr)
+}
+
+// environmentProxy returns the proxy of the environment for REST requests
+// when no ProxyConfig is set
+func (b *BitMEX) environmentProxy(req *http.Request) (*url.URL, error) {
+	b.proxyMutex.RLock()
+	d := b.proxy
+	b.proxyMutex.RUnlock()
+	if d != nil && len(d.proxies) > 0 {
+		return nil, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
@@ -0,0 +1,332 @@
+package bitmex
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeProxy is an HTTP CONNECT or SOCKS5 proxy requiring user:pass
+type fakeProxy struct {
+	l     net.Listener
+	conns int32
+	host  atomic.Value // asked by the last SOCKS5 connect
+}
+
+func newFakeProxy(t *testing.T, socks bool) *fakeProxy {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &fakeProxy{l: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&p.conns, 1)
+			if socks {
+				go p.serveSOCKS5(conn)
+			} else {
+				go p.serveConnect(conn)
+			}
+		}
+	}()
+	return p
+}
+
+func (p *fakeProxy) serveConnect(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	req, err := http.ReadRequest(r)
+	if err != nil {
+		return
+	}
+	if req.Method != http.MethodConnect || req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" {
+		io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+		return
+	}
+	target, err := net.Dial("tcp", req.Host)
+	if err != nil {
+		io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+		return
+	}
+	io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+	pipe(conn, target)
+}
+
+func (p *fakeProxy) serveSOCKS5(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	// greeting: version, methods; username/password is required
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return
+	}
+	methods := make([]byte, head[1])
+	io.ReadFull(r, methods)
+	conn.Write([]byte{5, 2})
+	// RFC 1929 subnegotiation
+	io.ReadFull(r, head)
+	user := make([]byte, head[1])
+	io.ReadFull(r, user)
+	n, _ := r.ReadByte()
+	pass := make([]byte, n)
+	io.ReadFull(r, pass)
+	if string(user) != "user" || string(pass) != "pass" {
+		conn.Write([]byte{1, 1})
+		return
+	}
+	conn.Write([]byte{1, 0})
+	// connect request: version, command, reserved, address type
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(r, req); err != nil {
+		return
+	}
+	var host string
+	switch req[3] {
+	case 1:
+		ip := make([]byte, 4)
+		io.ReadFull(r, ip)
+		host = net.IP(ip).String()
+	case 3:
+		n, _ := r.ReadByte()
+		name := make([]byte, n)
+		io.ReadFull(r, name)
+		host = string(name)
+	case 4:
+		ip := make([]byte, 16)
+		io.ReadFull(r, ip)
+		host = net.IP(ip).String()
+	default:
+		return
+	}
+	p.host.Store(host)
+	port := make([]byte, 2)
+	io.ReadFull(r, port)
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	pipe(conn, target)
+}
+
+func pipe(a net.Conn, b net.Conn) {
+	defer b.Close()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		io.Copy(b, a)
+		b.Close()
+	}()
+	io.Copy(a, b)
+	a.Close()
+	wg.Wait()
+}
+
+func TestProxyConfig(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/realtime" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		ts := time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"info":"Welcome","timestamp":"`+ts+`"}`))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	connect, socks := newFakeProxy(t, false), newFakeProxy(t, true)
+	defer connect.l.Close()
+	defer socks.l.Close()
+	dead, _ := net.Listen("tcp", "127.0.0.1:0")
+	dead.Close()
+
+	b, err := NewWithOptions(Options{
+		Key:              "key",
+		Secret:           "secret",
+		RESTURL:          srv.URL + "/api/v1",
+		HandshakeTimeout: 200 * time.Millisecond,
+		Proxy: ProxyConfig{URLs: []string{
+			"socks5://user:pass@" + dead.Addr().String(),
+			"socks5://user:pass@" + socks.l.Addr().String(),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the dead proxy fails over to the SOCKS5 one, for REST and websocket
+	if _, err := b.GetWallet(); err != nil {
+		t.Fatal(err)
+	}
+	b.StartWS()
+	defer b.ws.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for b.ClockOffset() < 50*time.Minute && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if b.ClockOffset() < 50*time.Minute {
+		t.Error("no websocket welcome through the SOCKS5 proxy")
+	}
+	if n := atomic.LoadInt32(&socks.conns); n != 2 {
+		t.Errorf("%v SOCKS5 connections, want 2", n)
+	}
+
+	if err := b.SetProxyConfig(ProxyConfig{URLs: []string{"http://user:pass@" + connect.l.Addr().String()}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetWallet(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&connect.conns); n != 1 {
+		t.Errorf("%v CONNECT connections, want 1", n)
+	}
+
+	b.SetRetryPolicy(RetryPolicy{})
+	if err := b.SetProxyConfig(ProxyConfig{URLs: []string{"http://user:wrong@" + connect.l.Addr().String()}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetWallet(); err == nil {
+		t.Error("connected with wrong proxy credentials")
+	}
+
+	// the test server is on 127.0.0.1
+	if err := b.SetProxyConfig(ProxyConfig{URLs: []string{"http://" + dead.Addr().String()}, NoProxy: "example.com, 127.0.0.0/8"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetWallet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.SetProxyConfig(ProxyConfig{URLs: []string{"ftp://" + dead.Addr().String()}}); err == nil {
+		t.Error("accepted an ftp proxy")
+	}
+}
+
+func TestProxyDialer_Bypass(t *testing.T) {
+	d, err := newProxyDialer(ProxyConfig{NoProxy: ".internal, relay.local,10.0.0.0/8, ::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, want := range map[string]bool{
+		"api.internal:443":   true,
+		"internal:443":       true,
+		"relay.local:443":    true,
+		"a.relay.local:443":  true,
+		"xrelay.local:443":   false,
+		"10.1.2.3:443":       true,
+		"11.1.2.3:443":       false,
+		"[::1]:443":          true,
+		"www.bitmex.com:443": false,
+	} {
+		if got := d.bypass(addr); got != want {
+			t.Errorf("bypass(%v) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestProxyDialer_SOCKS5Resolve(t *testing.T) {
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	socks := newFakeProxy(t, true)
+	defer socks.l.Close()
+	_, port, _ := net.SplitHostPort(target.Addr().String())
+
+	// socks5 resolves the name, socks5h lets the proxy do it
+	for scheme, local := range map[string]bool{"socks5": true, "socks5h": false} {
+		d, err := newProxyDialer(ProxyConfig{URLs: []string{scheme + "://user:pass@" + socks.l.Addr().String()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := d.DialContext(context.Background(), "tcp", "localhost:"+port)
+		if err != nil {
+			t.Fatalf("%v: %v", scheme, err)
+		}
+		conn.Close()
+		host, _ := socks.host.Load().(string)
+		if resolved := net.ParseIP(host) != nil; resolved != local {
+			t.Errorf("%v: proxy asked for %q", scheme, host)
+		}
+	}
+}
+
+func TestProxyDialer_ConnectBuffered(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		http.ReadRequest(bufio.NewReader(conn))
+		// the first bytes of the tunnel come with the answer
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\nhello")
+	}()
+
+	d, err := newProxyDialer(ProxyConfig{URLs: []string{"http://" + l.Addr().String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := d.DialContext(context.Background(), "tcp", "example.com:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	data, _ := ioutil.ReadAll(conn)
+	if string(data) != "hello" {
+		t.Errorf("read %q through the tunnel", data)
+	}
+}
+
+func TestNewWithOptions_BadProxy(t *testing.T) {
+	b, err := NewWithOptions(Options{Host: HostTestnet, Proxy: ProxyConfig{URLs: []string{"ftp://127.0.0.1:21"}}})
+	if err == nil || b != nil {
+		t.Errorf("proxy accepted: %v", err)
+	}
+	if _, err = NewWithOptions(Options{Host: HostTestnet, Proxy: ProxyConfig{URLs: []string{"http://[::1"}}}); err == nil {
+		t.Error("malformed proxy accepted")
+	}
+}
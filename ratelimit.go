@@ -0,0 +1,275 @@
+package bitmex
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitMode tells the rate limiter what to do with a request when the
+// budget is exhausted
+type RateLimitMode int
+
+const (
+	// RateLimitBlock waits until the budget allows the request
+	RateLimitBlock RateLimitMode = iota
+	// RateLimitFailFast returns a *RateLimitError without sending it
+	RateLimitFailFast
+	// RateLimitOff sends every request
+	RateLimitOff
+)
+
+var (
+	// Budgets in requests per minute until the X-Ratelimit headers of the
+	// first response tell the real ones
+	DefaultPrivateRateLimit = 120
+	DefaultPublicRateLimit  = 30
+
+	// Cost of one entry of a bulk request, in requests. A bulk request
+	// costs at least one request.
+	BulkOrderCost = 0.1
+	BulkAmendCost = 0.5
+)
+
+// RateLimitError is returned in RateLimitFailFast mode for a request the
+// budget does not allow
+type RateLimitError struct {
+	Public bool          // the public (unauthenticated) budget is exhausted
+	Wait   time.Duration // until the request would be allowed
+}
+
+func (e *RateLimitError) Error() string {
+	budget := "private"
+	if e.Public {
+		budget = "public"
+	}
+	return fmt.Sprintf("rate limit: %v budget exhausted, retry in %v", budget, e.Wait)
+}
+
+// Is makes a *RateLimitError match ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// rateBucket is a token bucket refilled at limit requests per minute
+type rateBucket struct {
+	limit  float64
+	tokens float64
+	last   time.Time
+	until  time.Time // nothing is sent before, after a 429
+
+	queue []*rateRequest // by priority, then arrival
+	timer *time.Timer
+}
+
+func newRateBucket(limit int) rateBucket {
+	return rateBucket{limit: float64(limit), tokens: float64(limit)}
+}
+
+func (k *rateBucket) refill(now time.Time) {
+	if !k.last.IsZero() {
+		k.tokens = math.Min(k.limit, k.tokens+now.Sub(k.last).Minutes()*k.limit)
+	}
+	k.last = now
+}
+
+// wait returns how long a request of cost has to wait
+func (k *rateBucket) wait(cost float64, now time.Time) time.Duration {
+	k.refill(now)
+	if now.Before(k.until) {
+		return k.until.Sub(now)
+	}
+	cost = math.Min(cost, k.limit)
+	if k.tokens >= cost || k.limit <= 0 {
+		return 0
+	}
+	return time.Duration((cost - k.tokens) / k.limit * float64(time.Minute))
+}
+
+// observe corrects the bucket from the headers of a response
+func (k *rateBucket) observe(response *http.Response, now time.Time) {
+	k.refill(now)
+	if v, err := strconv.ParseFloat(response.Header.Get("X-Ratelimit-Limit"), 64); err == nil && v > 0 {
+		k.limit = v
+	}
+	if v, err := strconv.ParseFloat(response.Header.Get("X-Ratelimit-Remaining"), 64); err == nil {
+		k.tokens = v
+	}
+	if response.StatusCode == http.StatusTooManyRequests {
+		k.tokens = 0
+		if v, err := strconv.ParseInt(response.Header.Get("Retry-After"), 10, 64); err == nil {
+			k.until = now.Add(time.Duration(v) * time.Second)
+		} else if v, err := strconv.ParseInt(response.Header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+			k.until = time.Unix(v, 0)
+		}
+	}
+}
+
+// rateLimiter holds the public and private budgets shared by all REST calls
+// and schedules the requests waiting for them
+type rateLimiter struct {
+	m       sync.Mutex
+	mode    RateLimitMode
+	public  rateBucket
+	private rateBucket
+	seq     uint64
+	stats   [PriorityCancel + 1]SchedulerStats
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		public:  newRateBucket(DefaultPublicRateLimit),
+		private: newRateBucket(DefaultPrivateRateLimit),
+	}
+}
+
+func (l *rateLimiter) bucket(public bool) *rateBucket {
+	if public {
+		return &l.public
+	}
+	return &l.private
+}
+
+// acquire takes the cost of r from its budget. In RateLimitBlock mode r
+// waits in the queue behind requests of a higher priority. It reports
+// whether the request had to wait.
+func (l *rateLimiter) acquire(ctx context.Context, r *rateRequest) (waited bool, err error) {
+	l.m.Lock()
+	k := l.bucket(r.public)
+	now := time.Now()
+	wait := l.need(k, r, now)
+	if wait <= 0 && len(k.queue) == 0 {
+		l.grant(k, r, 0)
+		l.m.Unlock()
+		return
+	}
+	if l.mode == RateLimitFailFast {
+		l.m.Unlock()
+		err = &RateLimitError{Public: r.public, Wait: wait}
+		return
+	}
+	l.enqueue(k, r, now)
+	l.schedule(k)
+	l.m.Unlock()
+
+	waited = true
+	select {
+	case <-r.ready:
+	case <-ctx.Done():
+		l.m.Lock()
+		if l.dequeue(k, r) {
+			r.err = ctx.Err()
+			l.schedule(k)
+		}
+		l.m.Unlock()
+	}
+	err = r.err
+	return
+}
+
+func (l *rateLimiter) observe(public bool, response *http.Response) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	k := l.bucket(public)
+	k.observe(response, time.Now())
+	l.schedule(k)
+}
+
+type requestCostKey struct{}
+
+// withRequestCost sets the rate limit cost of the requests made with ctx
+func withRequestCost(ctx context.Context, cost float64) context.Context {
+	return context.WithValue(ctx, requestCostKey{}, cost)
+}
+
+// bulkCost returns the cost of a bulk request of n entries
+func bulkCost(n int, amend bool) float64 {
+	per := BulkOrderCost
+	if amend {
+		per = BulkAmendCost
+	}
+	return math.Max(1, math.Ceil(float64(n)*per))
+}
+
+// rateLimitTransport applies the rate limiter to the requests of a client
+type rateLimitTransport struct {
+	b    *BitMEX
+	base http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := newRateRequest(req)
+	waited, err := t.b.limiter.acquire(req.Context(), r)
+	if err != nil {
+		return nil, err
+	}
+	if waited && !r.public {
+		// the signature may have expired while waiting
+		if req, err = t.b.resign(req); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.b.limiter.observe(r.public, response)
+	}
+	return response, err
+}
+
+// rateLimited wraps the transport of a client with the rate limiter
+func (b *BitMEX) rateLimited(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{b: b, base: base}
+}
+
+// resign returns a copy of a signed request with a new api-expires and
+// api-signature
+func (b *BitMEX) resign(req *http.Request) (*http.Request, error) {
+	var body []byte
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		body, err = ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// signed like SetAuthHeader: the path from /api on
+	var path string
+	if i := strings.Index(req.URL.Path, "/api"); i >= 0 {
+		path = req.URL.Path[i:]
+	}
+	expires := b.cfg.Expires()
+	signature, err := b.signer().Sign(req.Method, path, req.URL.RawQuery, expires, string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("api-expires", expires)
+	req.Header.Set("api-signature", signature)
+	return req, nil
+}
+
+// SetRateLimitMode sets what REST calls do when the rate limit budget is
+// exhausted. The default is RateLimitBlock.
+func (b *BitMEX) SetRateLimitMode(mode RateLimitMode) {
+	l := b.limiter
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.mode = mode
+	// RateLimitOff releases the waiting requests
+	l.schedule(&l.public)
+	l.schedule(&l.private)
+}
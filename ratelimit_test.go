@@ -0,0 +1,131 @@
+package bitmex
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestRateBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	k := newRateBucket(60)
+	if wait := k.wait(1, now); wait != 0 {
+		t.Fatalf("full bucket waits %v", wait)
+	}
+	k.tokens = 0
+	if wait := k.wait(1, now); wait != time.Second {
+		t.Errorf("empty bucket waits %v, want 1s", wait)
+	}
+	if wait := k.wait(1, now.Add(time.Second)); wait != 0 {
+		t.Errorf("refilled bucket waits %v", wait)
+	}
+	if k.wait(1, now.Add(time.Hour)); k.tokens != 60 {
+		t.Errorf("tokens %v above the limit", k.tokens)
+	}
+
+	header := http.Header{}
+	header.Set("X-Ratelimit-Limit", "120")
+	header.Set("X-Ratelimit-Remaining", "3")
+	k.observe(&http.Response{StatusCode: http.StatusOK, Header: header}, now.Add(time.Hour))
+	if k.limit != 120 || k.tokens != 3 {
+		t.Errorf("limit %v tokens %v after headers", k.limit, k.tokens)
+	}
+
+	header = http.Header{}
+	header.Set("Retry-After", "5")
+	later := now.Add(2 * time.Hour)
+	k.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: header}, later)
+	if wait := k.wait(1, later); wait != 5*time.Second {
+		t.Errorf("wait %v after 429, want 5s", wait)
+	}
+}
+
+func TestBulkCost(t *testing.T) {
+	for _, v := range []struct {
+		n     int
+		amend bool
+		cost  float64
+	}{
+		{1, false, 1},
+		{10, false, 1},
+		{11, false, 2},
+		{1, true, 1},
+		{3, true, 2},
+	} {
+		if cost := bulkCost(v.n, v.amend); cost != v.cost {
+			t.Errorf("bulkCost(%v, %v) = %v, want %v", v.n, v.amend, cost, v.cost)
+		}
+	}
+}
+
+func TestBitMEX_RateLimit(t *testing.T) {
+	var m sync.Mutex
+	var calls int
+	var signed []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		expires := r.Header.Get("api-expires")
+		signed = append(signed, r.Header.Get("api-signature") == swagger.Signature("secret", r.Method, "", r.URL.RawQuery, expires, string(body)))
+		calls++
+		// one request per 100ms, none left
+		w.Header().Set("X-Ratelimit-Limit", "600")
+		w.Header().Set("X-Ratelimit-Remaining", "0")
+		w.Write([]byte(`[{"orderID":"a","ordStatus":"Canceled"}]`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	if _, err := b.CancelOrder("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	b.SetRateLimitMode(RateLimitFailFast)
+	_, err := b.CancelOrder("a")
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.Public || rateErr.Wait <= 0 {
+		t.Fatalf("got %v, want a private *RateLimitError", err)
+	}
+
+	b.SetRateLimitMode(RateLimitBlock)
+	start := time.Now()
+	if _, err = b.CancelOrder("a"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("blocked for %v only", d)
+	}
+
+	// the public budget is untouched by private requests
+	b.limiter.m.Lock()
+	public := b.limiter.public.tokens
+	b.limiter.m.Unlock()
+	if public != float64(DefaultPublicRateLimit) {
+		t.Errorf("public tokens %v", public)
+	}
+
+	b.SetRateLimitMode(RateLimitOff)
+	if _, err = b.CancelOrder("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if calls != 3 {
+		t.Errorf("%v requests sent, want 3", calls)
+	}
+	for i, ok := range signed {
+		if !ok {
+			t.Errorf("request %v: bad signature", i)
+		}
+	}
+}
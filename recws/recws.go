@@ -3,9 +3,12 @@
 package recws
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"log"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"sync"
@@ -33,6 +36,12 @@ type RecConn struct {
 	// HandshakeTimeout specifies the duration for the handshake to complete,
 	// default to 2 seconds
 	HandshakeTimeout time.Duration
+	// TLSClientConfig specifies the TLS configuration of wss connections,
+	// default to the zero configuration
+	TLSClientConfig *tls.Config
+	// NetDialContext specifies the dial function of the connections,
+	// default to net.DialContext
+	NetDialContext func(ctx context.Context, network, addr string) (net.Conn, error)
 	// NonVerbose suppress connecting/reconnecting messages.
 	NonVerbose bool
 	// SubscribeHandler fires after the connection successfully establish.
@@ -269,6 +278,8 @@ func (rc *RecConn) setDefaultDialer(handshakeTimeout time.Duration) {
 
 	rc.dialer = &websocket.Dialer{
 		HandshakeTimeout: handshakeTimeout,
+		TLSClientConfig:  rc.TLSClientConfig,
+		NetDialContext:   rc.NetDialContext,
 	}
 	if rc.proxyURL != "" {
 		proxyURL_, err := url.Parse(rc.proxyURL)
@@ -1,6 +1,7 @@
 package bitmex
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -45,10 +46,28 @@ type Version struct {
 	Timestamp int64  `json:"timestamp"`
 }
 
-func (b *BitMEX) GetVersion() (version Version, time time.Duration, err error) {
-	url := "https://" + b.host + "/api/v1"
+// GetVersion returns the API version and the server time, and the round trip
+// of the request
+func (b *BitMEX) GetVersion() (version Version, rtt time.Duration, err error) {
+	return b.GetVersionContext(context.Background())
+}
+
+// GetVersionContext is like GetVersion but takes a context
+func (b *BitMEX) GetVersionContext(ctx context.Context) (version Version, rtt time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodGet, b.cfg.BasePath, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("User-Agent", b.cfg.UserAgent)
+	for k, v := range b.cfg.DefaultHeader {
+		req.Header.Set(k, v)
+	}
+	if id := TraceID(ctx); id != "" {
+		req.Header.Set(swagger.TraceIDHeader, id)
+	}
+	sent := time.Now()
 	var resp *http.Response
-	resp, err = b.httpClient.Get(url)
+	resp, err = b.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return
 	}
@@ -57,18 +76,23 @@ func (b *BitMEX) GetVersion() (version Version, time time.Duration, err error) {
 	if err != nil {
 		return
 	}
-	//time = resp.Time()
+	rtt = time.Since(sent)
 	err = json.Unmarshal(body, &version)
 	return
 }
 
 func (b *BitMEX) GetWallet() (wallet swagger.Wallet, err error) {
+	return b.GetWalletContext(context.Background())
+}
+
+// GetWalletContext is like GetWallet but takes a context
+func (b *BitMEX) GetWalletContext(ctx context.Context) (wallet swagger.Wallet, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{
 		"currency": "",
 	}
-	wallet, response, err = b.client.UserApi.UserGetWallet(b.ctx, params)
+	wallet, response, err = b.client.UserApi.UserGetWallet(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -77,12 +101,17 @@ func (b *BitMEX) GetWallet() (wallet swagger.Wallet, err error) {
 }
 
 func (b *BitMEX) GetMargin() (margin swagger.Margin, err error) {
+	return b.GetMarginContext(context.Background())
+}
+
+// GetMarginContext is like GetMargin but takes a context
+func (b *BitMEX) GetMarginContext(ctx context.Context) (margin swagger.Margin, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{
 		//"currency": "XBt",
 	}
-	margin, response, err = b.client.UserApi.UserGetMargin(b.ctx, params)
+	margin, response, err = b.client.UserApi.UserGetMargin(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -90,13 +119,13 @@ func (b *BitMEX) GetMargin() (margin swagger.Margin, err error) {
 	return
 }
 
-func (b *BitMEX) getOrderBookL2(depth int, symbol string) (orderbook []swagger.OrderBookL2, err error) {
+func (b *BitMEX) getOrderBookL2(ctx context.Context, depth int, symbol string) (orderbook []swagger.OrderBookL2, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
 	params["depth"] = float32(depth)
 
-	orderbook, response, err = b.client.OrderBookApi.OrderBookGetL2(symbol, params)
+	orderbook, response, err = b.client.OrderBookApi.OrderBookGetL2(ctx, symbol, params)
 	if err != nil {
 		return
 	}
@@ -105,8 +134,13 @@ func (b *BitMEX) getOrderBookL2(depth int, symbol string) (orderbook []swagger.O
 }
 
 func (b *BitMEX) GetOrderBook(depth int, symbol string) (ob OrderBook, err error) {
+	return b.GetOrderBookContext(context.Background(), depth, symbol)
+}
+
+// GetOrderBookContext is like GetOrderBook but takes a context
+func (b *BitMEX) GetOrderBookContext(ctx context.Context, depth int, symbol string) (ob OrderBook, err error) {
 	var orderbook []swagger.OrderBookL2
-	orderbook, err = b.getOrderBookL2(depth, symbol)
+	orderbook, err = b.getOrderBookL2(ctx, depth, symbol)
 	if err != nil {
 		return
 	}
@@ -138,6 +172,11 @@ func (b *BitMEX) GetOrderBook(depth int, symbol string) (ob OrderBook, err error
 }
 
 func (b *BitMEX) GetBucketed(symbol string, binSize string, partial bool, filter string, columns string, count float32, start float32, reverse bool, startTime time.Time, endTime time.Time) (o []swagger.TradeBin, err error) {
+	return b.GetBucketedContext(context.Background(), symbol, binSize, partial, filter, columns, count, start, reverse, startTime, endTime)
+}
+
+// GetBucketedContext is like GetBucketed but takes a context
+func (b *BitMEX) GetBucketedContext(ctx context.Context, symbol string, binSize string, partial bool, filter string, columns string, count float32, start float32, reverse bool, startTime time.Time, endTime time.Time) (o []swagger.TradeBin, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
@@ -164,7 +203,7 @@ func (b *BitMEX) GetBucketed(symbol string, binSize string, partial bool, filter
 		params["endTime"] = endTime
 	}
 	//params["endTime"] = endTime
-	o, response, err = b.client.TradeApi.TradeGetBucketed(params)
+	o, response, err = b.client.TradeApi.TradeGetBucketed(ctx, params)
 	if err != nil {
 		return
 	}
@@ -173,8 +212,13 @@ func (b *BitMEX) GetBucketed(symbol string, binSize string, partial bool, filter
 }
 
 func (b *BitMEX) GetPosition(symbol string) (position swagger.Position, err error) {
+	return b.GetPositionContext(context.Background(), symbol)
+}
+
+// GetPositionContext is like GetPosition but takes a context
+func (b *BitMEX) GetPositionContext(ctx context.Context, symbol string) (position swagger.Position, err error) {
 	var positions []swagger.Position
-	positions, err = b.GetPositions(symbol)
+	positions, err = b.GetPositionsContext(ctx, symbol)
 	if err != nil {
 		return
 	}
@@ -191,6 +235,11 @@ func (b *BitMEX) GetPosition(symbol string) (position swagger.Position, err erro
 }
 
 func (b *BitMEX) GetPositions(symbol string) (positions []swagger.Position, err error) {
+	return b.GetPositionsContext(context.Background(), symbol)
+}
+
+// GetPositionsContext is like GetPositions but takes a context
+func (b *BitMEX) GetPositionsContext(ctx context.Context, symbol string) (positions []swagger.Position, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
@@ -198,7 +247,7 @@ func (b *BitMEX) GetPositions(symbol string) (positions []swagger.Position, err
 		params["filter"] = fmt.Sprintf(`{"symbol":"%s"}`, symbol)
 	}
 
-	positions, response, err = b.client.PositionApi.PositionGet(b.ctx, params)
+	positions, response, err = b.client.PositionApi.PositionGet(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -207,6 +256,11 @@ func (b *BitMEX) GetPositions(symbol string) (positions []swagger.Position, err
 }
 
 func (b *BitMEX) GetPositionsRaw(filter string, columns string, count int32) (positions []swagger.Position, err error) {
+	return b.GetPositionsRawContext(context.Background(), filter, columns, count)
+}
+
+// GetPositionsRawContext is like GetPositionsRaw but takes a context
+func (b *BitMEX) GetPositionsRawContext(ctx context.Context, filter string, columns string, count int32) (positions []swagger.Position, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
@@ -220,7 +274,7 @@ func (b *BitMEX) GetPositionsRaw(filter string, columns string, count int32) (po
 		params["count"] = count
 	}
 
-	positions, response, err = b.client.PositionApi.PositionGet(b.ctx, params)
+	positions, response, err = b.client.PositionApi.PositionGet(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -229,8 +283,130 @@ func (b *BitMEX) GetPositionsRaw(filter string, columns string, count int32) (po
 }
 
 func (b *BitMEX) PositionUpdateLeverage(leverage float64, symbol string) (position swagger.Position, err error) {
+	return b.PositionUpdateLeverageContext(context.Background(), leverage, symbol)
+}
+
+// PositionUpdateLeverageContext is like PositionUpdateLeverage but takes a context
+func (b *BitMEX) PositionUpdateLeverageContext(ctx context.Context, leverage float64, symbol string) (position swagger.Position, err error) {
+	var response *http.Response
+	position, response, err = b.client.PositionApi.PositionUpdateLeverage(b.authContext(ctx), symbol, leverage)
+	if err != nil {
+		return
+	}
+	b.onResponse(response)
+	return
+}
+
+// PositionIsolateMargin switches the position of symbol to isolated margin,
+// or back to cross margin when isolated is false
+func (b *BitMEX) PositionIsolateMargin(isolated bool, symbol string) (position swagger.Position, err error) {
+	return b.PositionIsolateMarginContext(context.Background(), isolated, symbol)
+}
+
+// PositionIsolateMarginContext is like PositionIsolateMargin but takes a context
+func (b *BitMEX) PositionIsolateMarginContext(ctx context.Context, isolated bool, symbol string) (position swagger.Position, err error) {
+	var response *http.Response
+
+	params := map[string]interface{}{}
+	params["enabled"] = isolated
+
+	position, response, err = b.client.PositionApi.PositionIsolateMargin(b.authContext(ctx), symbol, params)
+	if err != nil {
+		return
+	}
+	b.onResponse(response)
+	return
+}
+
+// PositionTransferIsolatedMargin adds margin to an isolated position, or
+// removes it when amount is negative. amount must be in the settlement
+// currency of symbol.
+func (b *BitMEX) PositionTransferIsolatedMargin(amount Amount, symbol string) (position swagger.Position, err error) {
+	return b.PositionTransferIsolatedMarginContext(context.Background(), amount, symbol)
+}
+
+// PositionTransferIsolatedMarginContext is like PositionTransferIsolatedMargin but takes a context
+func (b *BitMEX) PositionTransferIsolatedMarginContext(ctx context.Context, amount Amount, symbol string) (position swagger.Position, err error) {
+	var response *http.Response
+
+	if amount.Value == 0 {
+		err = errors.New("transfer amount is zero")
+		return
+	}
+	if spec, ok := b.instruments.Get(symbol); ok && spec.SettlCurrency != "" && spec.SettlCurrency != amount.Currency {
+		err = fmt.Errorf("%v settles in %v, not %v", symbol, spec.SettlCurrency, amount.Currency)
+		return
+	}
+
+	position, response, err = b.client.PositionApi.PositionTransferIsolatedMargin(b.authContext(ctx), symbol, amount.Value)
+	if err != nil {
+		return
+	}
+	b.onResponse(response)
+	return
+}
+
+// PositionUpdateRiskLimit sets the risk limit of the position of symbol, in
+// settlement base units. It must be the base risk limit of the instrument
+// plus a multiple of its risk step.
+func (b *BitMEX) PositionUpdateRiskLimit(riskLimit int64, symbol string) (position swagger.Position, err error) {
+	return b.PositionUpdateRiskLimitContext(context.Background(), riskLimit, symbol)
+}
+
+// PositionUpdateRiskLimitContext is like PositionUpdateRiskLimit but takes a context
+func (b *BitMEX) PositionUpdateRiskLimitContext(ctx context.Context, riskLimit int64, symbol string) (position swagger.Position, err error) {
+	var response *http.Response
+
+	if err = b.checkRiskLimit(symbol, &riskLimit); err != nil {
+		return
+	}
+
+	position, response, err = b.client.PositionApi.PositionUpdateRiskLimit(b.authContext(ctx), symbol, riskLimit)
+	if err != nil {
+		return
+	}
+	b.onResponse(response)
+	return
+}
+
+// ClosePosition closes the whole position of symbol with a limit order at
+// price, or at market when price is 0. The side of the order is taken from
+// the position stream, so that ValidateRound can round price passively;
+// without it an off-tick price is rejected.
+func (b *BitMEX) ClosePosition(price Price, symbol string) (order swagger.Order, err error) {
+	return b.ClosePositionContext(context.Background(), price, symbol)
+}
+
+// ClosePositionContext is like ClosePosition but takes a context
+func (b *BitMEX) ClosePositionContext(ctx context.Context, price Price, symbol string) (order swagger.Order, err error) {
 	var response *http.Response
-	position, response, err = b.client.PositionApi.PositionUpdateLeverage(b.ctx, symbol, leverage)
+
+	// closing sells a long position and buys a short one
+	var side Side
+	if position, ok := b.localPosition(symbol); ok {
+		if qty := position.CurrentQty.V; qty > 0 {
+			side = SideSell
+		} else if qty < 0 {
+			side = SideBuy
+		}
+	}
+	if err = b.checkOrder(symbol, string(side), &price, nil); err != nil {
+		return
+	}
+	ordType := OrdTypeMarket
+	if !price.IsZero() {
+		ordType = OrdTypeLimit
+	}
+	if err = b.checkRisk(&RiskOrder{Symbol: symbol, Side: side, OrdType: ordType, Price: price.Float64(), ExecInst: ExecClose}); err != nil {
+		return
+	}
+
+	params := map[string]interface{}{}
+	if !price.IsZero() {
+		params["price"] = price.String()
+	}
+
+	order, response, err = b.client.OrderApi.OrderClosePosition(b.authContext(ctx), symbol, params)
 	if err != nil {
 		return
 	}
@@ -239,13 +415,18 @@ func (b *BitMEX) PositionUpdateLeverage(leverage float64, symbol string) (positi
 }
 
 func (b *BitMEX) GetOrders(symbol string) (orders []swagger.Order, err error) {
+	return b.GetOrdersContext(context.Background(), symbol)
+}
+
+// GetOrdersContext is like GetOrders but takes a context
+func (b *BitMEX) GetOrdersContext(ctx context.Context, symbol string) (orders []swagger.Order, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
 	params["symbol"] = symbol
 	params["filter"] = `{"open":true}`
 
-	orders, response, err = b.client.OrderApi.OrderGetOrders(b.ctx, params)
+	orders, response, err = b.client.OrderApi.OrderGetOrders(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -256,6 +437,11 @@ func (b *BitMEX) GetOrders(symbol string) (orders []swagger.Order, err error) {
 }
 
 func (b *BitMEX) GetOrdersRaw(symbol string, filter string) (orders []swagger.Order, err error) {
+	return b.GetOrdersRawContext(context.Background(), symbol, filter)
+}
+
+// GetOrdersRawContext is like GetOrdersRaw but takes a context
+func (b *BitMEX) GetOrdersRawContext(ctx context.Context, symbol string, filter string) (orders []swagger.Order, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
@@ -266,7 +452,7 @@ func (b *BitMEX) GetOrdersRaw(symbol string, filter string) (orders []swagger.Or
 		params["filter"] = filter // `{"open":true}`
 	}
 
-	orders, response, err = b.client.OrderApi.OrderGetOrders(b.ctx, params)
+	orders, response, err = b.client.OrderApi.OrderGetOrders(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -276,76 +462,91 @@ func (b *BitMEX) GetOrdersRaw(symbol string, filter string) (orders []swagger.Or
 	return
 }
 
-func (b *BitMEX) NewOrder(side string, ordType string, price float64, orderQty int32, postOnly bool, timeInForce string, symbol string) (order swagger.Order, err error) {
-	var response *http.Response
+func (b *BitMEX) NewOrder(side Side, ordType OrdType, price Price, orderQty int64, postOnly bool, timeInForce TimeInForce, symbol string) (order swagger.Order, err error) {
+	return b.NewOrderContext(context.Background(), side, ordType, price, orderQty, postOnly, timeInForce, symbol)
+}
+
+// NewOrderContext is like NewOrder but takes a context
+func (b *BitMEX) NewOrderContext(ctx context.Context, side Side, ordType OrdType, price Price, orderQty int64, postOnly bool, timeInForce TimeInForce, symbol string) (order swagger.Order, err error) {
+	var execInst ExecInst
+	if postOnly {
+		execInst = ExecParticipateDoNotInitiate
+	}
+	if err = checkEnums(side, ordType, timeInForce, execInst); err != nil {
+		return
+	}
+	qty := float64(orderQty)
+	if err = b.checkOrder(symbol, string(side), &price, nil, qtyField{"orderQty", &qty}); err != nil {
+		return
+	}
+	if err = b.checkRisk(&RiskOrder{Symbol: symbol, Side: side, OrdType: ordType, OrderQty: int64(qty), Price: price.Float64(), ExecInst: execInst}); err != nil {
+		return
+	}
 
 	params := map[string]interface{}{}
 	params["symbol"] = symbol
-	// params["clOrdID"] = ""	// 客户端委托ID
-	params["side"] = side
-	params["ordType"] = ordType
-	params["orderQty"] = float32(orderQty)
-	if price > 0.0 {
-		params["price"] = price // Limit order only
+	params["side"] = string(side)
+	params["ordType"] = string(ordType)
+	params["orderQty"] = int64(qty)
+	if !price.IsZero() {
+		params["price"] = price.String() // Limit order only
 	}
 	params["text"] = `open with bitmex api`
 
 	if timeInForce != "" { // "FillOrKill"	// 全数执行或立刻取消
-		params["timeInForce"] = timeInForce
+		params["timeInForce"] = string(timeInForce)
 	}
 
 	if postOnly {
-		params["execInst"] = "ParticipateDoNotInitiate"
+		params["execInst"] = execInst.String()
 	}
 
-	order, response, err = b.client.OrderApi.OrderNew(b.ctx, symbol, params)
-	if err != nil {
-		// >= 300 代表有错误
-		// 400 Bad Request
-		// 503
-		// log.Printf("response.StatusCode: %v", response.StatusCode)
-		return
-	}
-	b.onResponse(response)
+	order, err = b.submitOrder(b.authContext(ctx), symbol, params)
 	return
 }
 
 // PlaceOrder 放置委托单
 // execInst: MarkPrice = 标记价格 IndexPrice = 指数价格 LastPrice = 最新成交 ParticipateDoNotInitiate = 被动委托
-func (b *BitMEX) PlaceOrder(side string, ordType string, stopPx float64, price float64, orderQty int32, timeInForce string, execInst string, symbol string) (order swagger.Order, err error) {
-	var response *http.Response
+func (b *BitMEX) PlaceOrder(side Side, ordType OrdType, stopPx Price, price Price, orderQty int64, timeInForce TimeInForce, execInst ExecInst, symbol string) (order swagger.Order, err error) {
+	return b.PlaceOrderContext(context.Background(), side, ordType, stopPx, price, orderQty, timeInForce, execInst, symbol)
+}
+
+// PlaceOrderContext is like PlaceOrder but takes a context
+func (b *BitMEX) PlaceOrderContext(ctx context.Context, side Side, ordType OrdType, stopPx Price, price Price, orderQty int64, timeInForce TimeInForce, execInst ExecInst, symbol string) (order swagger.Order, err error) {
+	if err = checkEnums(side, ordType, timeInForce, execInst); err != nil {
+		return
+	}
+	qty := float64(orderQty)
+	if err = b.checkOrder(symbol, string(side), &price, &stopPx, qtyField{"orderQty", &qty}); err != nil {
+		return
+	}
+	if err = b.checkRisk(&RiskOrder{Symbol: symbol, Side: side, OrdType: ordType, OrderQty: int64(qty),
+		Price: price.Float64(), StopPx: stopPx.Float64(), ExecInst: execInst}); err != nil {
+		return
+	}
 
 	params := map[string]interface{}{}
 	params["symbol"] = symbol
-	// params["clOrdID"] = ""	// 客户端委托ID
-	params["side"] = side
-	params["ordType"] = ordType
-	params["orderQty"] = float32(orderQty)
-	if stopPx > 0.0 {
-		params["stopPx"] = stopPx
+	params["side"] = string(side)
+	params["ordType"] = string(ordType)
+	params["orderQty"] = int64(qty)
+	if !stopPx.IsZero() {
+		params["stopPx"] = stopPx.String()
 	}
-	if price > 0.0 {
-		params["price"] = price // Limit order only
+	if !price.IsZero() {
+		params["price"] = price.String() // Limit order only
 	}
 	params["text"] = `open with bitmex api`
 
 	if timeInForce != "" { // "FillOrKill"	// 全数执行或立刻取消
-		params["timeInForce"] = timeInForce
+		params["timeInForce"] = string(timeInForce)
 	}
 
-	if execInst != "" {
-		params["execInst"] = execInst
+	if execInst != 0 {
+		params["execInst"] = execInst.String()
 	}
 
-	order, response, err = b.client.OrderApi.OrderNew(b.ctx, symbol, params)
-	if err != nil {
-		// >= 300 代表有错误
-		// 400 Bad Request
-		// 503
-		// log.Printf("response.StatusCode: %v", response.StatusCode)
-		return
-	}
-	b.onResponse(response)
+	order, err = b.submitOrder(b.authContext(ctx), symbol, params)
 	return
 }
 
@@ -357,26 +558,43 @@ func (b *BitMEX) PlaceOrder(side string, ordType string, stopPx float64, price f
 // orderQty: 委托数量
 // displayQty: 默认传: -1
 // execInst: MarkPrice = 标记价格 IndexPrice = 指数价格 LastPrice = 最新成交 ParticipateDoNotInitiate = 被动委托
-func (b *BitMEX) PlaceOrder2(side string, ordType string, stopPx float64, price float64, orderQty int32,
-	displayQty int32, timeInForce string, execInst string, symbol string, clOrdID string, text string) (order swagger.Order, err error) {
-	var response *http.Response
+// clOrdID: 客户端委托ID, 为空时自动生成 (see NewClOrdID)
+func (b *BitMEX) PlaceOrder2(side Side, ordType OrdType, stopPx Price, price Price, orderQty int64,
+	displayQty int64, timeInForce TimeInForce, execInst ExecInst, symbol string, clOrdID string, text string) (order swagger.Order, err error) {
+	return b.PlaceOrder2Context(context.Background(), side, ordType, stopPx, price, orderQty, displayQty, timeInForce, execInst, symbol, clOrdID, text)
+}
+
+// PlaceOrder2Context is like PlaceOrder2 but takes a context
+func (b *BitMEX) PlaceOrder2Context(ctx context.Context, side Side, ordType OrdType, stopPx Price, price Price, orderQty int64,
+	displayQty int64, timeInForce TimeInForce, execInst ExecInst, symbol string, clOrdID string, text string) (order swagger.Order, err error) {
+	if err = checkEnums(side, ordType, timeInForce, execInst); err != nil {
+		return
+	}
+	qty, display := float64(orderQty), float64(displayQty)
+	if err = b.checkOrder(symbol, string(side), &price, &stopPx, qtyField{"orderQty", &qty}, qtyField{"displayQty", &display}); err != nil {
+		return
+	}
+	if err = b.checkRisk(&RiskOrder{Symbol: symbol, Side: side, OrdType: ordType, OrderQty: int64(qty),
+		Price: price.Float64(), StopPx: stopPx.Float64(), ExecInst: execInst}); err != nil {
+		return
+	}
 
 	params := map[string]interface{}{}
 	if clOrdID != "" {
 		params["clOrdID"] = clOrdID // 客户端委托ID
 	}
 	params["symbol"] = symbol
-	params["side"] = side
-	params["ordType"] = ordType
-	params["orderQty"] = float32(orderQty)
+	params["side"] = string(side)
+	params["ordType"] = string(ordType)
+	params["orderQty"] = int64(qty)
 	if displayQty >= 0 {
-		params["displayQty"] = float32(displayQty)
+		params["displayQty"] = int64(display)
 	}
-	if stopPx > 0.0 {
-		params["stopPx"] = stopPx
+	if !stopPx.IsZero() {
+		params["stopPx"] = stopPx.String()
 	}
-	if price > 0.0 {
-		params["price"] = price // Limit order only
+	if !price.IsZero() {
+		params["price"] = price.String() // Limit order only
 	}
 	if text == "" {
 		params["text"] = `open with bitmex api`
@@ -385,26 +603,23 @@ func (b *BitMEX) PlaceOrder2(side string, ordType string, stopPx float64, price
 	}
 
 	if timeInForce != "" { // "FillOrKill"	// 全数执行或立刻取消
-		params["timeInForce"] = timeInForce
+		params["timeInForce"] = string(timeInForce)
 	}
 
-	if execInst != "" {
-		params["execInst"] = execInst
+	if execInst != 0 {
+		params["execInst"] = execInst.String()
 	}
 
-	order, response, err = b.client.OrderApi.OrderNew(b.ctx, symbol, params)
-	if err != nil {
-		// >= 300 代表有错误
-		// 400 Bad Request
-		// 503
-		// log.Printf("response.StatusCode: %v", response.StatusCode)
-		return
-	}
-	b.onResponse(response)
+	order, err = b.submitOrder(b.authContext(ctx), symbol, params)
 	return
 }
 
 func (b *BitMEX) GetOrder(oid string, symbol string) (order swagger.Order, err error) {
+	return b.GetOrderContext(context.Background(), oid, symbol)
+}
+
+// GetOrderContext is like GetOrder but takes a context
+func (b *BitMEX) GetOrderContext(ctx context.Context, oid string, symbol string) (order swagger.Order, err error) {
 	var response *http.Response
 	var orders []swagger.Order
 
@@ -412,7 +627,7 @@ func (b *BitMEX) GetOrder(oid string, symbol string) (order swagger.Order, err e
 	params["symbol"] = symbol
 	params["filter"] = fmt.Sprintf(`{"orderID":"%s"}`, oid)
 
-	orders, response, err = b.client.OrderApi.OrderGetOrders(b.ctx, params)
+	orders, response, err = b.client.OrderApi.OrderGetOrders(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -426,6 +641,11 @@ func (b *BitMEX) GetOrder(oid string, symbol string) (order swagger.Order, err e
 }
 
 func (b *BitMEX) GetOrderByClOrdID(clOrdID string, symbol string) (order swagger.Order, err error) {
+	return b.GetOrderByClOrdIDContext(context.Background(), clOrdID, symbol)
+}
+
+// GetOrderByClOrdIDContext is like GetOrderByClOrdID but takes a context
+func (b *BitMEX) GetOrderByClOrdIDContext(ctx context.Context, clOrdID string, symbol string) (order swagger.Order, err error) {
 	var response *http.Response
 	var orders []swagger.Order
 
@@ -433,7 +653,7 @@ func (b *BitMEX) GetOrderByClOrdID(clOrdID string, symbol string) (order swagger
 	params["symbol"] = symbol
 	params["filter"] = fmt.Sprintf(`{"clOrdID":"%s"}`, clOrdID)
 
-	orders, response, err = b.client.OrderApi.OrderGetOrders(b.ctx, params)
+	orders, response, err = b.client.OrderApi.OrderGetOrders(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -446,14 +666,26 @@ func (b *BitMEX) GetOrderByClOrdID(clOrdID string, symbol string) (order swagger
 	return
 }
 
-func (b *BitMEX) AmendOrder(oid string, price float64) (order swagger.Order, err error) {
+func (b *BitMEX) AmendOrder(oid string, price Price) (order swagger.Order, err error) {
+	return b.AmendOrderContext(context.Background(), oid, price)
+}
+
+// AmendOrderContext is like AmendOrder but takes a context
+func (b *BitMEX) AmendOrderContext(ctx context.Context, oid string, price Price) (order swagger.Order, err error) {
 	var response *http.Response
 
+	if err = b.checkAmend(oid, &price, nil); err != nil {
+		return
+	}
+	if err = b.checkAmendRisk(oid, 0, 0, price, Price{}); err != nil {
+		return
+	}
+
 	params := map[string]interface{}{}
 	params["orderID"] = oid
-	params["price"] = price
+	params["price"] = price.String()
 
-	order, response, err = b.client.OrderApi.OrderAmend(b.ctx, params)
+	order, response, err = b.client.OrderApi.OrderAmend(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -461,9 +693,22 @@ func (b *BitMEX) AmendOrder(oid string, price float64) (order swagger.Order, err
 	return
 }
 
-func (b *BitMEX) AmendOrder2(orderID string, origClOrdID string, clOrdID string, simpleOrderQty float64, orderQty float32, simpleLeavesQty float64, leavesQty float32, price float64, stopPx float64, pegOffsetValue float64, text string) (order swagger.Order, err error) {
+func (b *BitMEX) AmendOrder2(orderID string, origClOrdID string, clOrdID string, simpleOrderQty float64, orderQty int64, simpleLeavesQty float64, leavesQty int64, price Price, stopPx Price, pegOffsetValue Price, text string) (order swagger.Order, err error) {
+	return b.AmendOrder2Context(context.Background(), orderID, origClOrdID, clOrdID, simpleOrderQty, orderQty, simpleLeavesQty, leavesQty, price, stopPx, pegOffsetValue, text)
+}
+
+// AmendOrder2Context is like AmendOrder2 but takes a context
+func (b *BitMEX) AmendOrder2Context(ctx context.Context, orderID string, origClOrdID string, clOrdID string, simpleOrderQty float64, orderQty int64, simpleLeavesQty float64, leavesQty int64, price Price, stopPx Price, pegOffsetValue Price, text string) (order swagger.Order, err error) {
 	var response *http.Response
 
+	qty, leaves := float64(orderQty), float64(leavesQty)
+	if err = b.checkAmend(orderID, &price, &stopPx, qtyField{"orderQty", &qty}, qtyField{"leavesQty", &leaves}); err != nil {
+		return
+	}
+	if err = b.checkAmendRisk(orderID, int64(qty), int64(leaves), price, stopPx); err != nil {
+		return
+	}
+
 	params := map[string]interface{}{}
 	if orderID != "" {
 		params["orderID"] = orderID
@@ -478,28 +723,28 @@ func (b *BitMEX) AmendOrder2(orderID string, origClOrdID string, clOrdID string,
 		params["simpleOrderQty"] = simpleOrderQty
 	}
 	if orderQty != 0 {
-		params["orderQty"] = orderQty
+		params["orderQty"] = int64(qty)
 	}
 	if simpleLeavesQty != 0 {
 		params["simpleLeavesQty"] = simpleLeavesQty
 	}
 	if leavesQty != 0 {
-		params["leavesQty"] = leavesQty
+		params["leavesQty"] = int64(leaves)
 	}
-	if price != 0 {
-		params["price"] = price
+	if !price.IsZero() {
+		params["price"] = price.String()
 	}
-	if stopPx != 0 {
-		params["stopPx"] = stopPx
+	if !stopPx.IsZero() {
+		params["stopPx"] = stopPx.String()
 	}
-	if pegOffsetValue != 0 {
-		params["pegOffsetValue"] = pegOffsetValue
+	if !pegOffsetValue.IsZero() {
+		params["pegOffsetValue"] = pegOffsetValue.String()
 	}
 	if text != "" {
 		params["text"] = text
 	}
 
-	order, response, err = b.client.OrderApi.OrderAmend(b.ctx, params)
+	order, response, err = b.client.OrderApi.OrderAmend(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -508,13 +753,18 @@ func (b *BitMEX) AmendOrder2(orderID string, origClOrdID string, clOrdID string,
 }
 
 func (b *BitMEX) CancelAllOrders(symbol string) (orders []swagger.Order, err error) {
+	return b.CancelAllOrdersContext(context.Background(), symbol)
+}
+
+// CancelAllOrdersContext is like CancelAllOrders but takes a context
+func (b *BitMEX) CancelAllOrdersContext(ctx context.Context, symbol string) (orders []swagger.Order, err error) {
 	var response *http.Response
 
 	params := map[string]interface{}{}
 	params["symbol"] = symbol
 	params["text"] = "cancel order with bitmex api"
 
-	orders, response, err = b.client.OrderApi.OrderCancelAll(b.ctx, params)
+	orders, response, err = b.client.OrderApi.OrderCancelAll(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -523,6 +773,11 @@ func (b *BitMEX) CancelAllOrders(symbol string) (orders []swagger.Order, err err
 }
 
 func (b *BitMEX) CancelOrder(oid string) (order swagger.Order, err error) {
+	return b.CancelOrderContext(context.Background(), oid)
+}
+
+// CancelOrderContext is like CancelOrder but takes a context
+func (b *BitMEX) CancelOrderContext(ctx context.Context, oid string) (order swagger.Order, err error) {
 	var response *http.Response
 	var orders []swagger.Order
 
@@ -530,7 +785,7 @@ func (b *BitMEX) CancelOrder(oid string) (order swagger.Order, err error) {
 	params["orderID"] = oid
 	params["text"] = "cancel order with bitmex api"
 
-	orders, response, err = b.client.OrderApi.OrderCancel(b.ctx, params)
+	orders, response, err = b.client.OrderApi.OrderCancel(b.authContext(ctx), params)
 	if err != nil {
 		return
 	}
@@ -543,38 +798,55 @@ func (b *BitMEX) CancelOrder(oid string) (order swagger.Order, err error) {
 	return
 }
 
-func (b *BitMEX) CloseOrder(side string, ordType string, price float64, orderQty int32, postOnly bool, timeInForce string, symbol string) (order swagger.Order, err error) {
-	var response *http.Response
+func (b *BitMEX) CloseOrder(side Side, ordType OrdType, price Price, orderQty int64, postOnly bool, timeInForce TimeInForce, symbol string) (order swagger.Order, err error) {
+	return b.CloseOrderContext(context.Background(), side, ordType, price, orderQty, postOnly, timeInForce, symbol)
+}
+
+// CloseOrderContext is like CloseOrder but takes a context
+func (b *BitMEX) CloseOrderContext(ctx context.Context, side Side, ordType OrdType, price Price, orderQty int64, postOnly bool, timeInForce TimeInForce, symbol string) (order swagger.Order, err error) {
+	execInst := ExecClose
+	if postOnly {
+		execInst |= ExecParticipateDoNotInitiate
+	}
+	if err = checkEnums(side, ordType, timeInForce, execInst); err != nil {
+		return
+	}
+	qty := float64(orderQty)
+	if err = b.checkOrder(symbol, string(side), &price, nil, qtyField{"orderQty", &qty}); err != nil {
+		return
+	}
+	if err = b.checkRisk(&RiskOrder{Symbol: symbol, Side: side, OrdType: ordType, OrderQty: int64(qty), Price: price.Float64(), ExecInst: execInst}); err != nil {
+		return
+	}
 
 	params := map[string]interface{}{}
 	params["symbol"] = symbol
-	params["side"] = side
-	params["ordType"] = ordType
-	params["orderQty"] = float32(orderQty)
-	if price > 0.0 {
-		params["price"] = price // Limit order only
+	params["side"] = string(side)
+	params["ordType"] = string(ordType)
+	params["orderQty"] = int64(qty)
+	if !price.IsZero() {
+		params["price"] = price.String() // Limit order only
 	}
 	params["text"] = `close with bitmex api`
 
 	//timeInForce := "FillOrKill"	// 全数执行或立刻取消
 	if timeInForce != "" {
-		params["timeInForce"] = timeInForce
+		params["timeInForce"] = string(timeInForce)
 	}
 
-	execInst := "Close"
-	if postOnly {
-		execInst += ",ParticipateDoNotInitiate"
-	}
-	params["execInst"] = execInst
-	order, response, err = b.client.OrderApi.OrderNew(b.ctx, symbol, params)
-	if err != nil {
-		return
-	}
-	b.onResponse(response)
+	params["execInst"] = execInst.String()
+	order, err = b.submitOrder(b.authContext(ctx), symbol, params)
 	return
 }
 
-func (b *BitMEX) RequestWithdrawal(currency string, amount float32, address string, otpToken string, fee float64) (trans swagger.Transaction, err error) {
+// RequestWithdrawal requests a withdrawal of amount to address.
+// fee is in base units of amount.Currency; a negative fee lets the exchange choose.
+func (b *BitMEX) RequestWithdrawal(amount Amount, address string, otpToken string, fee int64) (trans swagger.Transaction, err error) {
+	return b.RequestWithdrawalContext(context.Background(), amount, address, otpToken, fee)
+}
+
+// RequestWithdrawalContext is like RequestWithdrawal but takes a context
+func (b *BitMEX) RequestWithdrawalContext(ctx context.Context, amount Amount, address string, otpToken string, fee int64) (trans swagger.Transaction, err error) {
 	var response *http.Response
 	params := map[string]interface{}{}
 	if otpToken != "" {
@@ -583,7 +855,7 @@ func (b *BitMEX) RequestWithdrawal(currency string, amount float32, address stri
 	if fee >= 0 {
 		params["fee"] = fee
 	}
-	trans, response, err = b.client.UserApi.UserRequestWithdrawal(b.ctx, currency, amount, address, params)
+	trans, response, err = b.client.UserApi.UserRequestWithdrawal(b.authContext(ctx), amount.Currency, amount.Value, address, params)
 	if err != nil {
 		return
 	}
@@ -592,8 +864,13 @@ func (b *BitMEX) RequestWithdrawal(currency string, amount float32, address stri
 }
 
 func (b *BitMEX) ConfirmWithdrawal(token string) (trans swagger.Transaction, err error) {
+	return b.ConfirmWithdrawalContext(context.Background(), token)
+}
+
+// ConfirmWithdrawalContext is like ConfirmWithdrawal but takes a context
+func (b *BitMEX) ConfirmWithdrawalContext(ctx context.Context, token string) (trans swagger.Transaction, err error) {
 	var response *http.Response
-	trans, response, err = b.client.UserApi.UserConfirmWithdrawal(token)
+	trans, response, err = b.client.UserApi.UserConfirmWithdrawal(ctx, token)
 	if err != nil {
 		return
 	}
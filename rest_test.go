@@ -1,6 +1,7 @@
 package bitmex
 
 import (
+	"context"
 	"math"
 	"testing"
 	"time"
@@ -25,7 +26,7 @@ func TestBitMEX_GetVersion(t *testing.T) {
 
 func TestBitMEX_GetOrderBookL2(t *testing.T) {
 	bitmex := newBitmexForTest()
-	orderBookL2, err := bitmex.getOrderBookL2(5, "XBTUSD")
+	orderBookL2, err := bitmex.getOrderBookL2(context.Background(), 5, "XBTUSD")
 	if err != nil {
 		t.Error(err)
 		return
@@ -120,7 +121,7 @@ func TestBitMEX_PositionUpdateLeverage(t *testing.T) {
 
 func TestBitMEX_NewOrder(t *testing.T) {
 	bitmex := newBitmexForTest()
-	price := 3000.0
+	price := MustPrice("3000")
 	order, err := bitmex.NewOrder(SIDE_BUY, ORD_TYPE_LIMIT, price, 20, true, "", "XBTUSD")
 	if err != nil {
 		// 403 Forbidden
@@ -131,7 +132,7 @@ func TestBitMEX_NewOrder(t *testing.T) {
 		t.Errorf("symbol error [%v]", order.Symbol)
 		return
 	}
-	if order.Price != price {
+	if order.Price != price.Float64() {
 		t.Errorf("price error [%v]", order.Price)
 		return
 	}
@@ -164,13 +165,13 @@ func TestBitMEX_CancelAllOrders(t *testing.T) {
 func TestBitMEX_AmendOrder(t *testing.T) {
 	bitmex := newBitmexForTest()
 	oid := `a17d25a3-6149-3edf-d196-75cc775beb29`
-	newPrice := 3001.0
+	newPrice := MustPrice("3001")
 	order, err := bitmex.AmendOrder(oid, newPrice)
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	if order.Price != newPrice {
+	if order.Price != newPrice.Float64() {
 		t.Error("price error")
 		return
 	}
@@ -179,7 +180,7 @@ func TestBitMEX_AmendOrder(t *testing.T) {
 
 func TestBitMEX_CloseOrder(t *testing.T) {
 	bitmex := newBitmexForTest()
-	price := 6000.0
+	price := MustPrice("6000")
 	order, err := bitmex.CloseOrder(SIDE_SELL, ORD_TYPE_LIMIT, price, 20, true, "", "XBTUSD")
 	if err != nil {
 		// 403 Forbidden
@@ -190,7 +191,7 @@ func TestBitMEX_CloseOrder(t *testing.T) {
 		t.Errorf("symbol error [%v]", order.Symbol)
 		return
 	}
-	if order.Price != price {
+	if order.Price != price.Float64() {
 		t.Errorf("price error [%v]", order.Price)
 		return
 	}
@@ -199,13 +200,12 @@ func TestBitMEX_CloseOrder(t *testing.T) {
 
 func TestBitMEX_RequestWithdrawal(t *testing.T) {
 	bitmex := newBitmexForTest2()
-	currency := "XBt"
-	amount := float32(1000000.0)
+	amount := XBt(1000000)
 	address := "3BMEXT9XuBTSkALWTovH1idLSC2tusjKBT"
 	optToken := ""
-	fee := 0.0
+	fee := int64(0)
 	// 401 Unauthorized
-	trans, err := bitmex.RequestWithdrawal(currency, amount, address, optToken, fee)
+	trans, err := bitmex.RequestWithdrawal(amount, address, optToken, fee)
 	if err != nil {
 		t.Error(err)
 		return
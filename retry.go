@@ -0,0 +1,313 @@
+package bitmex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker is open after repeated overloads
+var ErrCircuitOpen = errors.New("circuit breaker open: BitMEX is overloaded")
+
+// RetryPolicy tells how REST requests are retried.
+//
+// 503 overload and 429 answers mean BitMEX didn't process the request, so
+// every request is retried on them. Network errors and other 5xx answers
+// leave the outcome unknown: reads, amends and cancels are retried, a new
+// order only when it has a clOrdID and GetOrderByClOrdID doesn't find it.
+type RetryPolicy struct {
+	MaxAttempts int           // including the first, 0 or 1 disables retries
+	BaseDelay   time.Duration // doubled on every retry
+	MaxDelay    time.Duration
+	Jitter      float64 // fraction of the delay taken off at random, 0 to 1
+
+	// BreakerThreshold consecutive overloads open the circuit breaker for
+	// BreakerCooldown; 0 disables it. Cancels and kill switch actions are
+	// always sent.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultRetryPolicy is the policy of a new BitMEX
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:      3,
+	BaseDelay:        500 * time.Millisecond,
+	MaxDelay:         5 * time.Second,
+	Jitter:           0.5,
+	BreakerThreshold: 10,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// delay returns the backoff before retry n (1 for the first retry)
+func (p *RetryPolicy) delay(n int) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(2, float64(n-1))
+	if p.MaxDelay > 0 {
+		d = math.Min(d, float64(p.MaxDelay))
+	}
+	d -= d * p.Jitter * rand.Float64()
+	return time.Duration(d)
+}
+
+// retrier holds the retry policy and the circuit breaker
+type retrier struct {
+	m         sync.Mutex
+	policy    RetryPolicy
+	overloads int // consecutive
+	openUntil time.Time
+}
+
+func newRetrier() *retrier {
+	return &retrier{policy: DefaultRetryPolicy}
+}
+
+// allow reports whether the breaker lets a request of priority through
+func (r *retrier) allow(priority Priority) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return priority == PriorityCancel || !time.Now().Before(r.openUntil)
+}
+
+// observe counts the overloads and opens the breaker
+func (r *retrier) observe(response *http.Response) {
+	if response == nil {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	if response.StatusCode != http.StatusServiceUnavailable {
+		r.overloads = 0
+		return
+	}
+	r.overloads++
+	if r.policy.BreakerThreshold > 0 && r.overloads >= r.policy.BreakerThreshold {
+		r.openUntil = time.Now().Add(r.policy.BreakerCooldown)
+	}
+}
+
+// retryTransport retries the requests of a client by the retry policy
+type retryTransport struct {
+	b    *BitMEX
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := t.b.retrier
+	r.m.Lock()
+	policy := r.policy
+	r.m.Unlock()
+
+	priority := newRateRequest(req).priority
+	for attempt := 1; ; attempt++ {
+		if !r.allow(priority) {
+			return nil, ErrCircuitOpen
+		}
+		response, err := t.base.RoundTrip(req)
+		r.observe(response)
+
+		if attempt >= policy.MaxAttempts || req.Context().Err() != nil {
+			return response, err
+		}
+		retry, ambiguous := retryable(response, err)
+		if !retry {
+			return response, err
+		}
+		if ambiguous && !idempotent(req) {
+			found, ok := t.b.lookupOrder(req)
+			if found != nil {
+				if response != nil {
+					response.Body.Close()
+				}
+				return found, nil
+			}
+			if !ok {
+				return response, err
+			}
+		}
+
+		wait := policy.delay(attempt)
+		if response != nil {
+			if d := retryAfter(response); d > wait {
+				wait = d
+			}
+			response.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		if req, err = t.b.rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether the outcome of a request may be retried, and
+// whether the request may have been processed
+func retryable(response *http.Response, err error) (retry bool, ambiguous bool) {
+	if err != nil {
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) || errors.Is(err, ErrAmendSuperseded) ||
+			errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) {
+			return false, false
+		}
+		return true, true
+	}
+	switch {
+	case response.StatusCode == http.StatusServiceUnavailable, response.StatusCode == http.StatusTooManyRequests:
+		return true, false
+	case response.StatusCode >= 500:
+		return true, true
+	}
+	return false, false
+}
+
+// idempotent reports whether sending req twice does no harm: reads, amends
+// to absolute values and cancels
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait asked by Retry-After or X-Ratelimit-Reset
+func retryAfter(response *http.Response) time.Duration {
+	if v, err := strconv.ParseInt(response.Header.Get("Retry-After"), 10, 64); err == nil {
+		return time.Duration(v) * time.Second
+	}
+	if response.StatusCode == http.StatusTooManyRequests {
+		if v, err := strconv.ParseInt(response.Header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+			return time.Until(time.Unix(v, 0))
+		}
+	}
+	return 0
+}
+
+// lookupOrder looks up the new order of req by its clOrdID after an unknown
+// outcome. It returns the response to give for an order found, and ok when
+// the request can be retried.
+func (b *BitMEX) lookupOrder(req *http.Request) (found *http.Response, ok bool) {
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/order") || req.GetBody == nil {
+		return
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return
+	}
+	data, _ := ioutil.ReadAll(body)
+	var form struct {
+		Symbol  string `json:"symbol"`
+		ClOrdID string `json:"clOrdID"`
+	}
+	if json.Unmarshal(data, &form) != nil || form.ClOrdID == "" {
+		return
+	}
+
+	order, err := b.GetOrderByClOrdIDContext(detach(req.Context()), form.ClOrdID, form.Symbol)
+	if err == NotFound {
+		return nil, true
+	}
+	if err != nil {
+		return
+	}
+	data, err = json.Marshal(order)
+	if err != nil {
+		return
+	}
+	found = &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+	return
+}
+
+// rewind returns a copy of req to send again, signed anew
+func (b *BitMEX) rewind(req *http.Request) (*http.Request, error) {
+	var err error
+	if req.Header.Get("api-key") != "" {
+		req, err = b.resign(req)
+	} else {
+		req = req.Clone(req.Context())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// transport wraps the transport of a client with the retry policy, the
+// rate limiter and the timeout of each attempt
+func (b *BitMEX) transport(base http.RoundTripper) http.RoundTripper {
+	return &retryTransport{b: b, base: b.rateLimited(&attemptTransport{base: base, timeout: b.timeout})}
+}
+
+// attemptTransport bounds every attempt of a request by timeout. It sits
+// under the retry policy and the rate limiter, so their waits don't count.
+type attemptTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *attemptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	response, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the timeout covers reading the body, as Client.Timeout does
+	response.Body = &cancelBody{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+// cancelBody releases the context of an attempt when the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelBody) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// SetRetryPolicy replaces the retry policy of REST calls
+func (b *BitMEX) SetRetryPolicy(policy RetryPolicy) {
+	b.retrier.m.Lock()
+	defer b.retrier.m.Unlock()
+	b.retrier.policy = policy
+}
+
+// CircuitOpen reports whether the circuit breaker holds back requests
+func (b *BitMEX) CircuitOpen() bool {
+	return !b.retrier.allow(PriorityNew)
+}
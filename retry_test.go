@@ -0,0 +1,220 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// retryServer answers the requests of a method with its statuses in turn,
+// then with 200
+type retryServer struct {
+	m        sync.Mutex
+	statuses map[string][]int
+	calls    map[string]int
+	placed   []swagger.Order // orders stored by POST /order, even when failing
+}
+
+func newRetryServer() (*retryServer, *httptest.Server) {
+	s := &retryServer{statuses: map[string][]int{}, calls: map[string]int{}}
+	return s, httptest.NewServer(s)
+}
+
+func (s *retryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.calls[r.Method]++
+
+	var placed *swagger.Order
+	if r.Method == http.MethodPost && r.URL.Path == "/order" {
+		body, _ := ioutil.ReadAll(r.Body)
+		var form map[string]interface{}
+		json.Unmarshal(body, &form)
+		clOrdID, _ := form["clOrdID"].(string)
+		s.placed = append(s.placed, swagger.Order{OrderID: "o1", ClOrdID: clOrdID, Symbol: "XBTUSD", OrdStatus: OS_NEW})
+		placed = &s.placed[len(s.placed)-1]
+	}
+
+	if statuses := s.statuses[r.Method]; len(statuses) > 0 {
+		s.statuses[r.Method] = statuses[1:]
+		if statuses[0] != http.StatusBadGateway && placed != nil {
+			// only the 502 of the tests is answered after processing
+			s.placed = s.placed[:len(s.placed)-1]
+		}
+		w.WriteHeader(statuses[0])
+		w.Write([]byte(`{"error":{"message":"failed","name":"HTTPError"}}`))
+		return
+	}
+	switch {
+	case placed != nil:
+		json.NewEncoder(w).Encode(placed)
+	case r.Method == http.MethodGet && r.URL.Path == "/order":
+		orders := []swagger.Order{}
+		for _, o := range s.placed {
+			if o.ClOrdID != "" && strings.Contains(r.URL.Query().Get("filter"), o.ClOrdID) {
+				orders = append(orders, o)
+			}
+		}
+		json.NewEncoder(w).Encode(orders)
+	default:
+		w.Write([]byte(`[{"orderID":"o1","ordStatus":"Canceled"}]`))
+	}
+}
+
+func retryBitMEX(url string) *BitMEX {
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = url
+	b.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, BreakerThreshold: 3, BreakerCooldown: time.Minute})
+	// a 429 would drain the budget
+	b.SetRateLimitMode(RateLimitOff)
+	return b
+}
+
+func TestRetry_Overload(t *testing.T) {
+	s, srv := newRetryServer()
+	defer srv.Close()
+	b := retryBitMEX(srv.URL)
+
+	// an order without clOrdID is retried on 503: it was not processed
+	s.statuses[http.MethodPost] = []int{503, 429}
+	if _, err := b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, -1, "", 0, "XBTUSD", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if s.calls[http.MethodPost] != 3 || len(s.placed) != 1 {
+		t.Errorf("%v attempts, %v orders placed", s.calls[http.MethodPost], len(s.placed))
+	}
+
+	// attempts are limited
+	s.statuses[http.MethodGet] = []int{503, 503, 503}
+	if _, err := b.GetOrdersRaw("XBTUSD", ""); !IsOverloaded(err) {
+		t.Errorf("got %v, want overloaded", err)
+	}
+	if s.calls[http.MethodGet] != 3 {
+		t.Errorf("%v attempts", s.calls[http.MethodGet])
+	}
+}
+
+func TestRetry_Ambiguous(t *testing.T) {
+	s, srv := newRetryServer()
+	defer srv.Close()
+	b := retryBitMEX(srv.URL)
+
+	// placed but answered 502: found by clOrdID, not placed again
+	s.statuses[http.MethodPost] = []int{502}
+	order, err := b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, -1, "", 0, "XBTUSD", "cl-1", "")
+	if err != nil || order.ClOrdID != "cl-1" {
+		t.Fatalf("order %+v err %v", order, err)
+	}
+	if s.calls[http.MethodPost] != 1 || len(s.placed) != 1 {
+		t.Errorf("%v attempts, %v orders placed", s.calls[http.MethodPost], len(s.placed))
+	}
+
+	// not placed: sent again after the lookup
+	s.calls = map[string]int{}
+	s.statuses[http.MethodPost] = []int{504}
+	if _, err = b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 10, -1, "", 0, "XBTUSD", "cl-2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if s.calls[http.MethodPost] != 2 || s.calls[http.MethodGet] != 1 || len(s.placed) != 2 {
+		t.Errorf("%v attempts, %v lookups, %v orders placed", s.calls[http.MethodPost], s.calls[http.MethodGet], len(s.placed))
+	}
+
+	// without clOrdID the outcome can't be checked; the wrappers always set one
+	s.calls = map[string]int{}
+	s.statuses[http.MethodPost] = []int{502}
+	var apiErr *APIError
+	params := map[string]interface{}{"side": SIDE_BUY, "orderQty": int64(10), "price": "5000"}
+	if _, _, err = b.client.OrderApi.OrderNew(b.ctx, "XBTUSD", params); !errors.As(err, &apiErr) || apiErr.StatusCode != 502 {
+		t.Errorf("got %v, want 502", err)
+	}
+	if s.calls[http.MethodPost] != 1 {
+		t.Errorf("%v attempts", s.calls[http.MethodPost])
+	}
+
+	// cancels are idempotent
+	s.statuses[http.MethodDelete] = []int{500}
+	if _, err = b.CancelOrder("o1"); err != nil {
+		t.Fatal(err)
+	}
+	if s.calls[http.MethodDelete] != 2 {
+		t.Errorf("%v cancel attempts", s.calls[http.MethodDelete])
+	}
+}
+
+func TestRetry_CircuitBreaker(t *testing.T) {
+	s, srv := newRetryServer()
+	defer srv.Close()
+	b := retryBitMEX(srv.URL)
+
+	s.statuses[http.MethodGet] = []int{503, 503, 503}
+	b.GetOrdersRaw("XBTUSD", "")
+	if !b.CircuitOpen() {
+		t.Fatal("breaker not open")
+	}
+	if _, err := b.GetOrdersRaw("XBTUSD", ""); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("got %v, want ErrCircuitOpen", err)
+	}
+	if s.calls[http.MethodGet] != 3 {
+		t.Errorf("%v requests sent", s.calls[http.MethodGet])
+	}
+	// cancels go through
+	if _, err := b.CancelOrder("o1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry_AttemptTimeout(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	b, err := NewWithOptions(Options{RESTURL: srv.URL, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the backoff outlasts the timeout: it must not count
+	b.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: 200 * time.Millisecond})
+	b.SetRateLimitMode(RateLimitOff)
+
+	if _, err := b.GetOrdersRaw("XBTUSD", ""); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("%v attempts, want 2", n)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Jitter: 0.5}
+	for n, max := range []time.Duration{100, 200, 300, 300} {
+		max *= time.Millisecond
+		if d := p.delay(n + 1); d > max || d < max/2 {
+			t.Errorf("retry %v: delay %v out of [%v, %v]", n+1, d, max/2, max)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+	if d := retryAfter(&http.Response{StatusCode: 503, Header: header}); d != 3*time.Second {
+		t.Errorf("Retry-After %v", d)
+	}
+	header = http.Header{}
+	header.Set("X-Ratelimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+	if d := retryAfter(&http.Response{StatusCode: 429, Header: header}); d < 58*time.Second || d > time.Minute {
+		t.Errorf("X-Ratelimit-Reset %v", d)
+	}
+}
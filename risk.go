@@ -0,0 +1,294 @@
+package bitmex
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// BitmexRiskRejected is emitted with (RiskOrder, error) for every order a
+// RiskManager or the kill switch rejects
+const BitmexRiskRejected = "riskRejected"
+
+// RiskManager is consulted by every order-submitting method before the
+// order is sent. A non-nil error rejects the order.
+type RiskManager interface {
+	CheckOrder(order *RiskOrder) error
+}
+
+// RiskOrder is an order, or the result of an amendment, as seen by a
+// RiskManager
+type RiskOrder struct {
+	Symbol   string
+	Side     Side
+	OrdType  OrdType
+	OrderQty int64   // for amends: the new quantity, 0 = unchanged
+	Price    float64 // 0 = none
+	StopPx   float64 // 0 = none
+	ExecInst ExecInst
+
+	// Amend is set for amendments of OrderID. Symbol, Side, OrdType and
+	// ExecInst then come from the order stream and are empty for orders
+	// not seen there.
+	Amend   bool
+	OrderID string
+}
+
+// RiskRule names a check of RiskGuard
+type RiskRule string
+
+const (
+	RiskMaxOrderQty      RiskRule = "maxOrderQty"
+	RiskMaxOrderNotional RiskRule = "maxOrderNotional"
+	RiskMaxPosition      RiskRule = "maxPosition"
+	RiskMaxOpenOrders    RiskRule = "maxOpenOrders"
+	RiskPriceBand        RiskRule = "priceBand"
+	RiskOrderRate        RiskRule = "orderRate"
+	RiskLossLimit        RiskRule = "lossLimit"
+)
+
+// RiskError is returned for an order rejected by RiskGuard
+type RiskError struct {
+	Rule   RiskRule
+	Symbol string
+	Value  float64
+	Limit  float64
+}
+
+func (e *RiskError) Error() string {
+	return fmt.Sprintf("risk rejected: symbol=%v rule=%v value=%v limit=%v", e.Symbol, e.Rule, e.Value, e.Limit)
+}
+
+// RiskLimits are the limits of one symbol. Zero disables a limit.
+type RiskLimits struct {
+	MaxOrderQty      int64   // contracts per order
+	MaxOrderNotional float64 // value of one order in settlement units, XBt for XBTUSD
+	// MaxPosition bounds the absolute net position once the order and the
+	// open orders on the same side have filled
+	MaxPosition   int64
+	MaxOpenOrders int // working orders on the symbol, including the new one
+
+	// PriceBand is the largest relative distance of price and stopPx from
+	// the reference price, e.g. 0.05 for 5%
+	PriceBand     float64
+	BandReference ExecInst // ExecMarkPrice (default) or ExecLastPrice
+
+	// LossLimit is a loss, realised plus unrealised, in settlement units.
+	// Once reached only orders that reduce the position are accepted.
+	LossLimit int64
+}
+
+// RiskGuard is the built-in RiskManager. Positions and open orders come from
+// the position and order streams, reference prices from the instrument
+// registry; rules whose data is missing are skipped.
+type RiskGuard struct {
+	b *BitMEX
+
+	m                  sync.Mutex
+	limits             map[string]RiskLimits // key: symbol, "" = default
+	maxOrdersPerSecond int
+	sent               []time.Time // accepted orders within the last second
+}
+
+// NewRiskGuard returns a RiskGuard applying limits to every symbol. Install
+// it with SetRiskManager.
+func NewRiskGuard(b *BitMEX, limits RiskLimits) *RiskGuard {
+	return &RiskGuard{
+		b:      b,
+		limits: map[string]RiskLimits{"": limits},
+	}
+}
+
+// SetLimits replaces the limits of symbol. An empty symbol sets the default.
+func (g *RiskGuard) SetLimits(symbol string, limits RiskLimits) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.limits[symbol] = limits
+}
+
+// SetMaxOrdersPerSecond limits new orders and amendments over all symbols
+func (g *RiskGuard) SetMaxOrdersPerSecond(n int) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.maxOrdersPerSecond = n
+}
+
+// CheckOrder implements RiskManager
+func (g *RiskGuard) CheckOrder(o *RiskOrder) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	limits, ok := g.limits[o.Symbol]
+	if !ok {
+		limits = g.limits[""]
+	}
+	if err := g.check(o, limits); err != nil {
+		return err
+	}
+	return g.checkRate(o)
+}
+
+func (g *RiskGuard) check(o *RiskOrder, limits RiskLimits) error {
+	reject := func(rule RiskRule, value float64, limit float64) error {
+		return &RiskError{Rule: rule, Symbol: o.Symbol, Value: value, Limit: limit}
+	}
+
+	if limits.MaxOrderQty > 0 && o.OrderQty > limits.MaxOrderQty {
+		return reject(RiskMaxOrderQty, float64(o.OrderQty), float64(limits.MaxOrderQty))
+	}
+
+	spec, hasSpec := g.b.instruments.Get(o.Symbol)
+	reference := spec.MarkPrice
+	if limits.BandReference == ExecLastPrice || reference == 0 {
+		reference = spec.LastPrice
+	}
+
+	if hasSpec && limits.MaxOrderNotional > 0 {
+		price := o.Price
+		if price == 0 {
+			price = reference
+		}
+		if n := spec.Notional(o.OrderQty, price); n > limits.MaxOrderNotional {
+			return reject(RiskMaxOrderNotional, n, limits.MaxOrderNotional)
+		}
+	}
+
+	if limits.PriceBand > 0 && reference > 0 {
+		for _, price := range []float64{o.Price, o.StopPx} {
+			if price <= 0 {
+				continue
+			}
+			if d := math.Abs(price/reference - 1); d > limits.PriceBand {
+				return reject(RiskPriceBand, price, reference)
+			}
+		}
+	}
+
+	if o.Amend {
+		// amendments don't add orders; a larger quantity is checked above
+		return nil
+	}
+
+	reducing := o.ExecInst&(ExecReduceOnly|ExecClose) != 0
+	var position int64
+	if p, ok := g.b.localPosition(o.Symbol); ok {
+		position = p.CurrentQty.V
+		loss := -(p.RealisedPnl.V + p.UnrealisedPnl.V)
+		if limits.LossLimit > 0 && loss >= limits.LossLimit && !reducing && !reduces(position, o.Side, o.OrderQty) {
+			return reject(RiskLossLimit, float64(loss), float64(limits.LossLimit))
+		}
+	}
+
+	open := g.b.openOrders(o.Symbol)
+	if limits.MaxOpenOrders > 0 && len(open)+1 > limits.MaxOpenOrders {
+		return reject(RiskMaxOpenOrders, float64(len(open)+1), float64(limits.MaxOpenOrders))
+	}
+
+	if limits.MaxPosition > 0 && !reducing {
+		projected := position + signedQty(o.Side, o.OrderQty)
+		for _, v := range open {
+			if Side(v.Side) == o.Side {
+				projected += signedQty(o.Side, v.LeavesQty)
+			}
+		}
+		if abs64(projected) > limits.MaxPosition {
+			return reject(RiskMaxPosition, float64(abs64(projected)), float64(limits.MaxPosition))
+		}
+	}
+	return nil
+}
+
+// checkRate counts o against the orders per second once every other rule
+// has passed
+func (g *RiskGuard) checkRate(o *RiskOrder) error {
+	now := time.Now()
+	i := 0
+	for i < len(g.sent) && now.Sub(g.sent[i]) >= time.Second {
+		i++
+	}
+	g.sent = g.sent[i:]
+	if g.maxOrdersPerSecond > 0 && len(g.sent) >= g.maxOrdersPerSecond {
+		return &RiskError{Rule: RiskOrderRate, Symbol: o.Symbol, Value: float64(len(g.sent) + 1), Limit: float64(g.maxOrdersPerSecond)}
+	}
+	g.sent = append(g.sent, now)
+	return nil
+}
+
+// reduces reports whether an order of qty on side only reduces position
+func reduces(position int64, side Side, qty int64) bool {
+	if qty <= 0 {
+		return false
+	}
+	return (position > 0 && side == SideSell && qty <= position) ||
+		(position < 0 && side == SideBuy && qty <= -position)
+}
+
+func signedQty(side Side, qty int64) int64 {
+	if side == SideSell {
+		return -qty
+	}
+	return qty
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// SetRiskManager installs rm in front of every order-submitting method.
+// nil removes it.
+func (b *BitMEX) SetRiskManager(rm RiskManager) {
+	b.riskMutex.Lock()
+	defer b.riskMutex.Unlock()
+	b.risk = rm
+}
+
+// checkRisk runs the kill switch and the risk manager, and emits
+// BitmexRiskRejected on rejection
+func (b *BitMEX) checkRisk(o *RiskOrder) error {
+	b.riskMutex.RLock()
+	rm := b.risk
+	b.riskMutex.RUnlock()
+
+	var err error
+	switch {
+	case b.KillSwitchEngaged() && !b.reducesPosition(o):
+		err = ErrKillSwitch
+	case rm != nil:
+		err = rm.CheckOrder(o)
+	}
+	if err != nil {
+		b.emitter.Emit(BitmexRiskRejected, *o, err)
+	}
+	return err
+}
+
+// reducesPosition reports whether o can only reduce the position of its
+// symbol: a reduce-only or close order, or one within the position on the
+// opposite side
+func (b *BitMEX) reducesPosition(o *RiskOrder) bool {
+	if o.ExecInst&(ExecReduceOnly|ExecClose) != 0 {
+		return true
+	}
+	p, ok := b.localPosition(o.Symbol)
+	return ok && reduces(p.CurrentQty.V, o.Side, o.OrderQty)
+}
+
+// checkAmendRisk runs the risk manager on an amendment of orderID. The new
+// quantity is orderQty, or cumQty+leavesQty when only leavesQty is given.
+func (b *BitMEX) checkAmendRisk(orderID string, orderQty int64, leavesQty int64, price Price, stopPx Price) error {
+	o := RiskOrder{Amend: true, OrderID: orderID, OrderQty: orderQty, Price: price.Float64(), StopPx: stopPx.Float64()}
+	if order, ok := b.localOrder(orderID); ok {
+		o.Symbol = order.Symbol
+		o.Side = Side(order.Side)
+		o.OrdType = OrdType(order.OrdType)
+		o.ExecInst = parseExecInst(order.ExecInst)
+		if orderQty == 0 && leavesQty != 0 {
+			o.OrderQty = order.CumQty + leavesQty
+		}
+	}
+	return b.checkRisk(&o)
+}
@@ -0,0 +1,108 @@
+package bitmex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func newRiskTestBitMEX() *BitMEX {
+	b := New(HostTestnet, "key", "secret")
+	b.instruments.update([]*swagger.Instrument{{
+		Symbol: "XBTUSD", IsInverse: true, Multiplier: -100000000, TickSize: 0.5, LotSize: 1,
+		MarkPrice: 5000, LastPrice: 5010,
+	}}, true)
+	b.updatePositions(&Response{Action: bitmexActionInitialData,
+		raw: []byte(`[{"account":1,"symbol":"XBTUSD","currency":"XBt","currentQty":100,"realisedPnl":-1000,"unrealisedPnl":-500}]`)})
+	b.processOrder(&Response{Action: bitmexActionInitialData,
+		raw: []byte(`[{"orderID":"o1","symbol":"XBTUSD","side":"Buy","ordType":"Limit","ordStatus":"New","orderQty":50,"leavesQty":50,"price":4900}]`)})
+	return b
+}
+
+func riskRule(err error) RiskRule {
+	var e *RiskError
+	if errors.As(err, &e) {
+		return e.Rule
+	}
+	return ""
+}
+
+func TestRiskGuard(t *testing.T) {
+	b := newRiskTestBitMEX()
+	buy := func(qty int64, price float64) *RiskOrder {
+		return &RiskOrder{Symbol: "XBTUSD", Side: SideBuy, OrdType: OrdTypeLimit, OrderQty: qty, Price: price}
+	}
+
+	tests := []struct {
+		limits RiskLimits
+		order  *RiskOrder
+		rule   RiskRule
+	}{
+		{RiskLimits{MaxOrderQty: 100}, buy(100, 5000), ""},
+		{RiskLimits{MaxOrderQty: 100}, buy(101, 5000), RiskMaxOrderQty},
+		// 1000 USD at 5000 = 0.2 XBT
+		{RiskLimits{MaxOrderNotional: 20000000}, buy(1000, 5000), ""},
+		{RiskLimits{MaxOrderNotional: 20000000}, buy(1000, 4000), RiskMaxOrderNotional},
+		{RiskLimits{MaxOrderNotional: 20000000}, &RiskOrder{Symbol: "XBTUSD", Side: SideBuy, OrdType: OrdTypeMarket, OrderQty: 1001}, RiskMaxOrderNotional},
+		{RiskLimits{PriceBand: 0.05}, buy(1, 4800), ""},
+		{RiskLimits{PriceBand: 0.05}, buy(1, 4700), RiskPriceBand},
+		{RiskLimits{PriceBand: 0.05}, &RiskOrder{Symbol: "XBTUSD", Side: SideSell, OrdType: OrdTypeStop, OrderQty: 1, StopPx: 5300}, RiskPriceBand},
+		{RiskLimits{MaxOpenOrders: 2}, buy(1, 5000), ""},
+		{RiskLimits{MaxOpenOrders: 1}, buy(1, 5000), RiskMaxOpenOrders},
+		// position 100 + open buy 50 + 50
+		{RiskLimits{MaxPosition: 200}, buy(50, 5000), ""},
+		{RiskLimits{MaxPosition: 200}, buy(51, 5000), RiskMaxPosition},
+		{RiskLimits{MaxPosition: 200}, &RiskOrder{Symbol: "XBTUSD", Side: SideSell, OrdType: OrdTypeLimit, OrderQty: 250, Price: 5000}, ""},
+		// loss 1500
+		{RiskLimits{LossLimit: 2000}, buy(10, 5000), ""},
+		{RiskLimits{LossLimit: 1500}, buy(10, 5000), RiskLossLimit},
+		{RiskLimits{LossLimit: 1500}, &RiskOrder{Symbol: "XBTUSD", Side: SideSell, OrdType: OrdTypeLimit, OrderQty: 100, Price: 5000}, ""},
+		{RiskLimits{LossLimit: 1500}, &RiskOrder{Symbol: "XBTUSD", Side: SideSell, OrdType: OrdTypeLimit, OrderQty: 101, Price: 5000}, RiskLossLimit},
+		{RiskLimits{LossLimit: 1500}, &RiskOrder{Symbol: "XBTUSD", Side: SideSell, OrdType: OrdTypeMarket, ExecInst: ExecClose}, ""},
+	}
+	for i, test := range tests {
+		g := NewRiskGuard(b, test.limits)
+		if rule := riskRule(g.CheckOrder(test.order)); rule != test.rule {
+			t.Errorf("%v: rule %q, want %q", i, rule, test.rule)
+		}
+	}
+}
+
+func TestRiskGuard_Rate(t *testing.T) {
+	b := newRiskTestBitMEX()
+	g := NewRiskGuard(b, RiskLimits{MaxOrderQty: 10})
+	g.SetMaxOrdersPerSecond(2)
+	o := &RiskOrder{Symbol: "XBTUSD", Side: SideBuy, OrdType: OrdTypeLimit, OrderQty: 1, Price: 5000}
+	g.CheckOrder(&RiskOrder{Symbol: "XBTUSD", OrderQty: 11}) // rejected, not counted
+	for i := 0; i < 2; i++ {
+		if err := g.CheckOrder(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rule := riskRule(g.CheckOrder(o)); rule != RiskOrderRate {
+		t.Errorf("rule %q", rule)
+	}
+}
+
+func TestBitMEX_RiskManager(t *testing.T) {
+	b := newRiskTestBitMEX()
+	b.SetRiskManager(NewRiskGuard(b, RiskLimits{MaxOrderQty: 100}))
+
+	var audited []RiskOrder
+	b.On(BitmexRiskRejected, func(o RiskOrder, err error) {
+		audited = append(audited, o)
+	})
+
+	_, err := b.PlaceOrder2(SIDE_BUY, ORD_TYPE_LIMIT, Price{}, MustPrice("5000"), 1000, -1, "", 0, "XBTUSD", "", "")
+	if riskRule(err) != RiskMaxOrderQty {
+		t.Errorf("PlaceOrder2: %v", err)
+	}
+	_, err = b.AmendOrder2("o1", "", "", 0, 0, 0, 200, Price{}, Price{}, Price{}, "")
+	if riskRule(err) != RiskMaxOrderQty {
+		t.Errorf("AmendOrder2: %v", err)
+	}
+	if len(audited) != 2 || audited[0].OrderQty != 1000 || !audited[1].Amend || audited[1].OrderQty != 200 {
+		t.Errorf("audit %+v", audited)
+	}
+}
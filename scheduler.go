@@ -0,0 +1,215 @@
+package bitmex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Priority orders the REST requests waiting for rate limit budget. Requests
+// of a higher priority are sent first, requests of one priority in order.
+type Priority int
+
+const (
+	PriorityRead   Priority = iota // GET requests
+	PriorityNew                    // new orders and other writes
+	PriorityAmend                  // order amendments
+	PriorityCancel                 // cancels and kill switch actions
+)
+
+var priorityNames = []string{"read", "new", "amend", "cancel"}
+
+func (p Priority) String() string {
+	if p < 0 || int(p) >= len(priorityNames) {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// ErrAmendSuperseded is returned for an amendment dropped from the queue for
+// a later amendment of the same order
+var ErrAmendSuperseded = errors.New("amend superseded by a later amend of the same order")
+
+// SchedulerReadReserve is the private budget, in requests, reads leave to
+// orders: a read waits while less than this would be left after it
+var SchedulerReadReserve = 10.0
+
+// SchedulerStats are the metrics of one priority
+type SchedulerStats struct {
+	Depth     int   // requests waiting now
+	Sent      int64 // requests let through
+	Coalesced int64 // amendments dropped for a later one
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// rateRequest is a request waiting for budget
+type rateRequest struct {
+	public   bool
+	cost     float64
+	priority Priority
+	orderID  string // amendments of one order coalesce
+	seq      uint64
+	queued   time.Time
+	ready    chan struct{}
+	err      error
+}
+
+type priorityKey struct{}
+
+// withPriority sets the priority of the requests made with ctx
+func withPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// newRateRequest classifies req
+func newRateRequest(req *http.Request) *rateRequest {
+	r := &rateRequest{public: req.Header.Get("api-key") == "", cost: 1}
+	if cost, ok := req.Context().Value(requestCostKey{}).(float64); ok {
+		r.cost = cost
+	}
+
+	switch {
+	case req.Method == http.MethodDelete, strings.HasSuffix(req.URL.Path, "/order/cancelAllAfter"):
+		r.priority = PriorityCancel
+	case req.Method == http.MethodPut:
+		r.priority = PriorityAmend
+	case req.Method == http.MethodGet:
+		r.priority = PriorityRead
+	default:
+		r.priority = PriorityNew
+	}
+	if p, ok := req.Context().Value(priorityKey{}).(Priority); ok {
+		r.priority = p
+	}
+
+	if req.Method == http.MethodPut && strings.HasSuffix(req.URL.Path, "/order") && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			var form struct {
+				OrderID     string `json:"orderID"`
+				OrigClOrdID string `json:"origClOrdID"`
+			}
+			if json.Unmarshal(data, &form) == nil {
+				r.orderID = form.OrderID
+				if r.orderID == "" && form.OrigClOrdID != "" {
+					r.orderID = "cl:" + form.OrigClOrdID
+				}
+			}
+		}
+	}
+	return r
+}
+
+// need returns how long r has to wait for budget. Caller holds l.m.
+func (l *rateLimiter) need(k *rateBucket, r *rateRequest, now time.Time) time.Duration {
+	if l.mode == RateLimitOff {
+		return 0
+	}
+	cost := r.cost
+	if r.priority == PriorityRead && !r.public {
+		cost += SchedulerReadReserve
+	}
+	return k.wait(cost, now)
+}
+
+// enqueue queues r, dropping a queued amendment of the same order. Caller
+// holds l.m.
+func (l *rateLimiter) enqueue(k *rateBucket, r *rateRequest, now time.Time) {
+	if r.priority == PriorityAmend && r.orderID != "" {
+		for i, q := range k.queue {
+			if q.priority == PriorityAmend && q.orderID == r.orderID {
+				k.queue = append(k.queue[:i], k.queue[i+1:]...)
+				q.err = ErrAmendSuperseded
+				close(q.ready)
+				l.stats[q.priority].Coalesced++
+				break
+			}
+		}
+	}
+	l.seq++
+	r.seq = l.seq
+	r.queued = now
+	r.ready = make(chan struct{})
+	i := sort.Search(len(k.queue), func(i int) bool {
+		q := k.queue[i]
+		return q.priority < r.priority || (q.priority == r.priority && q.seq > r.seq)
+	})
+	k.queue = append(k.queue, nil)
+	copy(k.queue[i+1:], k.queue[i:])
+	k.queue[i] = r
+}
+
+// dequeue removes r from the queue if it is still there. Caller holds l.m.
+func (l *rateLimiter) dequeue(k *rateBucket, r *rateRequest) bool {
+	for i, q := range k.queue {
+		if q == r {
+			k.queue = append(k.queue[:i], k.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// schedule lets queued requests through while the budget allows and sets a
+// timer for the next one. Caller holds l.m.
+func (l *rateLimiter) schedule(k *rateBucket) {
+	now := time.Now()
+	for len(k.queue) > 0 {
+		r := k.queue[0]
+		if wait := l.need(k, r, now); wait > 0 {
+			if k.timer == nil {
+				k.timer = time.AfterFunc(wait, func() {
+					l.m.Lock()
+					defer l.m.Unlock()
+					l.schedule(k)
+				})
+			} else {
+				k.timer.Reset(wait)
+			}
+			return
+		}
+		k.queue = k.queue[1:]
+		l.grant(k, r, now.Sub(r.queued))
+		close(r.ready)
+	}
+}
+
+// grant takes the cost of r from the budget. Caller holds l.m.
+func (l *rateLimiter) grant(k *rateBucket, r *rateRequest, wait time.Duration) {
+	if l.mode == RateLimitOff {
+		return
+	}
+	k.tokens -= r.cost
+	s := &l.stats[r.priority]
+	s.Sent++
+	s.TotalWait += wait
+	if wait > s.MaxWait {
+		s.MaxWait = wait
+	}
+}
+
+// SchedulerStats returns the metrics of the request scheduler by priority
+func (b *BitMEX) SchedulerStats() map[Priority]SchedulerStats {
+	l := b.limiter
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	stats := make(map[Priority]SchedulerStats, len(l.stats))
+	for p, s := range l.stats {
+		stats[Priority(p)] = s
+	}
+	for _, k := range []*rateBucket{&l.public, &l.private} {
+		for _, r := range k.queue {
+			s := stats[r.priority]
+			s.Depth++
+			stats[r.priority] = s
+		}
+	}
+	return stats
+}
@@ -0,0 +1,159 @@
+package bitmex
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queueRequest starts acquiring r and returns once it waits in the queue
+func queueRequest(t *testing.T, l *rateLimiter, ctx context.Context, r *rateRequest, done func(error)) {
+	l.m.Lock()
+	depth := len(l.private.queue)
+	l.m.Unlock()
+	go func() {
+		_, err := l.acquire(ctx, r)
+		done(err)
+	}()
+	for i := 0; i < 100; i++ {
+		l.m.Lock()
+		n := len(l.private.queue)
+		l.m.Unlock()
+		if n > depth {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%v request not queued", r.priority)
+}
+
+func emptyLimiter() *rateLimiter {
+	l := newRateLimiter()
+	// one request per 20ms, none left
+	l.private.limit = 3000
+	l.private.tokens = 0
+	l.private.last = time.Now()
+	return l
+}
+
+func TestScheduler_Priority(t *testing.T) {
+	reserve := SchedulerReadReserve
+	SchedulerReadReserve = 0
+	defer func() { SchedulerReadReserve = reserve }()
+
+	l := emptyLimiter()
+	var m sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	for _, p := range []Priority{PriorityRead, PriorityNew, PriorityNew, PriorityAmend, PriorityCancel} {
+		p := p
+		wg.Add(1)
+		queueRequest(t, l, context.Background(), &rateRequest{cost: 1, priority: p}, func(err error) {
+			defer wg.Done()
+			if err != nil {
+				t.Error(err)
+			}
+			m.Lock()
+			order = append(order, p)
+			m.Unlock()
+		})
+	}
+	wg.Wait()
+
+	want := []Priority{PriorityCancel, PriorityAmend, PriorityNew, PriorityNew, PriorityRead}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("sent in order %v, want %v", order, want)
+		}
+	}
+	stats := (&BitMEX{limiter: l}).SchedulerStats()
+	if s := stats[PriorityNew]; s.Sent != 2 || s.Depth != 0 || s.MaxWait <= 0 {
+		t.Errorf("new order stats %+v", s)
+	}
+}
+
+func TestScheduler_Coalesce(t *testing.T) {
+	l := emptyLimiter()
+	errs := make(chan error, 3)
+	done := func(err error) { errs <- err }
+	queueRequest(t, l, context.Background(), &rateRequest{cost: 1, priority: PriorityAmend, orderID: "a"}, done)
+	queueRequest(t, l, context.Background(), &rateRequest{cost: 1, priority: PriorityAmend, orderID: "b"}, done)
+
+	// the first amend of a is dropped when the second is queued
+	l.m.Lock()
+	l.enqueue(&l.private, &rateRequest{cost: 1, priority: PriorityAmend, orderID: "a"}, time.Now())
+	l.m.Unlock()
+	if err := <-errs; err != ErrAmendSuperseded {
+		t.Fatalf("got %v, want ErrAmendSuperseded", err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if s := (&BitMEX{limiter: l}).SchedulerStats()[PriorityAmend]; s.Coalesced != 1 || s.Sent != 1 {
+		t.Errorf("amend stats %+v", s)
+	}
+}
+
+func TestScheduler_ReadReserve(t *testing.T) {
+	l := newRateLimiter()
+	l.private.tokens = SchedulerReadReserve
+	r := &rateRequest{cost: 1, priority: PriorityRead}
+	if wait := l.need(&l.private, r, time.Now()); wait <= 0 {
+		t.Error("read sent into the reserve")
+	}
+	r.priority = PriorityNew
+	if wait := l.need(&l.private, r, time.Now()); wait != 0 {
+		t.Errorf("new order waits %v", wait)
+	}
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	l := emptyLimiter()
+	l.private.limit = 1 // a minute per request
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	queueRequest(t, l, ctx, &rateRequest{cost: 1, priority: PriorityNew}, func(err error) { errs <- err })
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Fatalf("got %v", err)
+	}
+	if s := (&BitMEX{limiter: l}).SchedulerStats()[PriorityNew]; s.Depth != 0 {
+		t.Errorf("depth %v after cancel", s.Depth)
+	}
+}
+
+func TestScheduler_Classify(t *testing.T) {
+	for _, v := range []struct {
+		method, path, body string
+		priority           Priority
+		orderID            string
+	}{
+		{http.MethodGet, "/api/v1/order", "", PriorityRead, ""},
+		{http.MethodPost, "/api/v1/order", `{"symbol":"XBTUSD"}`, PriorityNew, ""},
+		{http.MethodPut, "/api/v1/order", `{"orderID":"a","price":1}`, PriorityAmend, "a"},
+		{http.MethodPut, "/api/v1/order", `{"origClOrdID":"x"}`, PriorityAmend, "cl:x"},
+		{http.MethodPut, "/api/v1/order/bulk", `{"orders":[]}`, PriorityAmend, ""},
+		{http.MethodDelete, "/api/v1/order", `{"orderID":"a"}`, PriorityCancel, ""},
+		{http.MethodPost, "/api/v1/order/cancelAllAfter", `{"timeout":0}`, PriorityCancel, ""},
+	} {
+		req, _ := http.NewRequest(v.method, "https://testnet.bitmex.com"+v.path, bytes.NewBufferString(v.body))
+		req.Header.Set("api-key", "key")
+		r := newRateRequest(req)
+		if r.priority != v.priority || r.orderID != v.orderID || r.public {
+			t.Errorf("%v %v: priority %v orderID %q", v.method, v.path, r.priority, r.orderID)
+		}
+		if body, _ := ioutil.ReadAll(req.Body); string(body) != v.body {
+			t.Errorf("%v %v: body consumed", v.method, v.path)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://testnet.bitmex.com/api/v1/order/closePosition", nil)
+	req = req.WithContext(withPriority(context.Background(), PriorityCancel))
+	if r := newRateRequest(req); r.priority != PriorityCancel || !r.public {
+		t.Errorf("priority %v from context", r.priority)
+	}
+}
@@ -0,0 +1,147 @@
+package bitmex
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// Signer signs REST requests and the websocket authentication
+type Signer = swagger.Signer
+
+// HMACSigner signs with an API secret held in memory. The secret given to
+// New is used this way unless SetSigner is called.
+type HMACSigner = swagger.HMACSigner
+
+// DefaultSignerTimeout bounds a request to a signing daemon when
+// UnixSigner.Timeout is not set
+var DefaultSignerTimeout = time.Second
+
+// SetSigner signs requests with signer instead of the secret given to New,
+// which may then be empty
+func (b *BitMEX) SetSigner(signer Signer) {
+	b.signerMutex.Lock()
+	defer b.signerMutex.Unlock()
+	b.customSigner = signer
+}
+
+// signer returns the signer of the requests
+func (b *BitMEX) signer() Signer {
+	b.signerMutex.RLock()
+	defer b.signerMutex.RUnlock()
+	if b.customSigner != nil {
+		return b.customSigner
+	}
+	return HMACSigner(b.Secret)
+}
+
+// canSign reports whether requests can be signed
+func (b *BitMEX) canSign() bool {
+	b.signerMutex.RLock()
+	defer b.signerMutex.RUnlock()
+	return b.customSigner != nil || b.Secret != ""
+}
+
+// currentSigner is the signer of the swagger client: it signs with the
+// signer of b at the time of each request
+type currentSigner struct {
+	b *BitMEX
+}
+
+func (s currentSigner) Sign(method, path, query, expires, body string) (string, error) {
+	return s.b.signer().Sign(method, path, query, expires, body)
+}
+
+// signRequest is a request to a signing daemon, one JSON object per line
+type signRequest struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Query   string `json:"query"`
+	Expires string `json:"expires"`
+	Body    string `json:"body"`
+}
+
+// signResponse is the answer of a signing daemon, one JSON object per line
+type signResponse struct {
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// UnixSigner asks a signing daemon listening on a Unix socket for the
+// signatures, so that the API secret stays out of the process. Each request
+// is a line of JSON {"method","path","query","expires","body"} answered with
+// a line of JSON {"signature"} or {"error"}. ServeSigner implements the
+// daemon side.
+type UnixSigner struct {
+	Path    string        // socket path
+	Timeout time.Duration // per request, DefaultSignerTimeout when 0
+}
+
+// NewUnixSigner returns a signer for the daemon listening on path
+func NewUnixSigner(path string) *UnixSigner {
+	return &UnixSigner{Path: path}
+}
+
+func (s *UnixSigner) Sign(method, path, query, expires, body string) (string, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSignerTimeout
+	}
+	conn, err := net.DialTimeout("unix", s.Path, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	err = json.NewEncoder(conn).Encode(signRequest{method, path, query, expires, body})
+	if err != nil {
+		return "", err
+	}
+	var resp signResponse
+	if err = json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", errors.New("signer: " + resp.Error)
+	}
+	return resp.Signature, nil
+}
+
+// ServeSigner answers the UnixSigner requests of the connections accepted
+// on l with signer, until l is closed
+func ServeSigner(l net.Listener, signer Signer) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go serveSignerConn(conn, signer)
+	}
+}
+
+func serveSignerConn(conn net.Conn, signer Signer) {
+	defer conn.Close()
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	for {
+		var req signRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		var resp signResponse
+		signature, err := signer.Sign(req.Method, req.Path, req.Query, req.Expires, req.Body)
+		if err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Signature = signature
+		}
+		if err = enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package bitmex
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+type failingSigner struct{}
+
+func (failingSigner) Sign(method, path, query, expires, body string) (string, error) {
+	return "", errors.New("locked")
+}
+
+func TestUnixSigner(t *testing.T) {
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "signer.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go ServeSigner(l, HMACSigner("secret"))
+
+	var m sync.Mutex
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		header = r.Header.Clone()
+		m.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// the secret lives in the daemon only
+	b := New(HostTestnet, "key", "")
+	b.cfg.BasePath = srv.URL
+	b.SetSigner(NewUnixSigner(l.Addr().String()))
+
+	if _, err := b.GetWallet(); err != nil {
+		t.Fatal(err)
+	}
+	m.Lock()
+	want := swagger.Signature("secret", "GET", "", "currency=", header.Get("api-expires"), "")
+	if got := header.Get("api-signature"); got != want {
+		t.Errorf("api-signature %v, want %v", got, want)
+	}
+	m.Unlock()
+
+	msg, err := b.getAuthMessage("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = swagger.CalSignature("secret", "GET/realtime"+strconv.FormatInt(msg.Args[1].(int64), 10))
+	if msg.Args[2] != want {
+		t.Errorf("authKey signature %v, want %v", msg.Args[2], want)
+	}
+
+	// signing errors fail the request before it is sent
+	b.SetSigner(failingSigner{})
+	if _, err := b.GetWallet(); err == nil || err.Error() != "locked" {
+		t.Errorf("got %v, want the signer error", err)
+	}
+	if _, err := NewUnixSigner(l.Addr().String()+".missing").Sign("GET", "/api/v1", "", "1", ""); err == nil {
+		t.Error("signed without a daemon")
+	}
+}
+
+func TestSetSigner_Concurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.SetRateLimitMode(RateLimitOff)
+
+	// run with -race: requests read the signer while it is replaced
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			b.SetSigner(HMACSigner("secret"))
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if _, err := b.GetWallet(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
@@ -16,7 +16,7 @@ import (
 
 // Public Announcements
 type Announcement struct {
-	Id float32 `json:"id"`
+	Id int64 `json:"id"`
 
 	Link string `json:"link,omitempty"`
 
@@ -27,10 +27,11 @@ type AnnouncementApiService service
 
 /* AnnouncementApiService Get site announcements.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "columns" (string) Array of column names to fetch. If omitted, will return all columns.
 @return []Announcement*/
-func (a *AnnouncementApiService) AnnouncementGet(localVarOptionals map[string]interface{}) ([]Announcement, *http.Response, error) {
+func (a *AnnouncementApiService) AnnouncementGet(ctx context.Context, localVarOptionals map[string]interface{}) ([]Announcement, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -76,7 +77,7 @@ func (a *AnnouncementApiService) AnnouncementGet(localVarOptionals map[string]in
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -87,7 +88,7 @@ func (a *AnnouncementApiService) AnnouncementGet(localVarOptionals map[string]in
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -186,7 +187,7 @@ func (a *AnnouncementApiService) AnnouncementGetUrgent(ctx context.Context) ([]A
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -335,6 +335,11 @@ func (c *APIClient) prepareRequest(
 			localVarRequest.Header.Add("Authorization", "Bearer "+auth)
 		}
 
+		// Trace ID
+		if id, ok := ctx.Value(ContextTraceID).(string); ok && id != "" {
+			localVarRequest.Header.Set(TraceIDHeader, id)
+		}
+
 		// before setting auth header, remove Api-Nonce, Api-Key, Api-Signature
 		localVarRequest.Header.Del("Api-Nonce")
 		localVarRequest.Header.Del("Api-Key")
@@ -343,9 +348,12 @@ func (c *APIClient) prepareRequest(
 		// APIKey Authentication
 		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
 			if postBody != nil {
-				SetAuthHeader(localVarRequest, auth, c.cfg, method, path, postBody.(string), queryParams)
+				err = SetAuthHeader(localVarRequest, auth, c.cfg, method, path, postBody.(string), queryParams)
 			} else {
-				SetAuthHeader(localVarRequest, auth, c.cfg, method, path, "", queryParams)
+				err = SetAuthHeader(localVarRequest, auth, c.cfg, method, path, "", queryParams)
+			}
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -354,6 +362,9 @@ func (c *APIClient) prepareRequest(
 		localVarRequest.Header.Add(header, value)
 	}
 
+	if ctx != nil {
+		localVarRequest = localVarRequest.WithContext(ctx)
+	}
 	return localVarRequest, nil
 }
 
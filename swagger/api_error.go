@@ -0,0 +1,104 @@
+package swagger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors matched by APIError with errors.Is
+var (
+	ErrOverloaded          = errors.New("system overloaded")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInvalidOrdStatus    = errors.New("invalid ordStatus")
+	ErrDuplicateClOrdID    = errors.New("duplicate clOrdID")
+	ErrAuth                = errors.New("authentication failed")
+)
+
+// APIError is returned for a response with a status of 300 or above
+type APIError struct {
+	StatusCode int
+	Status     string
+	Name       string // error.name of the body, e.g. HTTPError or ValidationError
+	Message    string // error.message of the body, or the body when it isn't JSON
+	Method     string
+	Path       string
+	TraceID    string // ContextTraceID of the request
+
+	// X-Ratelimit-* and Retry-After headers, zero when missing
+	RateLimitLimit     int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+	RetryAfter         time.Duration
+}
+
+func (e *APIError) Error() string {
+	s := e.Status
+	if e.Message != "" {
+		s += ": " + e.Message
+	}
+	if e.Path != "" {
+		s += fmt.Sprintf(" (%v %v)", e.Method, e.Path)
+	}
+	if e.TraceID != "" {
+		s += " trace=" + e.TraceID
+	}
+	return s
+}
+
+// Is reports whether e is of the kind of one of the errors above
+func (e *APIError) Is(target error) bool {
+	message := strings.ToLower(e.Message)
+	switch target {
+	case ErrOverloaded:
+		return e.StatusCode == http.StatusServiceUnavailable
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrInsufficientBalance:
+		return strings.Contains(message, "insufficient available balance") ||
+			strings.Contains(message, "insufficient balance")
+	case ErrInvalidOrdStatus:
+		return strings.Contains(message, "invalid ordstatus")
+	case ErrDuplicateClOrdID:
+		return strings.Contains(message, "duplicate clordid")
+	case ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// newAPIError reads the error of a response; the caller closes the body
+func newAPIError(response *http.Response) error {
+	e := &APIError{StatusCode: response.StatusCode, Status: response.Status}
+	if response.Request != nil {
+		e.Method = response.Request.Method
+		e.Path = response.Request.URL.Path
+		e.TraceID = response.Request.Header.Get(TraceIDHeader)
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+	var model ModelError
+	if json.Unmarshal(body, &model) == nil && model.Error_ != nil {
+		e.Name = model.Error_.Name
+		e.Message = model.Error_.Message
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+
+	header := response.Header
+	e.RateLimitLimit, _ = strconv.Atoi(header.Get("X-Ratelimit-Limit"))
+	e.RateLimitRemaining, _ = strconv.Atoi(header.Get("X-Ratelimit-Remaining"))
+	if v, err := strconv.ParseInt(header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+		e.RateLimitReset = time.Unix(v, 0)
+	}
+	if v, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		e.RetryAfter = time.Duration(v) * time.Second
+	}
+	return e
+}
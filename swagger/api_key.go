@@ -22,7 +22,7 @@ type ApiKey struct {
 
 	Name string `json:"name"`
 
-	Nonce float32 `json:"nonce"`
+	Nonce int64 `json:"nonce"`
 
 	Cidr string `json:"cidr,omitempty"`
 
@@ -30,7 +30,7 @@ type ApiKey struct {
 
 	Enabled bool `json:"enabled,omitempty"`
 
-	UserId float32 `json:"userId"`
+	UserId int64 `json:"userId"`
 
 	Created time.Time `json:"created,omitempty"`
 }
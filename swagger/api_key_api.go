@@ -116,7 +116,7 @@ func (a *APIKeyApiService) APIKeyDisable(ctx context.Context, apiKeyID string) (
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -217,7 +217,7 @@ func (a *APIKeyApiService) APIKeyEnable(ctx context.Context, apiKeyID string) (A
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -325,7 +325,7 @@ func (a *APIKeyApiService) APIKeyGet(ctx context.Context, localVarOptionals map[
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -462,7 +462,7 @@ func (a *APIKeyApiService) APIKeyNew(ctx context.Context, localVarOptionals map[
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -563,7 +563,7 @@ func (a *APIKeyApiService) APIKeyRemove(ctx context.Context, apiKeyID string) (I
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
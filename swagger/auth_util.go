@@ -9,19 +9,40 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
-	"time"
 )
 
+// Signer signs BitMEX requests: the websocket authentication is signed as
+// a GET of /realtime without query and body
+type Signer interface {
+	Sign(method, path, query, expires, body string) (string, error)
+}
+
+// HMACSigner signs with an API secret held in memory
+type HMACSigner string
+
+func (secret HMACSigner) Sign(method, path, query, expires, body string) (string, error) {
+	return Signature(string(secret), method, path, query, expires, body), nil
+}
+
+// SetAuthHeader signs request with c.Signer, or with the secret of apiKey
+// when c.Signer is nil
 func SetAuthHeader(request *http.Request, apiKey APIKey, c *Configuration, httpMethod, path, postBody string,
-	queryParams url.Values) {
-	var expires = strconv.FormatInt(time.Now().Unix()+c.ExpireTime, 10)
+	queryParams url.Values) error {
+	var signer Signer = HMACSigner(apiKey.Secret)
+	if c.Signer != nil {
+		signer = c.Signer
+	}
+	var expires = c.Expires()
+	p := regexp.MustCompile("/api.*").FindString(path)
+	signature, err := signer.Sign(httpMethod, p, queryParams.Encode(), expires, postBody)
+	if err != nil {
+		return err
+	}
 	request.Header.Add("api-key", apiKey.Key)
 	request.Header.Add("api-expires", expires)
-	p := regexp.MustCompile("/api.*").FindString(path)
-	request.Header.Add("api-signature", Signature(apiKey.Secret, httpMethod, p, queryParams.Encode(),
-		expires, postBody))
+	request.Header.Add("api-signature", signature)
+	return nil
 }
 
 /**
@@ -16,7 +16,7 @@ import (
 
 // Trollbox Data
 type Chat struct {
-	Id float32 `json:"id,omitempty"`
+	Id int64 `json:"id,omitempty"`
 
 	Date time.Time `json:"date"`
 
@@ -27,13 +27,14 @@ type ChatApiService service
 
 /* ChatApiService Get chat messages.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "count" (float32) Number of results to fetch.
     @param "start" (float32) Starting ID for results.
     @param "reverse" (bool) If true, will sort results newest first.
     @param "channelID" (float64) Channel id. GET /chat/channels for ids. Leave blank for all.
 @return []Chat*/
-func (a *ChatApiService) ChatGet(localVarOptionals map[string]interface{}) ([]Chat, *http.Response, error) {
+func (a *ChatApiService) ChatGet(ctx context.Context, localVarOptionals map[string]interface{}) ([]Chat, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -97,7 +98,7 @@ func (a *ChatApiService) ChatGet(localVarOptionals map[string]interface{}) ([]Ch
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -108,7 +109,7 @@ func (a *ChatApiService) ChatGet(localVarOptionals map[string]interface{}) ([]Ch
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -120,8 +121,9 @@ func (a *ChatApiService) ChatGet(localVarOptionals map[string]interface{}) ([]Ch
 
 /* ChatApiService Get available channels.
 
+* @param ctx context.Context for cancellation and tracing
 @return []ChatChannel*/
-func (a *ChatApiService) ChatGetChannels() ([]ChatChannel, *http.Response, error) {
+func (a *ChatApiService) ChatGetChannels(ctx context.Context) ([]ChatChannel, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -160,7 +162,7 @@ func (a *ChatApiService) ChatGetChannels() ([]ChatChannel, *http.Response, error
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -171,7 +173,7 @@ func (a *ChatApiService) ChatGetChannels() ([]ChatChannel, *http.Response, error
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -184,8 +186,9 @@ func (a *ChatApiService) ChatGetChannels() ([]ChatChannel, *http.Response, error
 /* ChatApiService Get connected users.
 Returns an array with browser users in the first position and API users (bots) in the second position.
 
+* @param ctx context.Context for cancellation and tracing
 @return ConnectedUsers*/
-func (a *ChatApiService) ChatGetConnected() (ConnectedUsers, *http.Response, error) {
+func (a *ChatApiService) ChatGetConnected(ctx context.Context) (ConnectedUsers, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -224,7 +227,7 @@ func (a *ChatApiService) ChatGetConnected() (ConnectedUsers, *http.Response, err
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -235,7 +238,7 @@ func (a *ChatApiService) ChatGetConnected() (ConnectedUsers, *http.Response, err
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -345,7 +348,7 @@ func (a *ChatApiService) ChatNew(ctx context.Context, message string, localVarOp
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -12,12 +12,18 @@ package swagger
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 )
 
 const ContextOAuth2 int = 1
 const ContextBasicAuth int = 2
 const ContextAccessToken int = 3
 const ContextAPIKey int = 4
+const ContextTraceID int = 5
+
+// TraceIDHeader carries the ContextTraceID of a request
+const TraceIDHeader = "X-Request-Id"
 
 type BasicAuth struct {
 	UserName string `json:"userName,omitempty"`
@@ -41,6 +47,14 @@ type Configuration struct {
 	HTTPClient    *http.Client
 
 	ExpireTime int64
+
+	// ExpiresAt returns the api-expires time of a request signed now, e.g.
+	// on the server clock; nil uses the local clock plus ExpireTime
+	ExpiresAt func() time.Time
+
+	// Signer signs authenticated requests; nil signs with the secret of
+	// the ContextAPIKey
+	Signer Signer
 }
 
 func NewConfiguration() *Configuration {
@@ -53,6 +67,14 @@ func NewConfiguration() *Configuration {
 	return cfg
 }
 
+// Expires returns the api-expires value of a request signed now
+func (c *Configuration) Expires() string {
+	if c.ExpiresAt != nil {
+		return strconv.FormatInt(c.ExpiresAt().Unix(), 10)
+	}
+	return strconv.FormatInt(time.Now().Unix()+c.ExpireTime, 10)
+}
+
 func (c *Configuration) AddDefaultHeader(key string, value string) {
 	c.DefaultHeader[key] = value
 }
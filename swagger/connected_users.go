@@ -11,7 +11,7 @@
 package swagger
 
 type ConnectedUsers struct {
-	Users float32 `json:"users,omitempty"`
+	Users int64 `json:"users,omitempty"`
 
-	Bots float32 `json:"bots,omitempty"`
+	Bots int64 `json:"bots,omitempty"`
 }
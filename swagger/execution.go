@@ -24,13 +24,13 @@ type Execution struct {
 
 	ClOrdLinkID string `json:"clOrdLinkID,omitempty"`
 
-	Account float32 `json:"account,omitempty"`
+	Account int64 `json:"account,omitempty"`
 
 	Symbol string `json:"symbol,omitempty"`
 
 	Side string `json:"side,omitempty"`
 
-	LastQty float32 `json:"lastQty,omitempty"`
+	LastQty int64 `json:"lastQty,omitempty"`
 
 	LastPx float64 `json:"lastPx,omitempty"`
 
@@ -42,11 +42,11 @@ type Execution struct {
 
 	SimpleOrderQty float64 `json:"simpleOrderQty,omitempty"`
 
-	OrderQty float32 `json:"orderQty,omitempty"`
+	OrderQty int64 `json:"orderQty,omitempty"`
 
 	Price float64 `json:"price,omitempty"`
 
-	DisplayQty float32 `json:"displayQty,omitempty"`
+	DisplayQty int64 `json:"displayQty,omitempty"`
 
 	StopPx float64 `json:"stopPx,omitempty"`
 
@@ -80,11 +80,11 @@ type Execution struct {
 
 	SimpleLeavesQty float64 `json:"simpleLeavesQty,omitempty"`
 
-	LeavesQty float32 `json:"leavesQty,omitempty"`
+	LeavesQty int64 `json:"leavesQty,omitempty"`
 
 	SimpleCumQty float64 `json:"simpleCumQty,omitempty"`
 
-	CumQty float32 `json:"cumQty,omitempty"`
+	CumQty int64 `json:"cumQty,omitempty"`
 
 	AvgPx float64 `json:"avgPx,omitempty"`
 
@@ -98,9 +98,9 @@ type Execution struct {
 
 	TrdMatchID string `json:"trdMatchID,omitempty"`
 
-	ExecCost float32 `json:"execCost,omitempty"`
+	ExecCost int64 `json:"execCost,omitempty"`
 
-	ExecComm float32 `json:"execComm,omitempty"`
+	ExecComm int64 `json:"execComm,omitempty"`
 
 	HomeNotional float64 `json:"homeNotional,omitempty"`
 
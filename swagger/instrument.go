@@ -58,23 +58,23 @@ type Instrument struct {
 
 	PublishTime time.Time `json:"publishTime,omitempty"`
 
-	MaxOrderQty float32 `json:"maxOrderQty,omitempty"`
+	MaxOrderQty int64 `json:"maxOrderQty,omitempty"`
 
 	MaxPrice float64 `json:"maxPrice,omitempty"`
 
-	LotSize float32 `json:"lotSize,omitempty"`
+	LotSize int64 `json:"lotSize,omitempty"`
 
 	TickSize float64 `json:"tickSize,omitempty"`
 
-	Multiplier float32 `json:"multiplier,omitempty"`
+	Multiplier int64 `json:"multiplier,omitempty"`
 
 	SettlCurrency string `json:"settlCurrency,omitempty"`
 
-	UnderlyingToPositionMultiplier float32 `json:"underlyingToPositionMultiplier,omitempty"`
+	UnderlyingToPositionMultiplier int64 `json:"underlyingToPositionMultiplier,omitempty"`
 
-	UnderlyingToSettleMultiplier float32 `json:"underlyingToSettleMultiplier,omitempty"`
+	UnderlyingToSettleMultiplier int64 `json:"underlyingToSettleMultiplier,omitempty"`
 
-	QuoteToSettleMultiplier float32 `json:"quoteToSettleMultiplier,omitempty"`
+	QuoteToSettleMultiplier int64 `json:"quoteToSettleMultiplier,omitempty"`
 
 	IsQuanto bool `json:"isQuanto,omitempty"`
 
@@ -84,9 +84,9 @@ type Instrument struct {
 
 	MaintMargin float64 `json:"maintMargin,omitempty"`
 
-	RiskLimit float32 `json:"riskLimit,omitempty"`
+	RiskLimit int64 `json:"riskLimit,omitempty"`
 
-	RiskStep float32 `json:"riskStep,omitempty"`
+	RiskStep int64 `json:"riskStep,omitempty"`
 
 	Limit float64 `json:"limit,omitempty"`
 
@@ -138,21 +138,21 @@ type Instrument struct {
 
 	BankruptLimitUpPrice float64 `json:"bankruptLimitUpPrice,omitempty"`
 
-	PrevTotalVolume float32 `json:"prevTotalVolume,omitempty"`
+	PrevTotalVolume int64 `json:"prevTotalVolume,omitempty"`
 
-	TotalVolume float32 `json:"totalVolume,omitempty"`
+	TotalVolume int64 `json:"totalVolume,omitempty"`
 
-	Volume float32 `json:"volume,omitempty"`
+	Volume int64 `json:"volume,omitempty"`
 
-	Volume24h float32 `json:"volume24h,omitempty"`
+	Volume24h int64 `json:"volume24h,omitempty"`
 
-	PrevTotalTurnover float32 `json:"prevTotalTurnover,omitempty"`
+	PrevTotalTurnover int64 `json:"prevTotalTurnover,omitempty"`
 
-	TotalTurnover float32 `json:"totalTurnover,omitempty"`
+	TotalTurnover int64 `json:"totalTurnover,omitempty"`
 
-	Turnover float32 `json:"turnover,omitempty"`
+	Turnover int64 `json:"turnover,omitempty"`
 
-	Turnover24h float32 `json:"turnover24h,omitempty"`
+	Turnover24h int64 `json:"turnover24h,omitempty"`
 
 	PrevPrice24h float64 `json:"prevPrice24h,omitempty"`
 
@@ -184,9 +184,9 @@ type Instrument struct {
 
 	HasLiquidity bool `json:"hasLiquidity,omitempty"`
 
-	OpenInterest float32 `json:"openInterest,omitempty"`
+	OpenInterest int64 `json:"openInterest,omitempty"`
 
-	OpenValue float32 `json:"openValue,omitempty"`
+	OpenValue int64 `json:"openValue,omitempty"`
 
 	FairMethod string `json:"fairMethod,omitempty"`
 
@@ -29,6 +29,7 @@ type InstrumentApiService service
 /* InstrumentApiService Get instruments.
 This returns all instruments and indices, including those that have settled or are unlisted. Use this endpoint if you want to query for individual instruments or use a complex filter. Use &#x60;/instrument/active&#x60; to return active instruments, or use a filter like &#x60;{\&quot;state\&quot;: \&quot;Open\&quot;}&#x60;.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "symbol" (string) Instrument symbol. Send a bare series (e.g. XBU) to get data for the nearest expiring contract in that series.  You can also send a timeframe, e.g. &#x60;XBU:monthly&#x60;. Timeframes are &#x60;daily&#x60;, &#x60;weekly&#x60;, &#x60;monthly&#x60;, &#x60;quarterly&#x60;, and &#x60;biquarterly&#x60;.
     @param "filter" (string) Generic table filter. Send JSON key/value pairs, such as &#x60;{\&quot;key\&quot;: \&quot;value\&quot;}&#x60;. You can key on individual fields, and do more advanced querying on timestamps. See the [Timestamp Docs](https://www.bitmex.com/app/restAPI#timestamp-filters) for more details.
@@ -39,7 +40,7 @@ This returns all instruments and indices, including those that have settled or a
     @param "startTime" (time.Time) Starting date filter for results.
     @param "endTime" (time.Time) Ending date filter for results.
 @return []Instrument*/
-func (a *InstrumentApiService) InstrumentGet(localVarOptionals map[string]interface{}) ([]Instrument, *http.Response, error) {
+func (a *InstrumentApiService) InstrumentGet(ctx context.Context, localVarOptionals map[string]interface{}) ([]Instrument, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -127,7 +128,7 @@ func (a *InstrumentApiService) InstrumentGet(localVarOptionals map[string]interf
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -138,7 +139,7 @@ func (a *InstrumentApiService) InstrumentGet(localVarOptionals map[string]interf
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -150,8 +151,9 @@ func (a *InstrumentApiService) InstrumentGet(localVarOptionals map[string]interf
 
 /* InstrumentApiService Get all active instruments and instruments that have expired in &lt;24hrs.
 
+* @param ctx context.Context for cancellation and tracing
 @return []Instrument*/
-func (a *InstrumentApiService) InstrumentGetActive() ([]Instrument, *http.Response, error) {
+func (a *InstrumentApiService) InstrumentGetActive(ctx context.Context) ([]Instrument, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -190,7 +192,7 @@ func (a *InstrumentApiService) InstrumentGetActive() ([]Instrument, *http.Respon
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -201,7 +203,7 @@ func (a *InstrumentApiService) InstrumentGetActive() ([]Instrument, *http.Respon
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -213,8 +215,9 @@ func (a *InstrumentApiService) InstrumentGetActive() ([]Instrument, *http.Respon
 
 /* InstrumentApiService Helper method. Gets all active instruments and all indices. This is a join of the result of /indices and /active.
 
+* @param ctx context.Context for cancellation and tracing
 @return []Instrument*/
-func (a *InstrumentApiService) InstrumentGetActiveAndIndices() ([]Instrument, *http.Response, error) {
+func (a *InstrumentApiService) InstrumentGetActiveAndIndices(ctx context.Context) ([]Instrument, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -253,7 +256,7 @@ func (a *InstrumentApiService) InstrumentGetActiveAndIndices() ([]Instrument, *h
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -264,7 +267,7 @@ func (a *InstrumentApiService) InstrumentGetActiveAndIndices() ([]Instrument, *h
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -277,8 +280,9 @@ func (a *InstrumentApiService) InstrumentGetActiveAndIndices() ([]Instrument, *h
 /* InstrumentApiService Return all active contract series and interval pairs.
 This endpoint is useful for determining which pairs are live. It returns two arrays of   strings. The first is intervals, such as &#x60;[\&quot;BVOL:daily\&quot;, \&quot;BVOL:weekly\&quot;, \&quot;XBU:daily\&quot;, \&quot;XBU:monthly\&quot;, ...]&#x60;. These identifiers are usable in any query&#39;s &#x60;symbol&#x60; param. The second array is the current resolution of these intervals. Results are mapped at the same index.
 
+* @param ctx context.Context for cancellation and tracing
 @return InstrumentInterval*/
-func (a *InstrumentApiService) InstrumentGetActiveIntervals() (InstrumentInterval, *http.Response, error) {
+func (a *InstrumentApiService) InstrumentGetActiveIntervals(ctx context.Context) (InstrumentInterval, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -317,7 +321,7 @@ func (a *InstrumentApiService) InstrumentGetActiveIntervals() (InstrumentInterva
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -328,7 +332,7 @@ func (a *InstrumentApiService) InstrumentGetActiveIntervals() (InstrumentInterva
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -341,6 +345,7 @@ func (a *InstrumentApiService) InstrumentGetActiveIntervals() (InstrumentInterva
 /* InstrumentApiService Show constituent parts of an index.
 Composite indices are built from multiple external price sources.  Use this endpoint to get the underlying prices of an index. For example, send a &#x60;symbol&#x60; of &#x60;.XBT&#x60; to get the ticks and weights of the constituent exchanges that build the \&quot;.XBT\&quot; index.  A tick with reference &#x60;\&quot;BMI\&quot;&#x60; and weight &#x60;null&#x60; is the composite index tick.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "account" (float64)
     @param "symbol" (string) The composite index symbol.
@@ -352,7 +357,7 @@ Composite indices are built from multiple external price sources.  Use this endp
     @param "startTime" (time.Time) Starting date filter for results.
     @param "endTime" (time.Time) Ending date filter for results.
 @return []IndexComposite*/
-func (a *InstrumentApiService) InstrumentGetCompositeIndex(localVarOptionals map[string]interface{}) ([]IndexComposite, *http.Response, error) {
+func (a *InstrumentApiService) InstrumentGetCompositeIndex(ctx context.Context, localVarOptionals map[string]interface{}) ([]IndexComposite, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -446,7 +451,7 @@ func (a *InstrumentApiService) InstrumentGetCompositeIndex(localVarOptionals map
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -457,7 +462,7 @@ func (a *InstrumentApiService) InstrumentGetCompositeIndex(localVarOptionals map
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -469,8 +474,9 @@ func (a *InstrumentApiService) InstrumentGetCompositeIndex(localVarOptionals map
 
 /* InstrumentApiService Get all price indices.
 
+* @param ctx context.Context for cancellation and tracing
 @return []Instrument*/
-func (a *InstrumentApiService) InstrumentGetIndices() ([]Instrument, *http.Response, error) {
+func (a *InstrumentApiService) InstrumentGetIndices(ctx context.Context) ([]Instrument, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -509,7 +515,7 @@ func (a *InstrumentApiService) InstrumentGetIndices() ([]Instrument, *http.Respo
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -520,7 +526,7 @@ func (a *InstrumentApiService) InstrumentGetIndices() ([]Instrument, *http.Respo
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -20,5 +20,5 @@ type Insurance struct {
 
 	Timestamp time.Time `json:"timestamp"`
 
-	WalletBalance float32 `json:"walletBalance,omitempty"`
+	WalletBalance int64 `json:"walletBalance,omitempty"`
 }
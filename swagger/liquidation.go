@@ -20,5 +20,5 @@ type Liquidation struct {
 
 	Price float64 `json:"price,omitempty"`
 
-	LeavesQty float32 `json:"leavesQty,omitempty"`
+	LeavesQty int64 `json:"leavesQty,omitempty"`
 }
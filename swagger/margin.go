@@ -15,11 +15,11 @@ import (
 )
 
 type Margin struct {
-	Account float32 `json:"account"`
+	Account int64 `json:"account"`
 
 	Currency string `json:"currency"`
 
-	RiskLimit float32 `json:"riskLimit,omitempty"`
+	RiskLimit int64 `json:"riskLimit,omitempty"`
 
 	PrevState string `json:"prevState,omitempty"`
 
@@ -27,55 +27,55 @@ type Margin struct {
 
 	Action string `json:"action,omitempty"`
 
-	Amount float32 `json:"amount,omitempty"`
+	Amount int64 `json:"amount,omitempty"`
 
-	PendingCredit float32 `json:"pendingCredit,omitempty"`
+	PendingCredit int64 `json:"pendingCredit,omitempty"`
 
-	PendingDebit float32 `json:"pendingDebit,omitempty"`
+	PendingDebit int64 `json:"pendingDebit,omitempty"`
 
-	ConfirmedDebit float32 `json:"confirmedDebit,omitempty"`
+	ConfirmedDebit int64 `json:"confirmedDebit,omitempty"`
 
-	PrevRealisedPnl float32 `json:"prevRealisedPnl,omitempty"`
+	PrevRealisedPnl int64 `json:"prevRealisedPnl,omitempty"`
 
-	PrevUnrealisedPnl float32 `json:"prevUnrealisedPnl,omitempty"`
+	PrevUnrealisedPnl int64 `json:"prevUnrealisedPnl,omitempty"`
 
-	GrossComm float32 `json:"grossComm,omitempty"`
+	GrossComm int64 `json:"grossComm,omitempty"`
 
-	GrossOpenCost float32 `json:"grossOpenCost,omitempty"`
+	GrossOpenCost int64 `json:"grossOpenCost,omitempty"`
 
-	GrossOpenPremium float32 `json:"grossOpenPremium,omitempty"`
+	GrossOpenPremium int64 `json:"grossOpenPremium,omitempty"`
 
-	GrossExecCost float32 `json:"grossExecCost,omitempty"`
+	GrossExecCost int64 `json:"grossExecCost,omitempty"`
 
-	GrossMarkValue float32 `json:"grossMarkValue,omitempty"`
+	GrossMarkValue int64 `json:"grossMarkValue,omitempty"`
 
-	RiskValue float32 `json:"riskValue,omitempty"`
+	RiskValue int64 `json:"riskValue,omitempty"`
 
-	TaxableMargin float32 `json:"taxableMargin,omitempty"`
+	TaxableMargin int64 `json:"taxableMargin,omitempty"`
 
-	InitMargin float32 `json:"initMargin,omitempty"`
+	InitMargin int64 `json:"initMargin,omitempty"`
 
-	MaintMargin float32 `json:"maintMargin,omitempty"`
+	MaintMargin int64 `json:"maintMargin,omitempty"`
 
-	SessionMargin float32 `json:"sessionMargin,omitempty"`
+	SessionMargin int64 `json:"sessionMargin,omitempty"`
 
-	TargetExcessMargin float32 `json:"targetExcessMargin,omitempty"`
+	TargetExcessMargin int64 `json:"targetExcessMargin,omitempty"`
 
-	VarMargin float32 `json:"varMargin,omitempty"`
+	VarMargin int64 `json:"varMargin,omitempty"`
 
-	RealisedPnl float32 `json:"realisedPnl,omitempty"`
+	RealisedPnl int64 `json:"realisedPnl,omitempty"`
 
-	UnrealisedPnl float32 `json:"unrealisedPnl,omitempty"`
+	UnrealisedPnl int64 `json:"unrealisedPnl,omitempty"`
 
-	IndicativeTax float32 `json:"indicativeTax,omitempty"`
+	IndicativeTax int64 `json:"indicativeTax,omitempty"`
 
-	UnrealisedProfit float32 `json:"unrealisedProfit,omitempty"`
+	UnrealisedProfit int64 `json:"unrealisedProfit,omitempty"`
 
-	SyntheticMargin float32 `json:"syntheticMargin,omitempty"`
+	SyntheticMargin int64 `json:"syntheticMargin,omitempty"`
 
-	WalletBalance float32 `json:"walletBalance,omitempty"`
+	WalletBalance int64 `json:"walletBalance,omitempty"`
 
-	MarginBalance float32 `json:"marginBalance,omitempty"`
+	MarginBalance int64 `json:"marginBalance,omitempty"`
 
 	MarginBalancePcnt float64 `json:"marginBalancePcnt,omitempty"`
 
@@ -83,17 +83,17 @@ type Margin struct {
 
 	MarginUsedPcnt float64 `json:"marginUsedPcnt,omitempty"`
 
-	ExcessMargin float32 `json:"excessMargin,omitempty"`
+	ExcessMargin int64 `json:"excessMargin,omitempty"`
 
 	ExcessMarginPcnt float64 `json:"excessMarginPcnt,omitempty"`
 
-	AvailableMargin float32 `json:"availableMargin,omitempty"`
+	AvailableMargin int64 `json:"availableMargin,omitempty"`
 
-	WithdrawableMargin float32 `json:"withdrawableMargin,omitempty"`
+	WithdrawableMargin int64 `json:"withdrawableMargin,omitempty"`
 
 	Timestamp time.Time `json:"timestamp,omitempty"`
 
-	GrossLastValue float32 `json:"grossLastValue,omitempty"`
+	GrossLastValue int64 `json:"grossLastValue,omitempty"`
 
 	Commission float64 `json:"commission,omitempty"`
 }
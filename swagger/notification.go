@@ -16,7 +16,7 @@ import (
 
 // Account Notifications
 type Notification struct {
-	Id float32 `json:"id,omitempty"`
+	Id int64 `json:"id,omitempty"`
 
 	Date time.Time `json:"date"`
 
@@ -24,7 +24,7 @@ type Notification struct {
 
 	Body string `json:"body"`
 
-	Ttl float32 `json:"ttl"`
+	Ttl int64 `json:"ttl"`
 
 	Type_ string `json:"type,omitempty"`
 
@@ -22,7 +22,7 @@ type Order struct {
 
 	ClOrdLinkID string `json:"clOrdLinkID,omitempty"`
 
-	Account float32 `json:"account,omitempty"`
+	Account int64 `json:"account,omitempty"`
 
 	Symbol string `json:"symbol,omitempty"`
 
@@ -30,11 +30,11 @@ type Order struct {
 
 	SimpleOrderQty float64 `json:"simpleOrderQty,omitempty"`
 
-	OrderQty float32 `json:"orderQty,omitempty"`
+	OrderQty int64 `json:"orderQty,omitempty"`
 
 	Price float64 `json:"price,omitempty"`
 
-	DisplayQty float32 `json:"displayQty,omitempty"`
+	DisplayQty int64 `json:"displayQty,omitempty"`
 
 	StopPx float64 `json:"stopPx,omitempty"`
 
@@ -66,11 +66,11 @@ type Order struct {
 
 	SimpleLeavesQty float64 `json:"simpleLeavesQty,omitempty"`
 
-	LeavesQty float32 `json:"leavesQty,omitempty"`
+	LeavesQty int64 `json:"leavesQty,omitempty"`
 
 	SimpleCumQty float64 `json:"simpleCumQty,omitempty"`
 
-	CumQty float32 `json:"cumQty,omitempty"`
+	CumQty int64 `json:"cumQty,omitempty"`
 
 	AvgPx float64 `json:"avgPx,omitempty"`
 
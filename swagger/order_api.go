@@ -13,7 +13,6 @@ package swagger
 import (
 	"encoding/json"
 	"golang.org/x/net/context"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
@@ -35,12 +34,12 @@ Send an &#x60;orderID&#x60; or &#x60;origClOrdID&#x60; to identify the order you
     @param "origClOrdID" (string) Client Order ID. See POST /order.
     @param "clOrdID" (string) Optional new Client Order ID, requires &#x60;origClOrdID&#x60;.
     @param "simpleOrderQty" (float64) Optional order quantity in units of the underlying instrument (i.e. Bitcoin).
-    @param "orderQty" (float32) Optional order quantity in units of the instrument (i.e. contracts).
+    @param "orderQty" (int64) Optional order quantity in units of the instrument (i.e. contracts).
     @param "simpleLeavesQty" (float64) Optional leaves quantity in units of the underlying instrument (i.e. Bitcoin). Useful for amending partially filled orders.
-    @param "leavesQty" (float32) Optional leaves quantity in units of the instrument (i.e. contracts). Useful for amending partially filled orders.
-    @param "price" (float64) Optional limit price for &#39;Limit&#39;, &#39;StopLimit&#39;, and &#39;LimitIfTouched&#39; orders.
-    @param "stopPx" (float64) Optional trigger price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders. Use a price below the current price for stop-sell orders and buy-if-touched orders.
-    @param "pegOffsetValue" (float64) Optional trailing offset from the current price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders; use a negative offset for stop-sell orders and buy-if-touched orders. Optional offset from the peg price for &#39;Pegged&#39; orders.
+    @param "leavesQty" (int64) Optional leaves quantity in units of the instrument (i.e. contracts). Useful for amending partially filled orders.
+    @param "price" (string) Optional limit price for &#39;Limit&#39;, &#39;StopLimit&#39;, and &#39;LimitIfTouched&#39; orders.
+    @param "stopPx" (string) Optional trigger price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders. Use a price below the current price for stop-sell orders and buy-if-touched orders.
+    @param "pegOffsetValue" (string) Optional trailing offset from the current price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders; use a negative offset for stop-sell orders and buy-if-touched orders. Optional offset from the peg price for &#39;Pegged&#39; orders.
     @param "text" (string) Optional amend annotation. e.g. &#39;Adjust skew&#39;.
 @return Order*/
 func (a *OrderApiService) OrderAmend(ctx context.Context, localVarOptionals map[string]interface{}) (Order, *http.Response, error) {
@@ -71,22 +70,22 @@ func (a *OrderApiService) OrderAmend(ctx context.Context, localVarOptionals map[
 	if err := typeCheckParameter(localVarOptionals["simpleOrderQty"], "float64", "simpleOrderQty"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["orderQty"], "float32", "orderQty"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["orderQty"], "int64", "orderQty"); err != nil {
 		return successPayload, nil, err
 	}
 	if err := typeCheckParameter(localVarOptionals["simpleLeavesQty"], "float64", "simpleLeavesQty"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["leavesQty"], "float32", "leavesQty"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["leavesQty"], "int64", "leavesQty"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["price"], "float64", "price"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["price"], "string", "price"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["stopPx"], "float64", "stopPx"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["stopPx"], "string", "stopPx"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["pegOffsetValue"], "float64", "pegOffsetValue"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["pegOffsetValue"], "string", "pegOffsetValue"); err != nil {
 		return successPayload, nil, err
 	}
 	if err := typeCheckParameter(localVarOptionals["text"], "string", "text"); err != nil {
@@ -128,22 +127,22 @@ func (a *OrderApiService) OrderAmend(ctx context.Context, localVarOptionals map[
 	if localVarTempParam, localVarOk := localVarOptionals["simpleOrderQty"].(float64); localVarOk {
 		localVarFormParams.Add("simpleOrderQty", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["orderQty"].(float32); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["orderQty"].(int64); localVarOk {
 		localVarFormParams.Add("orderQty", parameterToString(localVarTempParam, ""))
 	}
 	if localVarTempParam, localVarOk := localVarOptionals["simpleLeavesQty"].(float64); localVarOk {
 		localVarFormParams.Add("simpleLeavesQty", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["leavesQty"].(float32); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["leavesQty"].(int64); localVarOk {
 		localVarFormParams.Add("leavesQty", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["price"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["price"].(string); localVarOk {
 		localVarFormParams.Add("price", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["stopPx"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["stopPx"].(string); localVarOk {
 		localVarFormParams.Add("stopPx", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["pegOffsetValue"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["pegOffsetValue"].(string); localVarOk {
 		localVarFormParams.Add("pegOffsetValue", parameterToString(localVarTempParam, ""))
 	}
 	if localVarTempParam, localVarOk := localVarOptionals["text"].(string); localVarOk {
@@ -196,8 +195,7 @@ func (a *OrderApiService) OrderAmend(ctx context.Context, localVarOptionals map[
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		bodyBytes, _ := ioutil.ReadAll(localVarHttpResponse.Body)
-		return successPayload, localVarHttpResponse, reportError("%v Text=%v", localVarHttpResponse.Status, string(bodyBytes))
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -306,7 +304,7 @@ func (a *OrderApiService) OrderAmendBulk(ctx context.Context, localVarOptionals
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -429,7 +427,7 @@ func (a *OrderApiService) OrderCancel(ctx context.Context, localVarOptionals map
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -551,7 +549,7 @@ func (a *OrderApiService) OrderCancelAll(ctx context.Context, localVarOptionals
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -653,7 +651,7 @@ func (a *OrderApiService) OrderCancelAllAfter(ctx context.Context, timeout float
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -668,7 +666,7 @@ If no &#x60;price&#x60; is specified, a market order will be submitted to close
 * @param ctx context.Context Authentication Context
 @param symbol Symbol of position to close.
 @param optional (nil or map[string]interface{}) with one or more of:
-    @param "price" (float64) Optional limit price.
+    @param "price" (string) Optional limit price.
 @return Order*/
 func (a *OrderApiService) OrderClosePosition(ctx context.Context, symbol string, localVarOptionals map[string]interface{}) (Order, *http.Response, error) {
 	var (
@@ -686,7 +684,7 @@ func (a *OrderApiService) OrderClosePosition(ctx context.Context, symbol string,
 	localVarQueryParams := url.Values{}
 	localVarFormParams := url.Values{}
 
-	if err := typeCheckParameter(localVarOptionals["price"], "float64", "price"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["price"], "string", "price"); err != nil {
 		return successPayload, nil, err
 	}
 
@@ -714,7 +712,7 @@ func (a *OrderApiService) OrderClosePosition(ctx context.Context, symbol string,
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
 	localVarFormParams.Add("symbol", parameterToString(symbol, ""))
-	if localVarTempParam, localVarOk := localVarOptionals["price"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["price"].(string); localVarOk {
 		localVarFormParams.Add("price", parameterToString(localVarTempParam, ""))
 	}
 	if ctx != nil {
@@ -764,7 +762,7 @@ func (a *OrderApiService) OrderClosePosition(ctx context.Context, symbol string,
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -922,7 +920,7 @@ func (a *OrderApiService) OrderGetOrders(ctx context.Context, localVarOptionals
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -939,15 +937,15 @@ func (a *OrderApiService) OrderGetOrders(ctx context.Context, localVarOptionals
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "side" (string) Order side. Valid options: Buy, Sell. Defaults to &#39;Buy&#39; unless &#x60;orderQty&#x60; or &#x60;simpleOrderQty&#x60; is negative.
     @param "simpleOrderQty" (float64) Order quantity in units of the underlying instrument (i.e. Bitcoin).
-    @param "quantity" (float32) Deprecated: use &#x60;orderQty&#x60;.
-    @param "orderQty" (float32) Order quantity in units of the instrument (i.e. contracts).
-    @param "price" (float64) Optional limit price for &#39;Limit&#39;, &#39;StopLimit&#39;, and &#39;LimitIfTouched&#39; orders.
-    @param "displayQty" (float32) Optional quantity to display in the book. Use 0 for a fully hidden order.
+    @param "quantity" (int64) Deprecated: use &#x60;orderQty&#x60;.
+    @param "orderQty" (int64) Order quantity in units of the instrument (i.e. contracts).
+    @param "price" (string) Optional limit price for &#39;Limit&#39;, &#39;StopLimit&#39;, and &#39;LimitIfTouched&#39; orders.
+    @param "displayQty" (int64) Optional quantity to display in the book. Use 0 for a fully hidden order.
     @param "stopPrice" (float64) Deprecated: use &#x60;stopPx&#x60;.
-    @param "stopPx" (float64) Optional trigger price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders. Use a price below the current price for stop-sell orders and buy-if-touched orders. Use &#x60;execInst&#x60; of &#39;MarkPrice&#39; or &#39;LastPrice&#39; to define the current price used for triggering.
+    @param "stopPx" (string) Optional trigger price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders. Use a price below the current price for stop-sell orders and buy-if-touched orders. Use &#x60;execInst&#x60; of &#39;MarkPrice&#39; or &#39;LastPrice&#39; to define the current price used for triggering.
     @param "clOrdID" (string) Optional Client Order ID. This clOrdID will come back on the order and any related executions.
     @param "clOrdLinkID" (string) Optional Client Order Link ID for contingent orders.
-    @param "pegOffsetValue" (float64) Optional trailing offset from the current price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders; use a negative offset for stop-sell orders and buy-if-touched orders. Optional offset from the peg price for &#39;Pegged&#39; orders.
+    @param "pegOffsetValue" (string) Optional trailing offset from the current price for &#39;Stop&#39;, &#39;StopLimit&#39;, &#39;MarketIfTouched&#39;, and &#39;LimitIfTouched&#39; orders; use a negative offset for stop-sell orders and buy-if-touched orders. Optional offset from the peg price for &#39;Pegged&#39; orders.
     @param "pegPriceType" (string) Optional peg price type. Valid options: LastPeg, MidPricePeg, MarketPeg, PrimaryPeg, TrailingStopPeg.
     @param "type_" (string) Deprecated: use &#x60;ordType&#x60;.
     @param "ordType" (string) Order type. Valid options: Market, Limit, Stop, StopLimit, MarketIfTouched, LimitIfTouched, MarketWithLeftOverAsLimit, Pegged. Defaults to &#39;Limit&#39; when &#x60;price&#x60; is specified. Defaults to &#39;Stop&#39; when &#x60;stopPx&#x60; is specified. Defaults to &#39;StopLimit&#39; when &#x60;price&#x60; and &#x60;stopPx&#x60; are specified.
@@ -978,22 +976,22 @@ func (a *OrderApiService) OrderNew(ctx context.Context, symbol string, localVarO
 	if err := typeCheckParameter(localVarOptionals["simpleOrderQty"], "float64", "simpleOrderQty"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["quantity"], "float32", "quantity"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["quantity"], "int64", "quantity"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["orderQty"], "float32", "orderQty"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["orderQty"], "int64", "orderQty"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["price"], "float64", "price"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["price"], "string", "price"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["displayQty"], "float32", "displayQty"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["displayQty"], "int64", "displayQty"); err != nil {
 		return successPayload, nil, err
 	}
 	if err := typeCheckParameter(localVarOptionals["stopPrice"], "float64", "stopPrice"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["stopPx"], "float64", "stopPx"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["stopPx"], "string", "stopPx"); err != nil {
 		return successPayload, nil, err
 	}
 	if err := typeCheckParameter(localVarOptionals["clOrdID"], "string", "clOrdID"); err != nil {
@@ -1002,7 +1000,7 @@ func (a *OrderApiService) OrderNew(ctx context.Context, symbol string, localVarO
 	if err := typeCheckParameter(localVarOptionals["clOrdLinkID"], "string", "clOrdLinkID"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["pegOffsetValue"], "float64", "pegOffsetValue"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["pegOffsetValue"], "string", "pegOffsetValue"); err != nil {
 		return successPayload, nil, err
 	}
 	if err := typeCheckParameter(localVarOptionals["pegPriceType"], "string", "pegPriceType"); err != nil {
@@ -1057,22 +1055,22 @@ func (a *OrderApiService) OrderNew(ctx context.Context, symbol string, localVarO
 	if localVarTempParam, localVarOk := localVarOptionals["simpleOrderQty"].(float64); localVarOk {
 		localVarFormParams.Add("simpleOrderQty", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["quantity"].(float32); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["quantity"].(int64); localVarOk {
 		localVarFormParams.Add("quantity", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["orderQty"].(float32); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["orderQty"].(int64); localVarOk {
 		localVarFormParams.Add("orderQty", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["price"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["price"].(string); localVarOk {
 		localVarFormParams.Add("price", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["displayQty"].(float32); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["displayQty"].(int64); localVarOk {
 		localVarFormParams.Add("displayQty", parameterToString(localVarTempParam, ""))
 	}
 	if localVarTempParam, localVarOk := localVarOptionals["stopPrice"].(float64); localVarOk {
 		localVarFormParams.Add("stopPrice", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["stopPx"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["stopPx"].(string); localVarOk {
 		localVarFormParams.Add("stopPx", parameterToString(localVarTempParam, ""))
 	}
 	if localVarTempParam, localVarOk := localVarOptionals["clOrdID"].(string); localVarOk {
@@ -1081,7 +1079,7 @@ func (a *OrderApiService) OrderNew(ctx context.Context, symbol string, localVarO
 	if localVarTempParam, localVarOk := localVarOptionals["clOrdLinkID"].(string); localVarOk {
 		localVarFormParams.Add("clOrdLinkID", parameterToString(localVarTempParam, ""))
 	}
-	if localVarTempParam, localVarOk := localVarOptionals["pegOffsetValue"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["pegOffsetValue"].(string); localVarOk {
 		localVarFormParams.Add("pegOffsetValue", parameterToString(localVarTempParam, ""))
 	}
 	if localVarTempParam, localVarOk := localVarOptionals["pegPriceType"].(string); localVarOk {
@@ -1119,8 +1117,7 @@ func (a *OrderApiService) OrderNew(ctx context.Context, symbol string, localVarO
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		bodyBytes, _ := ioutil.ReadAll(localVarHttpResponse.Body)
-		return successPayload, localVarHttpResponse, reportError("%v Text=%v", localVarHttpResponse.Status, string(bodyBytes))
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1229,7 +1226,7 @@ func (a *OrderApiService) OrderNewBulk(ctx context.Context, localVarOptionals ma
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
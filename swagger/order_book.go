@@ -18,15 +18,15 @@ import (
 type OrderBook struct {
 	Symbol string `json:"symbol"`
 
-	Level float32 `json:"level"`
+	Level int64 `json:"level"`
 
-	BidSize float32 `json:"bidSize,omitempty"`
+	BidSize int64 `json:"bidSize,omitempty"`
 
 	BidPrice float64 `json:"bidPrice,omitempty"`
 
 	AskPrice float64 `json:"askPrice,omitempty"`
 
-	AskSize float32 `json:"askSize,omitempty"`
+	AskSize int64 `json:"askSize,omitempty"`
 
 	Timestamp time.Time `json:"timestamp,omitempty"`
 }
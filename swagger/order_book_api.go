@@ -27,11 +27,12 @@ type OrderBookApiService service
 
 /* OrderBookApiService Get current orderbook [deprecated, use /orderBook/L2].
 
+* @param ctx context.Context for cancellation and tracing
 @param symbol Instrument symbol. Send a series (e.g. XBT) to get data for the nearest contract in that series.
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "depth" (float32) Orderbook depth.
 @return []OrderBook*/
-func (a *OrderBookApiService) OrderBookGet(symbol string, localVarOptionals map[string]interface{}) ([]OrderBook, *http.Response, error) {
+func (a *OrderBookApiService) OrderBookGet(ctx context.Context, symbol string, localVarOptionals map[string]interface{}) ([]OrderBook, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -78,7 +79,7 @@ func (a *OrderBookApiService) OrderBookGet(symbol string, localVarOptionals map[
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -89,7 +90,7 @@ func (a *OrderBookApiService) OrderBookGet(symbol string, localVarOptionals map[
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -101,11 +102,12 @@ func (a *OrderBookApiService) OrderBookGet(symbol string, localVarOptionals map[
 
 /* OrderBookApiService Get current orderbook in vertical format.
 
+* @param ctx context.Context for cancellation and tracing
 @param symbol Instrument symbol. Send a series (e.g. XBT) to get data for the nearest contract in that series.
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "depth" (float32) Orderbook depth per side. Send 0 for full depth.
 @return []OrderBookL2*/
-func (a *OrderBookApiService) OrderBookGetL2(symbol string, localVarOptionals map[string]interface{}) ([]OrderBookL2, *http.Response, error) {
+func (a *OrderBookApiService) OrderBookGetL2(ctx context.Context, symbol string, localVarOptionals map[string]interface{}) ([]OrderBookL2, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -152,7 +154,7 @@ func (a *OrderBookApiService) OrderBookGetL2(symbol string, localVarOptionals ma
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -163,7 +165,7 @@ func (a *OrderBookApiService) OrderBookGetL2(symbol string, localVarOptionals ma
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -16,7 +16,7 @@ import (
 
 // Summary of Open and Closed Positions
 type Position struct {
-	Account float32 `json:"account"`
+	Account int64 `json:"account"`
 
 	Symbol string `json:"symbol"`
 
@@ -32,7 +32,7 @@ type Position struct {
 
 	MaintMarginReq float64 `json:"maintMarginReq,omitempty"`
 
-	RiskLimit float32 `json:"riskLimit,omitempty"`
+	RiskLimit int64 `json:"riskLimit,omitempty"`
 
 	Leverage float64 `json:"leverage,omitempty"`
 
@@ -40,73 +40,73 @@ type Position struct {
 
 	DeleveragePercentile float64 `json:"deleveragePercentile,omitempty"`
 
-	RebalancedPnl float32 `json:"rebalancedPnl,omitempty"`
+	RebalancedPnl int64 `json:"rebalancedPnl,omitempty"`
 
-	PrevRealisedPnl float32 `json:"prevRealisedPnl,omitempty"`
+	PrevRealisedPnl int64 `json:"prevRealisedPnl,omitempty"`
 
-	PrevUnrealisedPnl float32 `json:"prevUnrealisedPnl,omitempty"`
+	PrevUnrealisedPnl int64 `json:"prevUnrealisedPnl,omitempty"`
 
 	PrevClosePrice float64 `json:"prevClosePrice,omitempty"`
 
 	OpeningTimestamp time.Time `json:"openingTimestamp,omitempty"`
 
-	OpeningQty float32 `json:"openingQty,omitempty"`
+	OpeningQty int64 `json:"openingQty,omitempty"`
 
-	OpeningCost float32 `json:"openingCost,omitempty"`
+	OpeningCost int64 `json:"openingCost,omitempty"`
 
-	OpeningComm float32 `json:"openingComm,omitempty"`
+	OpeningComm int64 `json:"openingComm,omitempty"`
 
-	OpenOrderBuyQty float32 `json:"openOrderBuyQty,omitempty"`
+	OpenOrderBuyQty int64 `json:"openOrderBuyQty,omitempty"`
 
-	OpenOrderBuyCost float32 `json:"openOrderBuyCost,omitempty"`
+	OpenOrderBuyCost int64 `json:"openOrderBuyCost,omitempty"`
 
-	OpenOrderBuyPremium float32 `json:"openOrderBuyPremium,omitempty"`
+	OpenOrderBuyPremium int64 `json:"openOrderBuyPremium,omitempty"`
 
-	OpenOrderSellQty float32 `json:"openOrderSellQty,omitempty"`
+	OpenOrderSellQty int64 `json:"openOrderSellQty,omitempty"`
 
-	OpenOrderSellCost float32 `json:"openOrderSellCost,omitempty"`
+	OpenOrderSellCost int64 `json:"openOrderSellCost,omitempty"`
 
-	OpenOrderSellPremium float32 `json:"openOrderSellPremium,omitempty"`
+	OpenOrderSellPremium int64 `json:"openOrderSellPremium,omitempty"`
 
-	ExecBuyQty float32 `json:"execBuyQty,omitempty"`
+	ExecBuyQty int64 `json:"execBuyQty,omitempty"`
 
-	ExecBuyCost float32 `json:"execBuyCost,omitempty"`
+	ExecBuyCost int64 `json:"execBuyCost,omitempty"`
 
-	ExecSellQty float32 `json:"execSellQty,omitempty"`
+	ExecSellQty int64 `json:"execSellQty,omitempty"`
 
-	ExecSellCost float32 `json:"execSellCost,omitempty"`
+	ExecSellCost int64 `json:"execSellCost,omitempty"`
 
-	ExecQty float32 `json:"execQty,omitempty"`
+	ExecQty int64 `json:"execQty,omitempty"`
 
-	ExecCost float32 `json:"execCost,omitempty"`
+	ExecCost int64 `json:"execCost,omitempty"`
 
-	ExecComm float32 `json:"execComm,omitempty"`
+	ExecComm int64 `json:"execComm,omitempty"`
 
 	CurrentTimestamp time.Time `json:"currentTimestamp,omitempty"`
 
-	CurrentQty float32 `json:"currentQty,omitempty"`
+	CurrentQty int64 `json:"currentQty,omitempty"`
 
-	CurrentCost float32 `json:"currentCost,omitempty"`
+	CurrentCost int64 `json:"currentCost,omitempty"`
 
-	CurrentComm float32 `json:"currentComm,omitempty"`
+	CurrentComm int64 `json:"currentComm,omitempty"`
 
-	RealisedCost float32 `json:"realisedCost,omitempty"`
+	RealisedCost int64 `json:"realisedCost,omitempty"`
 
-	UnrealisedCost float32 `json:"unrealisedCost,omitempty"`
+	UnrealisedCost int64 `json:"unrealisedCost,omitempty"`
 
-	GrossOpenCost float32 `json:"grossOpenCost,omitempty"`
+	GrossOpenCost int64 `json:"grossOpenCost,omitempty"`
 
-	GrossOpenPremium float32 `json:"grossOpenPremium,omitempty"`
+	GrossOpenPremium int64 `json:"grossOpenPremium,omitempty"`
 
-	GrossExecCost float32 `json:"grossExecCost,omitempty"`
+	GrossExecCost int64 `json:"grossExecCost,omitempty"`
 
 	IsOpen bool `json:"isOpen,omitempty"`
 
 	MarkPrice float64 `json:"markPrice,omitempty"`
 
-	MarkValue float32 `json:"markValue,omitempty"`
+	MarkValue int64 `json:"markValue,omitempty"`
 
-	RiskValue float32 `json:"riskValue,omitempty"`
+	RiskValue int64 `json:"riskValue,omitempty"`
 
 	HomeNotional float64 `json:"homeNotional,omitempty"`
 
@@ -114,57 +114,57 @@ type Position struct {
 
 	PosState string `json:"posState,omitempty"`
 
-	PosCost float32 `json:"posCost,omitempty"`
+	PosCost int64 `json:"posCost,omitempty"`
 
-	PosCost2 float32 `json:"posCost2,omitempty"`
+	PosCost2 int64 `json:"posCost2,omitempty"`
 
-	PosCross float32 `json:"posCross,omitempty"`
+	PosCross int64 `json:"posCross,omitempty"`
 
-	PosInit float32 `json:"posInit,omitempty"`
+	PosInit int64 `json:"posInit,omitempty"`
 
-	PosComm float32 `json:"posComm,omitempty"`
+	PosComm int64 `json:"posComm,omitempty"`
 
-	PosLoss float32 `json:"posLoss,omitempty"`
+	PosLoss int64 `json:"posLoss,omitempty"`
 
-	PosMargin float32 `json:"posMargin,omitempty"`
+	PosMargin int64 `json:"posMargin,omitempty"`
 
-	PosMaint float32 `json:"posMaint,omitempty"`
+	PosMaint int64 `json:"posMaint,omitempty"`
 
-	PosAllowance float32 `json:"posAllowance,omitempty"`
+	PosAllowance int64 `json:"posAllowance,omitempty"`
 
-	TaxableMargin float32 `json:"taxableMargin,omitempty"`
+	TaxableMargin int64 `json:"taxableMargin,omitempty"`
 
-	InitMargin float32 `json:"initMargin,omitempty"`
+	InitMargin int64 `json:"initMargin,omitempty"`
 
-	MaintMargin float32 `json:"maintMargin,omitempty"`
+	MaintMargin int64 `json:"maintMargin,omitempty"`
 
-	SessionMargin float32 `json:"sessionMargin,omitempty"`
+	SessionMargin int64 `json:"sessionMargin,omitempty"`
 
-	TargetExcessMargin float32 `json:"targetExcessMargin,omitempty"`
+	TargetExcessMargin int64 `json:"targetExcessMargin,omitempty"`
 
-	VarMargin float32 `json:"varMargin,omitempty"`
+	VarMargin int64 `json:"varMargin,omitempty"`
 
-	RealisedGrossPnl float32 `json:"realisedGrossPnl,omitempty"`
+	RealisedGrossPnl int64 `json:"realisedGrossPnl,omitempty"`
 
-	RealisedTax float32 `json:"realisedTax,omitempty"`
+	RealisedTax int64 `json:"realisedTax,omitempty"`
 
-	RealisedPnl float32 `json:"realisedPnl,omitempty"`
+	RealisedPnl int64 `json:"realisedPnl,omitempty"`
 
-	UnrealisedGrossPnl float32 `json:"unrealisedGrossPnl,omitempty"`
+	UnrealisedGrossPnl int64 `json:"unrealisedGrossPnl,omitempty"`
 
-	LongBankrupt float32 `json:"longBankrupt,omitempty"`
+	LongBankrupt int64 `json:"longBankrupt,omitempty"`
 
-	ShortBankrupt float32 `json:"shortBankrupt,omitempty"`
+	ShortBankrupt int64 `json:"shortBankrupt,omitempty"`
 
-	TaxBase float32 `json:"taxBase,omitempty"`
+	TaxBase int64 `json:"taxBase,omitempty"`
 
 	IndicativeTaxRate float64 `json:"indicativeTaxRate,omitempty"`
 
-	IndicativeTax float32 `json:"indicativeTax,omitempty"`
+	IndicativeTax int64 `json:"indicativeTax,omitempty"`
 
-	UnrealisedTax float32 `json:"unrealisedTax,omitempty"`
+	UnrealisedTax int64 `json:"unrealisedTax,omitempty"`
 
-	UnrealisedPnl float32 `json:"unrealisedPnl,omitempty"`
+	UnrealisedPnl int64 `json:"unrealisedPnl,omitempty"`
 
 	UnrealisedPnlPcnt float64 `json:"unrealisedPnlPcnt,omitempty"`
 
@@ -196,5 +196,5 @@ type Position struct {
 
 	LastPrice float64 `json:"lastPrice,omitempty"`
 
-	LastValue float32 `json:"lastValue,omitempty"`
+	LastValue int64 `json:"lastValue,omitempty"`
 }
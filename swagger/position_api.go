@@ -138,7 +138,7 @@ func (a *PositionApiService) PositionGet(ctx context.Context, localVarOptionals
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -249,7 +249,7 @@ func (a *PositionApiService) PositionIsolateMargin(ctx context.Context, symbol s
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -265,7 +265,7 @@ When margin is isolated on a position, use this function to add or remove margin
 @param symbol Symbol of position to isolate.
 @param amount Amount to transfer, in Satoshis. May be negative.
 @return Position*/
-func (a *PositionApiService) PositionTransferIsolatedMargin(ctx context.Context, symbol string, amount float32) (Position, *http.Response, error) {
+func (a *PositionApiService) PositionTransferIsolatedMargin(ctx context.Context, symbol string, amount int64) (Position, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -353,7 +353,7 @@ func (a *PositionApiService) PositionTransferIsolatedMargin(ctx context.Context,
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -457,7 +457,7 @@ func (a *PositionApiService) PositionUpdateLeverage(ctx context.Context, symbol
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -473,7 +473,7 @@ Risk Limits limit the size of positions you can trade at various margin levels.
 @param symbol Symbol of position to isolate.
 @param riskLimit New Risk Limit, in Satoshis.
 @return Position*/
-func (a *PositionApiService) PositionUpdateRiskLimit(ctx context.Context, symbol string, riskLimit float32) (Position, *http.Response, error) {
+func (a *PositionApiService) PositionUpdateRiskLimit(ctx context.Context, symbol string, riskLimit int64) (Position, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -561,7 +561,7 @@ func (a *PositionApiService) PositionUpdateRiskLimit(ctx context.Context, symbol
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
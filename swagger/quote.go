@@ -20,11 +20,11 @@ type Quote struct {
 
 	Symbol string `json:"symbol"`
 
-	BidSize float32 `json:"bidSize,omitempty"`
+	BidSize int64 `json:"bidSize,omitempty"`
 
 	BidPrice float64 `json:"bidPrice,omitempty"`
 
 	AskPrice float64 `json:"askPrice,omitempty"`
 
-	AskSize float32 `json:"askSize,omitempty"`
+	AskSize int64 `json:"askSize,omitempty"`
 }
@@ -27,10 +27,11 @@ type SchemaApiService service
 
 /* SchemaApiService Get model schemata for data objects returned by this API.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "model" (string) Optional model filter. If omitted, will return all models.
 @return interface{}*/
-func (a *SchemaApiService) SchemaGet(localVarOptionals map[string]interface{}) (interface{}, *http.Response, error) {
+func (a *SchemaApiService) SchemaGet(ctx context.Context, localVarOptionals map[string]interface{}) (interface{}, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -76,7 +77,7 @@ func (a *SchemaApiService) SchemaGet(localVarOptionals map[string]interface{}) (
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -87,7 +88,7 @@ func (a *SchemaApiService) SchemaGet(localVarOptionals map[string]interface{}) (
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -99,8 +100,9 @@ func (a *SchemaApiService) SchemaGet(localVarOptionals map[string]interface{}) (
 
 /* SchemaApiService Returns help text &amp; subject list for websocket usage.
 
+* @param ctx context.Context for cancellation and tracing
 @return interface{}*/
-func (a *SchemaApiService) SchemaWebsocketHelp() (interface{}, *http.Response, error) {
+func (a *SchemaApiService) SchemaWebsocketHelp(ctx context.Context) (interface{}, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -139,7 +141,7 @@ func (a *SchemaApiService) SchemaWebsocketHelp() (interface{}, *http.Response, e
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -150,7 +152,7 @@ func (a *SchemaApiService) SchemaWebsocketHelp() (interface{}, *http.Response, e
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -24,9 +24,9 @@ type Settlement struct {
 
 	SettledPrice float64 `json:"settledPrice,omitempty"`
 
-	Bankrupt float32 `json:"bankrupt,omitempty"`
+	Bankrupt int64 `json:"bankrupt,omitempty"`
 
-	TaxBase float32 `json:"taxBase,omitempty"`
+	TaxBase int64 `json:"taxBase,omitempty"`
 
 	TaxRate float64 `json:"taxRate,omitempty"`
 }
@@ -16,11 +16,11 @@ type Stats struct {
 
 	Currency string `json:"currency,omitempty"`
 
-	Volume24h float32 `json:"volume24h,omitempty"`
+	Volume24h int64 `json:"volume24h,omitempty"`
 
-	Turnover24h float32 `json:"turnover24h,omitempty"`
+	Turnover24h int64 `json:"turnover24h,omitempty"`
 
-	OpenInterest float32 `json:"openInterest,omitempty"`
+	OpenInterest int64 `json:"openInterest,omitempty"`
 
-	OpenValue float32 `json:"openValue,omitempty"`
+	OpenValue int64 `json:"openValue,omitempty"`
 }
@@ -27,8 +27,9 @@ type StatsApiService service
 
 /* StatsApiService Get exchange-wide and per-series turnover and volume statistics.
 
+* @param ctx context.Context for cancellation and tracing
 @return []Stats*/
-func (a *StatsApiService) StatsGet() ([]Stats, *http.Response, error) {
+func (a *StatsApiService) StatsGet(ctx context.Context) ([]Stats, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -67,7 +68,7 @@ func (a *StatsApiService) StatsGet() ([]Stats, *http.Response, error) {
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -78,7 +79,7 @@ func (a *StatsApiService) StatsGet() ([]Stats, *http.Response, error) {
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -90,8 +91,9 @@ func (a *StatsApiService) StatsGet() ([]Stats, *http.Response, error) {
 
 /* StatsApiService Get historical exchange-wide and per-series turnover and volume statistics.
 
+* @param ctx context.Context for cancellation and tracing
 @return []StatsHistory*/
-func (a *StatsApiService) StatsHistory() ([]StatsHistory, *http.Response, error) {
+func (a *StatsApiService) StatsHistory(ctx context.Context) ([]StatsHistory, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -130,7 +132,7 @@ func (a *StatsApiService) StatsHistory() ([]StatsHistory, *http.Response, error)
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -141,7 +143,7 @@ func (a *StatsApiService) StatsHistory() ([]StatsHistory, *http.Response, error)
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -153,8 +155,9 @@ func (a *StatsApiService) StatsHistory() ([]StatsHistory, *http.Response, error)
 
 /* StatsApiService Get a summary of exchange statistics in USD.
 
+* @param ctx context.Context for cancellation and tracing
 @return []StatsUsd*/
-func (a *StatsApiService) StatsHistoryUSD() ([]StatsUsd, *http.Response, error) {
+func (a *StatsApiService) StatsHistoryUSD(ctx context.Context) ([]StatsUsd, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -193,7 +196,7 @@ func (a *StatsApiService) StatsHistoryUSD() ([]StatsUsd, *http.Response, error)
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -204,7 +207,7 @@ func (a *StatsApiService) StatsHistoryUSD() ([]StatsUsd, *http.Response, error)
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
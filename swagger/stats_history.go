@@ -21,7 +21,7 @@ type StatsHistory struct {
 
 	Currency string `json:"currency,omitempty"`
 
-	Volume float32 `json:"volume,omitempty"`
+	Volume int64 `json:"volume,omitempty"`
 
-	Turnover float32 `json:"turnover,omitempty"`
+	Turnover int64 `json:"turnover,omitempty"`
 }
@@ -15,11 +15,11 @@ type StatsUsd struct {
 
 	Currency string `json:"currency,omitempty"`
 
-	Turnover24h float32 `json:"turnover24h,omitempty"`
+	Turnover24h int64 `json:"turnover24h,omitempty"`
 
-	Turnover30d float32 `json:"turnover30d,omitempty"`
+	Turnover30d int64 `json:"turnover30d,omitempty"`
 
-	Turnover365d float32 `json:"turnover365d,omitempty"`
+	Turnover365d int64 `json:"turnover365d,omitempty"`
 
-	Turnover float32 `json:"turnover,omitempty"`
+	Turnover int64 `json:"turnover,omitempty"`
 }
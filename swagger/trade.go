@@ -22,7 +22,7 @@ type Trade struct {
 
 	Side string `json:"side,omitempty"`
 
-	Size float32 `json:"size,omitempty"`
+	Size int64 `json:"size,omitempty"`
 
 	Price float64 `json:"price,omitempty"`
 
@@ -30,7 +30,7 @@ type Trade struct {
 
 	TrdMatchID string `json:"trdMatchID,omitempty"`
 
-	GrossValue float32 `json:"grossValue,omitempty"`
+	GrossValue int64 `json:"grossValue,omitempty"`
 
 	HomeNotional float64 `json:"homeNotional,omitempty"`
 
@@ -27,15 +27,15 @@ type TradeBin struct {
 
 	Close float64 `json:"close,omitempty"`
 
-	Trades float32 `json:"trades,omitempty"`
+	Trades int64 `json:"trades,omitempty"`
 
-	Volume float32 `json:"volume,omitempty"`
+	Volume int64 `json:"volume,omitempty"`
 
 	Vwap float64 `json:"vwap,omitempty"`
 
-	LastSize float32 `json:"lastSize,omitempty"`
+	LastSize int64 `json:"lastSize,omitempty"`
 
-	Turnover float32 `json:"turnover,omitempty"`
+	Turnover int64 `json:"turnover,omitempty"`
 
 	HomeNotional float64 `json:"homeNotional,omitempty"`
 
@@ -17,15 +17,15 @@ import (
 type Transaction struct {
 	TransactID string `json:"transactID"`
 
-	Account float32 `json:"account,omitempty"`
+	Account int64 `json:"account,omitempty"`
 
 	Currency string `json:"currency,omitempty"`
 
 	TransactType string `json:"transactType,omitempty"`
 
-	Amount float32 `json:"amount,omitempty"`
+	Amount int64 `json:"amount,omitempty"`
 
-	Fee float32 `json:"fee,omitempty"`
+	Fee int64 `json:"fee,omitempty"`
 
 	TransactStatus string `json:"transactStatus,omitempty"`
 
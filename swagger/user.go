@@ -16,9 +16,9 @@ import (
 
 // Account Operations
 type User struct {
-	Id float32 `json:"id,omitempty"`
+	Id int64 `json:"id,omitempty"`
 
-	OwnerId float32 `json:"ownerId,omitempty"`
+	OwnerId int64 `json:"ownerId,omitempty"`
 
 	Firstname string `json:"firstname,omitempty"`
 
@@ -27,9 +27,10 @@ type UserApiService service
 
 /* UserApiService Cancel a withdrawal.
 
+* @param ctx context.Context for cancellation and tracing
 @param token
 @return Transaction*/
-func (a *UserApiService) UserCancelWithdrawal(token string) (Transaction, *http.Response, error) {
+func (a *UserApiService) UserCancelWithdrawal(ctx context.Context, token string) (Transaction, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -69,7 +70,7 @@ func (a *UserApiService) UserCancelWithdrawal(token string) (Transaction, *http.
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
 	localVarFormParams.Add("token", parameterToString(token, ""))
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -80,7 +81,7 @@ func (a *UserApiService) UserCancelWithdrawal(token string) (Transaction, *http.
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -93,10 +94,11 @@ func (a *UserApiService) UserCancelWithdrawal(token string) (Transaction, *http.
 /* UserApiService Check if a referral code is valid.
 If the code is valid, responds with the referral code&#39;s discount (e.g. &#x60;0.1&#x60; for 10%). Otherwise, will return a 404.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "referralCode" (string)
 @return float64*/
-func (a *UserApiService) UserCheckReferralCode(localVarOptionals map[string]interface{}) (float64, *http.Response, error) {
+func (a *UserApiService) UserCheckReferralCode(ctx context.Context, localVarOptionals map[string]interface{}) (float64, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -142,7 +144,7 @@ func (a *UserApiService) UserCheckReferralCode(localVarOptionals map[string]inte
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -153,7 +155,7 @@ func (a *UserApiService) UserCheckReferralCode(localVarOptionals map[string]inte
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -165,9 +167,10 @@ func (a *UserApiService) UserCheckReferralCode(localVarOptionals map[string]inte
 
 /* UserApiService Confirm your email address with a token.
 
+* @param ctx context.Context for cancellation and tracing
 @param token
 @return AccessToken*/
-func (a *UserApiService) UserConfirm(token string) (AccessToken, *http.Response, error) {
+func (a *UserApiService) UserConfirm(ctx context.Context, token string) (AccessToken, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -207,7 +210,7 @@ func (a *UserApiService) UserConfirm(token string) (AccessToken, *http.Response,
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
 	localVarFormParams.Add("token", parameterToString(token, ""))
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -218,7 +221,7 @@ func (a *UserApiService) UserConfirm(token string) (AccessToken, *http.Response,
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -328,7 +331,7 @@ func (a *UserApiService) UserConfirmEnableTFA(ctx context.Context, token string,
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -340,9 +343,10 @@ func (a *UserApiService) UserConfirmEnableTFA(ctx context.Context, token string,
 
 /* UserApiService Confirm a withdrawal.
 
+* @param ctx context.Context for cancellation and tracing
 @param token
 @return Transaction*/
-func (a *UserApiService) UserConfirmWithdrawal(token string) (Transaction, *http.Response, error) {
+func (a *UserApiService) UserConfirmWithdrawal(ctx context.Context, token string) (Transaction, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -382,7 +386,7 @@ func (a *UserApiService) UserConfirmWithdrawal(token string) (Transaction, *http
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
 	localVarFormParams.Add("token", parameterToString(token, ""))
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -393,7 +397,7 @@ func (a *UserApiService) UserConfirmWithdrawal(token string) (Transaction, *http
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -503,7 +507,7 @@ func (a *UserApiService) UserDisableTFA(ctx context.Context, token string, local
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -602,7 +606,7 @@ func (a *UserApiService) UserGet(ctx context.Context) (User, *http.Response, err
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -701,7 +705,7 @@ func (a *UserApiService) UserGetAffiliateStatus(ctx context.Context) (Affiliate,
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -800,7 +804,7 @@ func (a *UserApiService) UserGetCommission(ctx context.Context) ([]UserCommissio
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -908,7 +912,7 @@ func (a *UserApiService) UserGetDepositAddress(ctx context.Context, localVarOpti
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1016,7 +1020,7 @@ func (a *UserApiService) UserGetMargin(ctx context.Context, localVarOptionals ma
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1124,7 +1128,7 @@ func (a *UserApiService) UserGetWallet(ctx context.Context, localVarOptionals ma
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1232,7 +1236,7 @@ func (a *UserApiService) UserGetWalletHistory(ctx context.Context, localVarOptio
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1340,7 +1344,7 @@ func (a *UserApiService) UserGetWalletSummary(ctx context.Context, localVarOptio
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1352,8 +1356,9 @@ func (a *UserApiService) UserGetWalletSummary(ctx context.Context, localVarOptio
 
 /* UserApiService Log out of BitMEX.
 
+* @param ctx context.Context for cancellation and tracing
 @return */
-func (a *UserApiService) UserLogout() (*http.Response, error) {
+func (a *UserApiService) UserLogout(ctx context.Context) (*http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -1391,7 +1396,7 @@ func (a *UserApiService) UserLogout() (*http.Response, error) {
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -1402,7 +1407,7 @@ func (a *UserApiService) UserLogout() (*http.Response, error) {
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	return localVarHttpResponse, err
@@ -1497,7 +1502,7 @@ func (a *UserApiService) UserLogoutAll(ctx context.Context) (float64, *http.Resp
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1510,10 +1515,11 @@ func (a *UserApiService) UserLogoutAll(ctx context.Context) (float64, *http.Resp
 /* UserApiService Get the minimum withdrawal fee for a currency.
 This is changed based on network conditions to ensure timely withdrawals. During network congestion, this may be high. The fee is returned in the same currency.
 
+* @param ctx context.Context for cancellation and tracing
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "currency" (string)
 @return interface{}*/
-func (a *UserApiService) UserMinWithdrawalFee(localVarOptionals map[string]interface{}) (interface{}, *http.Response, error) {
+func (a *UserApiService) UserMinWithdrawalFee(ctx context.Context, localVarOptionals map[string]interface{}) (interface{}, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -1559,7 +1565,7 @@ func (a *UserApiService) UserMinWithdrawalFee(localVarOptionals map[string]inter
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
-	r, err := a.client.prepareRequest(nil, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return successPayload, nil, err
 	}
@@ -1570,7 +1576,7 @@ func (a *UserApiService) UserMinWithdrawalFee(localVarOptionals map[string]inter
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1679,7 +1685,7 @@ func (a *UserApiService) UserRequestEnableTFA(ctx context.Context, localVarOptio
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1693,13 +1699,13 @@ func (a *UserApiService) UserRequestEnableTFA(ctx context.Context, localVarOptio
 This will send a confirmation email to the email address on record, unless requested via an API Key with the &#x60;withdraw&#x60; permission.
 * @param ctx context.Context Authentication Context
 @param currency Currency you&#39;re withdrawing. Options: &#x60;XBt&#x60;
-@param amount Amount of withdrawal currency.
+@param amount Amount of withdrawal currency, in base units.
 @param address Destination Address.
 @param optional (nil or map[string]interface{}) with one or more of:
     @param "otpToken" (string) 2FA token. Required if 2FA is enabled on your account.
-    @param "fee" (float64) Network fee for Bitcoin withdrawals. If not specified, a default value will be calculated based on Bitcoin network conditions. You will have a chance to confirm this via email.
+    @param "fee" (int64) Network fee, in base units, for Bitcoin withdrawals. If not specified, a default value will be calculated based on Bitcoin network conditions. You will have a chance to confirm this via email.
 @return Transaction*/
-func (a *UserApiService) UserRequestWithdrawal(ctx context.Context, currency string, amount float32, address string, localVarOptionals map[string]interface{}) (Transaction, *http.Response, error) {
+func (a *UserApiService) UserRequestWithdrawal(ctx context.Context, currency string, amount int64, address string, localVarOptionals map[string]interface{}) (Transaction, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Post")
 		localVarPostBody   interface{}
@@ -1718,7 +1724,7 @@ func (a *UserApiService) UserRequestWithdrawal(ctx context.Context, currency str
 	if err := typeCheckParameter(localVarOptionals["otpToken"], "string", "otpToken"); err != nil {
 		return successPayload, nil, err
 	}
-	if err := typeCheckParameter(localVarOptionals["fee"], "float64", "fee"); err != nil {
+	if err := typeCheckParameter(localVarOptionals["fee"], "int64", "fee"); err != nil {
 		return successPayload, nil, err
 	}
 
@@ -1751,7 +1757,7 @@ func (a *UserApiService) UserRequestWithdrawal(ctx context.Context, currency str
 	localVarFormParams.Add("currency", parameterToString(currency, ""))
 	localVarFormParams.Add("amount", parameterToString(amount, ""))
 	localVarFormParams.Add("address", parameterToString(address, ""))
-	if localVarTempParam, localVarOk := localVarOptionals["fee"].(float64); localVarOk {
+	if localVarTempParam, localVarOk := localVarOptionals["fee"].(int64); localVarOk {
 		localVarFormParams.Add("fee", parameterToString(localVarTempParam, ""))
 	}
 	if ctx != nil {
@@ -1801,7 +1807,7 @@ func (a *UserApiService) UserRequestWithdrawal(ctx context.Context, currency str
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -1911,7 +1917,7 @@ func (a *UserApiService) UserSavePreferences(ctx context.Context, prefs string,
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
@@ -2068,7 +2074,7 @@ func (a *UserApiService) UserUpdate(ctx context.Context, localVarOptionals map[s
 	}
 	defer localVarHttpResponse.Body.Close()
 	if localVarHttpResponse.StatusCode >= 300 {
-		return successPayload, localVarHttpResponse, reportError(localVarHttpResponse.Status)
+		return successPayload, localVarHttpResponse, newAPIError(localVarHttpResponse)
 	}
 
 	if err = json.NewDecoder(localVarHttpResponse.Body).Decode(&successPayload); err != nil {
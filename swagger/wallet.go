@@ -15,47 +15,47 @@ import (
 )
 
 type Wallet struct {
-	Account float32 `json:"account"`
+	Account int64 `json:"account"`
 
 	Currency string `json:"currency"`
 
-	PrevDeposited float32 `json:"prevDeposited,omitempty"`
+	PrevDeposited int64 `json:"prevDeposited,omitempty"`
 
-	PrevWithdrawn float32 `json:"prevWithdrawn,omitempty"`
+	PrevWithdrawn int64 `json:"prevWithdrawn,omitempty"`
 
-	PrevTransferIn float32 `json:"prevTransferIn,omitempty"`
+	PrevTransferIn int64 `json:"prevTransferIn,omitempty"`
 
-	PrevTransferOut float32 `json:"prevTransferOut,omitempty"`
+	PrevTransferOut int64 `json:"prevTransferOut,omitempty"`
 
-	PrevAmount float32 `json:"prevAmount,omitempty"`
+	PrevAmount int64 `json:"prevAmount,omitempty"`
 
 	PrevTimestamp time.Time `json:"prevTimestamp,omitempty"`
 
-	DeltaDeposited float32 `json:"deltaDeposited,omitempty"`
+	DeltaDeposited int64 `json:"deltaDeposited,omitempty"`
 
-	DeltaWithdrawn float32 `json:"deltaWithdrawn,omitempty"`
+	DeltaWithdrawn int64 `json:"deltaWithdrawn,omitempty"`
 
-	DeltaTransferIn float32 `json:"deltaTransferIn,omitempty"`
+	DeltaTransferIn int64 `json:"deltaTransferIn,omitempty"`
 
-	DeltaTransferOut float32 `json:"deltaTransferOut,omitempty"`
+	DeltaTransferOut int64 `json:"deltaTransferOut,omitempty"`
 
-	DeltaAmount float32 `json:"deltaAmount,omitempty"`
+	DeltaAmount int64 `json:"deltaAmount,omitempty"`
 
-	Deposited float32 `json:"deposited,omitempty"`
+	Deposited int64 `json:"deposited,omitempty"`
 
-	Withdrawn float32 `json:"withdrawn,omitempty"`
+	Withdrawn int64 `json:"withdrawn,omitempty"`
 
-	TransferIn float32 `json:"transferIn,omitempty"`
+	TransferIn int64 `json:"transferIn,omitempty"`
 
-	TransferOut float32 `json:"transferOut,omitempty"`
+	TransferOut int64 `json:"transferOut,omitempty"`
 
-	Amount float32 `json:"amount,omitempty"`
+	Amount int64 `json:"amount,omitempty"`
 
-	PendingCredit float32 `json:"pendingCredit,omitempty"`
+	PendingCredit int64 `json:"pendingCredit,omitempty"`
 
-	PendingDebit float32 `json:"pendingDebit,omitempty"`
+	PendingDebit int64 `json:"pendingDebit,omitempty"`
 
-	ConfirmedDebit float32 `json:"confirmedDebit,omitempty"`
+	ConfirmedDebit int64 `json:"confirmedDebit,omitempty"`
 
 	Timestamp time.Time `json:"timestamp,omitempty"`
 
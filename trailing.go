@@ -0,0 +1,364 @@
+package bitmex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+// DefaultTrailingInterval is the minimum time between two amends of a
+// trailing stop when TrailingStopParams.MinInterval is not set.
+var DefaultTrailingInterval = time.Second
+
+// TrailingStopParams describes a stop order that follows the price
+type TrailingStopParams struct {
+	Symbol string
+	Side   Side // side of the stop order: SideSell protects a long position
+
+	// OrderID of an existing stop order to trail. When empty a reduce-only
+	// stop for OrderQty is placed at StopPx.
+	OrderID  string
+	OrderQty int64
+	StopPx   Price
+
+	Offset  Price    // distance between the reference price and stopPx
+	Trigger ExecInst // ExecMarkPrice (default), ExecLastPrice or ExecIndexPrice
+	// IndexSymbol is the instrument publishing the index price, e.g. ".BXBT".
+	// Required with ExecIndexPrice.
+	IndexSymbol string
+
+	// TickSize is the minimum stop move. It is learned from the instrument
+	// stream when zero.
+	TickSize Price
+	// MinInterval throttles amends, DefaultTrailingInterval when zero
+	MinInterval time.Duration
+
+	// Native places an exchange-side TrailingStopPeg order instead of trailing
+	// on the client. Only valid when OrderID is empty.
+	Native bool
+}
+
+// TrailingStop moves a stop order behind the mark, last or index price
+// taken from the instrument stream. The stop only ever moves in the
+// protective direction, by whole ticks, and at most once per MinInterval.
+type TrailingStop struct {
+	b      *BitMEX
+	params TrailingStopParams
+
+	m         sync.Mutex
+	order     swagger.Order
+	tickSize  Price
+	price     Price // latest reference price
+	lastAmend time.Time
+	err       error
+
+	remove []func()
+	wake   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// TrailStop starts trailing a stop order. The instrument stream of the
+// symbol (and of IndexSymbol) must be subscribed.
+func (b *BitMEX) TrailStop(ctx context.Context, params TrailingStopParams) (s *TrailingStop, err error) {
+	if params.Offset.Cmp(Price{}) <= 0 {
+		err = errors.New("trailing stop: offset must be positive")
+		return
+	}
+	if !params.Side.Valid() {
+		err = errors.New("trailing stop: side must be Buy or Sell")
+		return
+	}
+	switch params.Trigger {
+	case 0:
+		params.Trigger = ExecMarkPrice
+	case ExecMarkPrice, ExecLastPrice, ExecIndexPrice:
+	default:
+		err = fmt.Errorf("trailing stop: trigger %v must be MarkPrice, LastPrice or IndexPrice", params.Trigger)
+		return
+	}
+	if params.Trigger == ExecIndexPrice && params.IndexSymbol == "" {
+		err = errors.New("trailing stop: index symbol required")
+		return
+	}
+	if params.MinInterval == 0 {
+		params.MinInterval = DefaultTrailingInterval
+	}
+
+	var order swagger.Order
+	switch {
+	case params.Native:
+		if params.OrderID != "" {
+			err = errors.New("trailing stop: native trailing needs a new order")
+			return
+		}
+		order, err = b.PlaceTrailingStopPeg(ctx, params.Symbol, params.Side, params.OrderQty, params.Offset, params.Trigger, "", "trailing stop")
+		if err != nil {
+			return
+		}
+		s = &TrailingStop{b: b, params: params, order: order, done: make(chan struct{})}
+		close(s.done)
+		return
+	case params.OrderID != "":
+		order, err = b.GetOrderContext(ctx, params.OrderID, params.Symbol)
+	case params.StopPx.Cmp(Price{}) <= 0:
+		err = errors.New("trailing stop: stopPx required for a new stop")
+	default:
+		order, err = b.PlaceOrder2Context(ctx, params.Side, OrdTypeStop, params.StopPx, Price{}, params.OrderQty, -1,
+			"", ExecReduceOnly|params.Trigger, params.Symbol, "", "trailing stop")
+	}
+	if err != nil {
+		return
+	}
+
+	s = &TrailingStop{
+		b:        b,
+		params:   params,
+		order:    order,
+		tickSize: params.TickSize,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	s.remove = append(s.remove, b.WatchInstruments(s.onInstruments), b.WatchOrders(s.onOrders))
+	go s.run()
+	return
+}
+
+// PlaceTrailingStopPeg places an exchange-side trailing stop market order.
+// offset is the positive distance of the stop from the trigger price
+// (ExecMarkPrice, ExecLastPrice or ExecIndexPrice); it is negated for sell
+// stops as BitMEX expects. Like PlaceOrder2, the order is checked against
+// the instrument and clOrdID is generated when empty.
+func (b *BitMEX) PlaceTrailingStopPeg(ctx context.Context, symbol string, side Side, orderQty int64, offset Price, trigger ExecInst,
+	clOrdID string, text string) (order swagger.Order, err error) {
+	if !side.Valid() {
+		err = fmt.Errorf("invalid side %q", side)
+		return
+	}
+	execInst := ExecReduceOnly | trigger
+	if err = checkEnums(side, OrdTypeStop, "", execInst); err != nil {
+		return
+	}
+	if offset.Cmp(Price{}) <= 0 {
+		err = &InvalidOrderError{symbol, "pegOffsetValue", offset.Float64(), "must be positive"}
+		return
+	}
+	qty := float64(orderQty)
+	if err = b.checkOrder(symbol, string(side), nil, nil, qtyField{"orderQty", &qty}); err != nil {
+		return
+	}
+	if err = b.checkPegOffset(symbol, &offset); err != nil {
+		return
+	}
+	orderQty = int64(qty)
+	if err = b.checkRisk(&RiskOrder{Symbol: symbol, Side: side, OrdType: OrdTypeStop, OrderQty: orderQty,
+		ExecInst: execInst}); err != nil {
+		return
+	}
+	pegOffsetValue := offset
+	if side == SideSell {
+		pegOffsetValue = Price{}.Sub(offset)
+	}
+
+	if clOrdID == "" {
+		clOrdID = b.NewClOrdID()
+	}
+	params := map[string]interface{}{}
+	params["clOrdID"] = clOrdID
+	params["symbol"] = symbol
+	params["side"] = string(side)
+	params["ordType"] = string(OrdTypeStop)
+	params["orderQty"] = orderQty
+	params["pegPriceType"] = string(PegPriceTypeTrailingStopPeg)
+	params["pegOffsetValue"] = pegOffsetValue.String()
+	params["execInst"] = execInst.String()
+	if text == "" {
+		params["text"] = `trailing stop with bitmex api`
+	} else {
+		params["text"] = text
+	}
+
+	order, err = b.submitOrder(b.authContext(ctx), symbol, params)
+	return
+}
+
+// checkPegOffset validates, or rounds up in place, a peg offset against the
+// tick size of symbol. Rounding widens the offset, the passive direction.
+func (b *BitMEX) checkPegOffset(symbol string, offset *Price) error {
+	mode := b.validationMode()
+	if mode == ValidateOff {
+		return nil
+	}
+	spec, ok := b.instruments.Get(symbol)
+	if !ok {
+		return nil
+	}
+	tick := PriceOf(spec.TickSize)
+	if offset.OnTick(tick) {
+		return nil
+	}
+	if mode != ValidateRound {
+		return &InvalidOrderError{symbol, "pegOffsetValue", offset.Float64(), fmt.Sprintf("not a multiple of tick size %v", tick)}
+	}
+	*offset = offset.Round(tick, true)
+	return nil
+}
+
+// Order returns the latest state of the stop order
+func (s *TrailingStop) Order() swagger.Order {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.order
+}
+
+// Err returns the last amend error
+func (s *TrailingStop) Err() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.err
+}
+
+// Done is closed once the stop order is no longer working or Stop is called
+func (s *TrailingStop) Done() <-chan struct{} {
+	return s.done
+}
+
+// Stop stops trailing; the stop order is left in place
+func (s *TrailingStop) Stop() {
+	s.once.Do(func() {
+		for _, remove := range s.remove {
+			remove()
+		}
+		close(s.done)
+	})
+}
+
+func (s *TrailingStop) onInstruments(instruments []*swagger.Instrument, action string) {
+	updated := false
+	s.m.Lock()
+	for _, v := range instruments {
+		if v.Symbol == s.params.Symbol && v.TickSize > 0 && s.tickSize.IsZero() {
+			s.tickSize = PriceOf(v.TickSize)
+		}
+		if p := s.referencePrice(v); !p.IsZero() {
+			s.price = p
+			updated = true
+		}
+	}
+	s.m.Unlock()
+	if updated {
+		s.notify()
+	}
+}
+
+func (s *TrailingStop) referencePrice(v *swagger.Instrument) Price {
+	switch s.params.Trigger {
+	case ExecIndexPrice:
+		if v.Symbol == s.params.IndexSymbol {
+			return PriceOf(v.LastPrice)
+		}
+	case ExecLastPrice:
+		if v.Symbol == s.params.Symbol {
+			return PriceOf(v.LastPrice)
+		}
+	default:
+		if v.Symbol == s.params.Symbol {
+			return PriceOf(v.MarkPrice)
+		}
+	}
+	return Price{}
+}
+
+func (s *TrailingStop) onOrders(orders []*swagger.Order, action string) {
+	s.m.Lock()
+	for _, o := range orders {
+		if o.OrderID == s.order.OrderID && !o.Timestamp.Before(s.order.Timestamp) {
+			s.order = *o
+		}
+	}
+	working := isWorking(&s.order)
+	s.m.Unlock()
+	if !working {
+		s.Stop()
+	}
+}
+
+func (s *TrailingStop) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *TrailingStop) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+		if wait := s.trail(); wait > 0 {
+			timer.Reset(wait)
+		}
+	}
+}
+
+// target returns the stop price wanted for the reference price.
+// Caller holds s.m.
+func (s *TrailingStop) target() Price {
+	if s.params.Side == SideSell {
+		return s.price.Sub(s.params.Offset).Round(s.tickSize, false)
+	}
+	return s.price.Add(s.params.Offset).Round(s.tickSize, true)
+}
+
+// trail amends the stop when it is behind by at least one tick. It returns
+// how long to wait when the amend is held back by the throttle.
+func (s *TrailingStop) trail() time.Duration {
+	s.m.Lock()
+	if s.price.Cmp(Price{}) <= 0 || s.tickSize.Cmp(Price{}) <= 0 || !isWorking(&s.order) {
+		s.m.Unlock()
+		return 0
+	}
+	stopPx := s.target()
+	if current := PriceOf(s.order.StopPx); !current.IsZero() {
+		// move by whole ticks only
+		if s.params.Side == SideSell && stopPx.Cmp(current.AddTicks(s.tickSize, 1)) < 0 {
+			s.m.Unlock()
+			return 0
+		}
+		if s.params.Side == SideBuy && stopPx.Cmp(current.AddTicks(s.tickSize, -1)) > 0 {
+			s.m.Unlock()
+			return 0
+		}
+	}
+	if wait := s.params.MinInterval - time.Since(s.lastAmend); wait > 0 {
+		s.m.Unlock()
+		return wait
+	}
+	s.lastAmend = time.Now()
+	orderID := s.order.OrderID
+	s.m.Unlock()
+
+	order, err := s.b.AmendOrder2(orderID, "", "", 0, 0, 0, 0, Price{}, stopPx, Price{}, "trailing stop")
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	if err != nil {
+		s.err = err
+		log.Printf("trailing stop %v: %v", orderID, err)
+		return 0
+	}
+	if !order.Timestamp.Before(s.order.Timestamp) {
+		s.order = order
+	}
+	return 0
+}
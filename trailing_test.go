@@ -0,0 +1,97 @@
+package bitmex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sumorf/bitmex-api/swagger"
+)
+
+func TestBitMEX_TrailStop(t *testing.T) {
+	f, srv := newFakeOrderServer()
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+
+	s, err := b.TrailStop(nil, TrailingStopParams{
+		Symbol:      "XBTUSD",
+		Side:        SIDE_SELL,
+		OrderQty:    100,
+		StopPx:      MustPrice("4900"),
+		Offset:      MustPrice("100"),
+		TickSize:    MustPrice("0.5"),
+		MinInterval: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	orderID := s.Order().OrderID
+
+	mark := func(price float64) {
+		b.processInstrument(&Response{
+			Action: bitmexActionUpdateData,
+			Data:   []*swagger.Instrument{{Symbol: "XBTUSD", MarkPrice: price}},
+		})
+	}
+
+	mark(5000.2) // stop would stay at 4900
+	time.Sleep(20 * time.Millisecond)
+	if px := f.get(orderID).StopPx; px != 4900 {
+		t.Fatalf("stop moved to %v", px)
+	}
+
+	mark(5050.7)
+	waitFor(t, "first amend", func() bool { return f.get(orderID).StopPx == 4950.5 })
+
+	mark(5010) // price falls back: the stop must not follow
+	mark(5100) // throttled, then applied
+	waitFor(t, "throttled amend", func() bool { return f.get(orderID).StopPx == 5000 })
+}
+
+func TestBitMEX_PlaceTrailingStopPeg(t *testing.T) {
+	var form map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &form)
+		w.Write([]byte(`{"orderID":"o1","ordStatus":"New"}`))
+	}))
+	defer srv.Close()
+
+	b := New(HostTestnet, "key", "secret")
+	b.cfg.BasePath = srv.URL
+	b.instruments.update([]*swagger.Instrument{{Symbol: "XBTUSD", TickSize: 0.5, LotSize: 1}}, true)
+
+	invalid := []struct {
+		side   Side
+		offset Price
+	}{
+		{"", MustPrice("100")},
+		{SideSell, Price{}},
+		{SideSell, MustPrice("-100")},
+		{SideSell, MustPrice("100.2")},
+	}
+	for _, tt := range invalid {
+		if _, err := b.PlaceTrailingStopPeg(nil, "XBTUSD", tt.side, 100, tt.offset, ExecLastPrice, "", ""); err == nil {
+			t.Errorf("side %q offset %v accepted", tt.side, tt.offset)
+		}
+	}
+	if form != nil {
+		t.Fatalf("invalid order sent: %v", form)
+	}
+
+	if _, err := b.PlaceTrailingStopPeg(nil, "XBTUSD", SideSell, 100, MustPrice("100.5"), ExecLastPrice, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if form["clOrdID"] == "" || form["pegOffsetValue"] != "-100.5" || form["execInst"] != "ReduceOnly,LastPrice" {
+		t.Errorf("sent %v", form)
+	}
+	if n := len(b.InFlightOrders()); n != 0 {
+		t.Errorf("%v orders left in flight", n)
+	}
+}
@@ -1,13 +1,10 @@
 package bitmex
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"log"
-	"net/url"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -74,6 +71,12 @@ type Response struct {
 	Table     string      `json:"table,omitempty"`
 	Action    string      `json:"action,omitempty"`
 	Data      interface{} `json:"data,omitempty"`
+
+	// welcome message
+	Info      string `json:"info,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+
+	raw []byte // undecoded data
 }
 
 func decodeMessage(message []byte) (Response, error) {
@@ -87,6 +90,7 @@ func decodeMessage(message []byte) (Response, error) {
 
 	if ret.Get("table").Exists() {
 		raw := ret.Get("data").Raw
+		res.raw = []byte(raw)
 		switch res.Table {
 		case BitmexWSInstrument:
 			var instruments []*swagger.Instrument
@@ -110,6 +114,13 @@ func decodeMessage(message []byte) (Response, error) {
 			}
 			res.Data = quotes
 
+		case BitmexWSTrade:
+			var trades []*swagger.Trade
+			err = json.Unmarshal([]byte(raw), &trades)
+			if err != nil {
+				return res, err
+			}
+			res.Data = trades
 		case BitmexWSTradeBin1m, BitmexWSTradeBin5m, BitmexWSTradeBin1h, BitmexWSTradeBin1d:
 			var tradeBins []*swagger.TradeBin
 			err = json.Unmarshal([]byte(raw), &tradeBins)
@@ -173,26 +184,29 @@ func (b *BitMEX) sendWSMessage(msg interface{}) error {
 
 // sendAuth sends an authenticated subscription
 func (b *BitMEX) sendAuth() error {
-	if b.Key == "" || b.Secret == "" {
+	if b.Key == "" || !b.canSign() {
 		return nil
 	}
-	msg := b.getAuthMessage(b.Key, b.Secret)
+	msg, err := b.getAuthMessage(b.Key)
+	if err != nil {
+		return errors.Wrap(err, "signing authKey failed")
+	}
 	log.Println("sendAuth")
 	return b.sendWSMessage(msg)
 }
 
-func (b *BitMEX) getAuthMessage(key string, secret string) WSCmd {
-	nonce := time.Now().Unix() + 412
-	req := fmt.Sprintf("GET/realtime%d", nonce)
-	sig := hmac.New(sha256.New, []byte(secret))
-	sig.Write([]byte(req))
-	signature := hex.EncodeToString(sig.Sum(nil))
+func (b *BitMEX) getAuthMessage(key string) (WSCmd, error) {
+	nonce := b.wsExpires().Unix()
+	signature, err := b.signer().Sign("GET", "/realtime", "", strconv.FormatInt(nonce, 10), "")
+	if err != nil {
+		return WSCmd{}, err
+	}
 	var msgKey []interface{}
 	msgKey = append(msgKey, key)
 	msgKey = append(msgKey, nonce)
 	msgKey = append(msgKey, signature)
 
-	return WSCmd{"authKey", msgKey}
+	return WSCmd{"authKey", msgKey}, nil
 }
 
 func (b *BitMEX) Subscribe(subscribeTypes []SubscribeInfo) error {
@@ -224,10 +238,11 @@ func (b *BitMEX) subscribeHandler() error {
 
 // StartWS opens the websocket connection, and waits for message events
 func (b *BitMEX) StartWS() {
-	u := url.URL{Scheme: "wss", Host: b.host, Path: "/realtime"}
-	bitmexWSURL := u.String()
-	b.ws.SetProxyURL(b.proxyURL)
-	b.ws.Dial(bitmexWSURL, nil)
+	header := http.Header{}
+	for k, v := range b.cfg.DefaultHeader {
+		header.Set(k, v)
+	}
+	b.ws.Dial(b.wsURL, header)
 
 	go func() {
 		t := time.NewTicker(time.Second * 5)
@@ -262,12 +277,18 @@ func (b *BitMEX) StartWS() {
 					continue
 				}
 			}
+			at := time.Now()
 			resp, err := decodeMessage(message)
 			if err != nil {
 				log.Println("decode:", err)
 				continue
 			}
 
+			if resp.Info != "" {
+				b.onWelcome(resp.Timestamp, at)
+				continue
+			}
+
 			if resp.Success {
 				log.Println(string(message))
 				continue
@@ -282,6 +303,8 @@ func (b *BitMEX) StartWS() {
 				b.processOrderbook(&resp)
 			case BitmexWSQuote, BitmexWSQuoteBin1m, BitmexWSQuoteBin5m, BitmexWSQuoteBin1h, BitmexWSQuoteBin1d:
 				b.processQuote(&resp, resp.Table)
+			case BitmexWSTrade:
+				b.processTrade(&resp)
 			case BitmexWSTradeBin1m, BitmexWSTradeBin5m, BitmexWSTradeBin1h, BitmexWSTradeBin1d:
 				b.processTradeBin(&resp, resp.Table)
 			case BitmexWSExecution:
@@ -319,6 +342,8 @@ func (b *BitMEX) processInstrument(msg *Response) (err error) {
 		return errors.New("ws.go error - no instrument data")
 	}
 
+	b.instruments.update(instruments, msg.Action != bitmexActionUpdateData)
+	b.dispatch(BitmexWSInstrument, instruments, msg.Action)
 	b.emitter.Emit(BitmexWSInstrument, instruments, msg.Action)
 	return nil
 }
@@ -367,6 +392,17 @@ func (b *BitMEX) processQuote(msg *Response, name string) (err error) {
 	return nil
 }
 
+func (b *BitMEX) processTrade(msg *Response) (err error) {
+	trades, _ := msg.Data.([]*swagger.Trade)
+	if len(trades) < 1 {
+		return errors.New("ws.go error - no trade data")
+	}
+
+	b.dispatch(BitmexWSTrade, trades, msg.Action)
+	b.emitter.Emit(BitmexWSTrade, trades, msg.Action)
+	return nil
+}
+
 func (b *BitMEX) processTradeBin(msg *Response, name string) (err error) {
 	tradeBins, _ := msg.Data.([]*swagger.TradeBin)
 	if len(tradeBins) < 1 {
@@ -388,63 +424,82 @@ func (b *BitMEX) processExecution(msg *Response) (err error) {
 }
 
 func (b *BitMEX) processOrder(msg *Response) (err error) {
-	orders, _ := msg.Data.([]*swagger.Order)
+	// decoded again into Order so that a null price or a zero leavesQty is
+	// not mistaken for an absent field
+	var orders []*Order
+	if err = json.Unmarshal(msg.raw, &orders); err != nil {
+		return
+	}
 	if len(orders) < 1 {
 		return errors.New("ws.go error - no order data")
 	}
 
-	switch msg.Action {
-	case bitmexActionInitialData, bitmexActionInsertData:
-		for _, v := range orders {
+	b.orderLocalsMutex.Lock()
+	for _, v := range orders {
+		switch msg.Action {
+		case bitmexActionInitialData, bitmexActionInsertData:
 			b.orderLocals[v.OrderID] = v
-		}
-	case bitmexActionUpdateData:
-		for _, v := range orders {
+		case bitmexActionUpdateData:
 			if old, ok := b.orderLocals[v.OrderID]; ok {
-				if v.Price > 0.0 {
-					old.Price = v.Price
-				}
-				if v.OrderQty > 0 {
-					old.OrderQty = v.OrderQty
-				}
-				if v.OrdStatus != "" {
-					old.OrdStatus = v.OrdStatus
-				}
-				if v.AvgPx > 0 {
-					old.AvgPx = v.AvgPx
-				}
-				if v.CumQty > 0 {
-					old.CumQty = v.CumQty
-				}
-				if v.SimpleCumQty > 0 {
-					old.SimpleCumQty = v.SimpleCumQty
-				}
-				if v.Text != "" {
-					old.Text = v.Text
-				}
-				if v.Side != "" {
-					old.Side = v.Side
-				}
-				old.Timestamp = v.Timestamp // 2018-10-12T02:33:18.886Z
+				old.Merge(v)
+			} else {
+				b.orderLocals[v.OrderID] = v
 			}
 		}
-	case bitmexActionDeleteData:
+		if o, ok := b.orderLocals[v.OrderID]; ok && o.ClOrdID.V != "" {
+			b.orderClOrdIDs[o.ClOrdID.V] = o.OrderID
+		}
 	}
 
+	// handlers get snapshots, the event carries the typed orders
 	var result []*swagger.Order
+	var event []*Order
 	for _, v := range orders {
 		order, ok := b.orderLocals[v.OrderID]
 		if ok {
-			newOrder := *order
+			newOrder := order.Swagger()
 			result = append(result, &newOrder)
+			snapshot := *order
+			event = append(event, &snapshot)
 		}
 	}
+	b.orderLocalsMutex.Unlock()
 
-	//b.emitter.Emit(BitmexWSOrder, orders, msg.Action)
-	b.emitter.Emit(BitmexWSOrder, result, msg.Action)
+	for _, v := range result {
+		if v.ClOrdID != "" {
+			b.removeInFlight(v.ClOrdID)
+		}
+	}
+	b.updateOrderGroups(result)
+	b.dispatch(BitmexWSOrder, result, msg.Action)
+
+	b.emitter.Emit(BitmexWSOrder, event, msg.Action)
 	return nil
 }
 
+// openOrders returns the working orders of symbol seen on the order stream
+func (b *BitMEX) openOrders(symbol string) (orders []swagger.Order) {
+	b.orderLocalsMutex.RLock()
+	defer b.orderLocalsMutex.RUnlock()
+	for _, o := range b.orderLocals {
+		if o.Symbol.V == symbol && o.OrdStatus.IsOpen() {
+			orders = append(orders, o.Swagger())
+		}
+	}
+	return
+}
+
+// localOrder returns the latest state of an order seen on the order stream
+func (b *BitMEX) localOrder(orderID string) (order swagger.Order, ok bool) {
+	b.orderLocalsMutex.RLock()
+	defer b.orderLocalsMutex.RUnlock()
+	o, ok := b.orderLocals[orderID]
+	if ok {
+		order = o.Swagger()
+	}
+	return
+}
+
 func (b *BitMEX) processMargin(msg *Response) (err error) {
 	margins, _ := msg.Data.([]*swagger.Margin)
 	if len(margins) < 1 {
@@ -461,10 +516,50 @@ func (b *BitMEX) processPosition(msg *Response) (err error) {
 		return errors.New("ws.go error - no position data")
 	}
 
+	b.updatePositions(msg)
 	b.emitter.Emit(BitmexWSPosition, positions, msg.Action)
 	return nil
 }
 
+// updatePositions merges the position stream into positionLocals. The raw
+// data is decoded again into Position so that a zero currentQty is not
+// mistaken for an absent one.
+func (b *BitMEX) updatePositions(msg *Response) {
+	var positions []*Position
+	if err := json.Unmarshal(msg.raw, &positions); err != nil {
+		return
+	}
+
+	b.positionLocalsMutex.Lock()
+	defer b.positionLocalsMutex.Unlock()
+	for _, v := range positions {
+		switch msg.Action {
+		case bitmexActionInitialData, bitmexActionInsertData:
+			b.positionLocals[v.Symbol] = v
+		case bitmexActionUpdateData:
+			if old, ok := b.positionLocals[v.Symbol]; ok {
+				old.Merge(v)
+			} else {
+				b.positionLocals[v.Symbol] = v
+			}
+		case bitmexActionDeleteData:
+			delete(b.positionLocals, v.Symbol)
+		}
+	}
+}
+
+// localPosition returns the latest state of a position seen on the position
+// stream
+func (b *BitMEX) localPosition(symbol string) (position Position, ok bool) {
+	b.positionLocalsMutex.RLock()
+	defer b.positionLocalsMutex.RUnlock()
+	p, ok := b.positionLocals[symbol]
+	if ok {
+		position = *p
+	}
+	return
+}
+
 func (b *BitMEX) processWallet(msg *Response) (err error) {
 	wallets, _ := msg.Data.([]*swagger.Wallet)
 	if len(wallets) < 1 {